@@ -0,0 +1,98 @@
+package surrealdb
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// AccessAuth signs up or in through a record access method of SurrealDB 2.x, defined with
+// DEFINE ACCESS ... TYPE RECORD, rather than as a system user like Auth.
+type AccessAuth struct {
+	Namespace string
+	Database  string
+	// Access is the name of the access method.
+	Access string
+	// Vars are the variables of the SIGNUP or SIGNIN clause of the access method, such as email
+	// and pass. The values of the keys pass, password, secret, key, token and refresh are redacted
+	// from the request logs.
+	Vars map[string]interface{}
+}
+
+// Tokens are the tokens returned when signing up or in through a record access method. Refresh
+// is only set when the access method was defined WITH REFRESH.
+type Tokens struct {
+	Access  string `json:"token"`
+	Refresh string `json:"refresh,omitempty"`
+}
+
+// SignUpAccess signs up a record user through a record access method, and authenticates the
+// connection as this user. Failures match constants.ErrAuthentication or
+// constants.ErrAccessNotFound with errors.Is.
+//
+//	tokens, err := db.SignUpAccess(&surrealdb.AccessAuth{
+//		Namespace: "app", Database: "main", Access: "account",
+//		Vars: map[string]interface{}{"email": "john@example.com", "pass": "secret"},
+//	})
+func (db *DB) SignUpAccess(auth *AccessAuth) (*Tokens, error) {
+	return db.access("signup", auth, auth.Vars)
+}
+
+// SignInAccess signs in a record user through a record access method, and authenticates the
+// connection as this user. Failures match constants.ErrAuthentication or
+// constants.ErrAccessNotFound with errors.Is.
+func (db *DB) SignInAccess(auth *AccessAuth) (*Tokens, error) {
+	return db.access("signin", auth, auth.Vars)
+}
+
+// RefreshAccess exchanges the refresh token of a record access method defined WITH REFRESH for
+// new tokens, and authenticates the connection with them. The variables of auth are not sent. A
+// refresh token is used once: the returned Refresh replaces it. An expired refresh token matches
+// constants.ErrTokenExpired with errors.Is, after which the user has to sign in again.
+func (db *DB) RefreshAccess(auth *AccessAuth, refreshToken string) (*Tokens, error) {
+	return db.access("signin", auth, map[string]interface{}{"refresh": refreshToken})
+}
+
+func (db *DB) access(method string, auth *AccessAuth, vars map[string]interface{}) (*Tokens, error) {
+	if auth == nil || auth.Namespace == "" || auth.Database == "" || auth.Access == "" {
+		return nil, fmt.Errorf("the namespace, database and access method must be set")
+	}
+
+	credentials := make(map[string]interface{}, len(vars)+3)
+	for key, value := range vars {
+		credentials[key] = value
+	}
+	credentials["NS"] = auth.Namespace
+	credentials["DB"] = auth.Database
+	credentials["AC"] = auth.Access
+
+	var res connection.RPCResponse[cbor.RawMessage]
+	if err := db.send(&res, method, credentials); err != nil {
+		return nil, err
+	}
+	if res.Result == nil {
+		return nil, constants.InvalidResponse
+	}
+
+	// a token, or the token and refresh token of an access method defined WITH REFRESH
+	tokens := &Tokens{}
+	unmarshaler := db.con.GetUnmarshaler()
+	if err := unmarshaler.Unmarshal(*res.Result, &tokens.Access); err != nil {
+		if err := connection.DecodeResult(unmarshaler, method, *res.Result, tokens); err != nil {
+			return nil, err
+		}
+	}
+	if tokens.Access == "" {
+		return nil, constants.InvalidResponse
+	}
+
+	if err := db.con.Let(constants.AuthTokenKey, tokens.Access); err != nil {
+		return nil, err
+	}
+	db.setAuthentication(tokens.Access, "")
+
+	return tokens, nil
+}
@@ -0,0 +1,80 @@
+package surrealdb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+func TestAccessAuth(t *testing.T) {
+	var lock sync.Mutex
+	var requests []connection.RPCRequest
+	var authorizations []string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		requests = append(requests, req.RPCRequest)
+		authorizations = append(authorizations, req.HTTP.Header.Get("Authorization"))
+		lock.Unlock()
+
+		credentials, _ := req.Params[0].(map[interface{}]interface{})
+		switch {
+		case req.Method == "signup":
+			return "signup-token", nil
+		case credentials["AC"] == "missing":
+			return nil, &connection.RPCError{Code: -32000, Message: "The database access method 'missing' does not exist in database 'main'"}
+		case credentials["refresh"] == "expired":
+			return nil, &connection.RPCError{Code: -32000, Message: "There was a problem with the database: The refresh token has expired"}
+		case credentials["refresh"] != nil:
+			return map[string]interface{}{"token": "refreshed-token", "refresh": "refresh-2"}, nil
+		default:
+			return map[string]interface{}{"token": "signin-token", "refresh": "refresh-1"}, nil
+		}
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("app", "main"))
+	require.NoError(t, err)
+
+	auth := &surrealdb.AccessAuth{
+		Namespace: "app", Database: "main", Access: "account",
+		Vars: map[string]interface{}{"email": "john@example.com", "pass": "secret"},
+	}
+	tokens, err := db.SignUpAccess(auth)
+	require.NoError(t, err)
+	require.Equal(t, &surrealdb.Tokens{Access: "signup-token"}, tokens)
+
+	tokens, err = db.SignInAccess(auth)
+	require.NoError(t, err)
+	require.Equal(t, &surrealdb.Tokens{Access: "signin-token", Refresh: "refresh-1"}, tokens)
+
+	tokens, err = db.RefreshAccess(auth, tokens.Refresh)
+	require.NoError(t, err)
+	require.Equal(t, &surrealdb.Tokens{Access: "refreshed-token", Refresh: "refresh-2"}, tokens)
+	require.Equal(t, "refreshed-token", db.SessionState().Token)
+
+	_, err = db.RefreshAccess(auth, "expired")
+	require.ErrorIs(t, err, constants.ErrTokenExpired)
+
+	_, err = db.SignInAccess(&surrealdb.AccessAuth{Namespace: "app", Database: "main", Access: "missing"})
+	require.ErrorIs(t, err, constants.ErrAccessNotFound)
+
+	_, err = db.SignInAccess(&surrealdb.AccessAuth{Access: "account"})
+	require.Error(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, requests, 5)
+	require.Equal(t, map[interface{}]interface{}{
+		"NS": "app", "DB": "main", "AC": "account", "email": "john@example.com", "pass": "secret",
+	}, requests[0].Params[0])
+	require.Equal(t, map[interface{}]interface{}{
+		"NS": "app", "DB": "main", "AC": "account", "refresh": "refresh-1",
+	}, requests[2].Params[0])
+	require.Equal(t, "Bearer signin-token", authorizations[2])
+}
@@ -0,0 +1,81 @@
+package surrealdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+)
+
+// AuditEntry describes a request that may have changed data on the server.
+type AuditEntry struct {
+	Time   time.Time
+	Method string
+	// Target is the table or record the request applies to, or the function called by a run
+	// request. It is empty for queries.
+	Target string
+	// Query is the SurrealQL sent with a query request. Variables are not recorded.
+	Query string
+	// User is the user signed in with SignIn or SignUp, or for record users and tokens the
+	// identity the token was issued for, such as user:john. It is empty when the connection is not
+	// authenticated.
+	User string
+	// Access is the record access method the user signed in through, empty for system users.
+	Access string
+	// Err is the error returned by the request, if any.
+	Err error
+}
+
+// AuditHook receives an entry for every request that may change data: create, insert,
+// insert_relation, update, upsert, merge, patch, delete, relate, query and run. It is called
+// synchronously once the request returns, so it should hand slow work off to another goroutine.
+type AuditHook func(entry AuditEntry)
+
+// WithAuditHook calls hook for every request that may change data.
+func WithAuditHook(hook AuditHook) Option {
+	return func(c *config) error {
+		if hook == nil {
+			return fmt.Errorf("audit hook must not be nil")
+		}
+		c.auditHook = hook
+		return nil
+	}
+}
+
+var auditedMethods = map[string]bool{
+	"create":          true,
+	"insert":          true,
+	"insert_relation": true,
+	"update":          true,
+	"upsert":          true,
+	"merge":           true,
+	"patch":           true,
+	"delete":          true,
+	"relate":          true,
+	"query":           true,
+	"run":             true,
+}
+
+func (db *DB) audit(method string, params []interface{}, err error) {
+	if db.auditHook == nil || !auditedMethods[method] {
+		return
+	}
+
+	entry := AuditEntry{
+		Time:   time.Now(),
+		Method: method,
+		Target: requestTarget(method, params),
+		Err:    err,
+	}
+	entry.User, entry.Access = db.authenticatedUser()
+	if method == "query" {
+		entry.Query = connection.RequestStart{Method: method, Params: params}.Statement()
+	}
+
+	db.auditHook(entry)
+}
+
+// requestTarget returns the table or record a request applies to.
+func requestTarget(method string, params []interface{}) string {
+	return connection.RequestStart{Method: method, Params: params}.Target()
+}
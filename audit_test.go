@@ -0,0 +1,86 @@
+package surrealdb_test
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestAuditHook(t *testing.T) {
+	var lock sync.Mutex
+	var entries []surrealdb.AuditEntry
+	hook := surrealdb.WithAuditHook(func(entry surrealdb.AuditEntry) {
+		lock.Lock()
+		defer lock.Unlock()
+		entries = append(entries, entry)
+	})
+
+	db, err := surrealdb.Connect(context.Background(), getURL(), hook,
+		surrealdb.WithAuth(&surrealdb.Auth{Username: "root", Password: "root"}),
+		surrealdb.WithNamespace("test", "audit"),
+	)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = surrealdb.Select[[]testUser](db, models.Table("users"))
+	require.NoError(t, err)
+	_, err = surrealdb.Create[testUser](db, models.Table("users"), testUser{Username: "audited"})
+	require.NoError(t, err)
+	_, err = surrealdb.Query[interface{}](db, "DELETE users", map[string]interface{}{})
+	require.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, entries, 2, "reads are not audited")
+	require.Equal(t, "create", entries[0].Method)
+	require.Equal(t, "users", entries[0].Target)
+	require.Equal(t, "root", entries[0].User)
+	require.Equal(t, "query", entries[1].Method)
+	require.Equal(t, "DELETE users", entries[1].Query)
+}
+
+func TestAuditRecordUser(t *testing.T) {
+	// a token of a record user, whose claims are not verified by the client
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"NS":"app","DB":"main","AC":"account","ID":"user:john"}`))
+	token := "eyJhbGciOiJIUzUxMiJ9." + claims + ".signature"
+
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		if req.Method == "create" {
+			return map[string]interface{}{"id": models.NewRecordID("note", "a")}, nil
+		}
+		return token, nil
+	})
+
+	var entries []surrealdb.AuditEntry
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("app", "main"),
+		surrealdb.WithAuditHook(func(entry surrealdb.AuditEntry) {
+			entries = append(entries, entry)
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = db.SignInAccess(&surrealdb.AccessAuth{Namespace: "app", Database: "main", Access: "account"})
+	require.NoError(t, err)
+	_, err = surrealdb.Create[map[string]interface{}](db, models.Table("note"), map[string]interface{}{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Invalidate())
+	require.NoError(t, db.Authenticate(token))
+	_, err = surrealdb.Create[map[string]interface{}](db, models.Table("note"), map[string]interface{}{})
+	require.NoError(t, err)
+
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		require.Equal(t, "user:john", entry.User)
+		require.Equal(t, "account", entry.Access)
+	}
+	require.Equal(t, "account", db.SessionState().Access)
+}
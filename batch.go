@@ -0,0 +1,78 @@
+package surrealdb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+)
+
+// maxBatchInFlight bounds the number of requests of a batch waiting for their response at once
+const maxBatchInFlight = 128
+
+// BatchResult is the outcome of a request sent with SendBatch.
+type BatchResult struct {
+	// Result is the undecoded result of the request, see DecodeBatchResult.
+	Result cbor.RawMessage
+	Err    error
+}
+
+// SendBatch sends requests without waiting for the response of one before sending the next, and
+// returns their results in the order of the requests. Over WebSocket the requests are pipelined on
+// the connection, and responses are matched to requests by id. Only the Method and Params of the
+// requests are used.
+//
+// A failed request does not stop the others: its error is reported in its result. Once ctx is
+// done, the requests waiting for their response, and those not sent yet, fail with its error.
+func (db *DB) SendBatch(ctx context.Context, requests []connection.RPCRequest) []BatchResult {
+	db = db.WithContext(ctx)
+	results := make([]BatchResult, len(requests))
+	inFlight := make(chan struct{}, maxBatchInFlight)
+
+	var wg sync.WaitGroup
+	for i := range requests {
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-inFlight
+				wg.Done()
+			}()
+
+			var res connection.RPCResponse[cbor.RawMessage]
+			if err := db.send(&res, requests[i].Method, requests[i].Params...); err != nil {
+				results[i].Err = err
+				return
+			}
+			if res.Result != nil {
+				results[i].Result = *res.Result
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// DecodeBatchResult decodes the result of a request sent with SendBatch, or returns its error.
+func DecodeBatchResult[TResult any](db *DB, result BatchResult) (*TResult, error) {
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	if len(result.Result) == 0 {
+		return nil, nil
+	}
+
+	var decoded TResult
+	if err := connection.DecodeResult(db.con.GetUnmarshaler(), "batch", result.Result, &decoded); err != nil {
+		db.stats.record(err)
+		return nil, err
+	}
+	return &decoded, nil
+}
@@ -0,0 +1,56 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestSendBatch(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		if req.Method == "delete" {
+			return nil, &connection.RPCError{Code: -32000, Message: "Not enough permissions to perform this action"}
+		}
+		// answer out of order
+		time.Sleep(time.Duration(len(req.Params[0].(models.Table))) * time.Millisecond)
+		return map[string]interface{}{"table": req.Params[0]}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	results := db.SendBatch(context.Background(), []connection.RPCRequest{
+		{Method: "select", Params: []interface{}{models.Table("users_with_a_long_name")}},
+		{Method: "delete", Params: []interface{}{models.Table("users")}},
+		{Method: "select", Params: []interface{}{models.Table("notes")}},
+	})
+	require.Len(t, results, 3)
+
+	first, err := surrealdb.DecodeBatchResult[map[string]models.Table](db, results[0])
+	require.NoError(t, err)
+	require.Equal(t, models.Table("users_with_a_long_name"), (*first)["table"])
+
+	_, err = surrealdb.DecodeBatchResult[map[string]models.Table](db, results[1])
+	require.ErrorIs(t, err, constants.ErrPermissionDenied)
+
+	last, err := surrealdb.DecodeBatchResult[map[string]models.Table](db, results[2])
+	require.NoError(t, err)
+	require.Equal(t, models.Table("notes"), (*last)["table"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, result := range db.SendBatch(ctx, []connection.RPCRequest{
+		{Method: "select", Params: []interface{}{models.Table("users")}},
+		{Method: "select", Params: []interface{}{models.Table("notes")}},
+	}) {
+		require.ErrorIs(t, result.Err, context.Canceled)
+	}
+}
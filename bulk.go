@@ -0,0 +1,45 @@
+package surrealdb
+
+import "github.com/surrealdb/surrealdb.go/pkg/models"
+
+// defaultMaxBatchSize is the number of record ids SelectByIDs and DeleteByIDs send in one
+// statement, unless set with WithMaxBatchSize
+const defaultMaxBatchSize = 1000
+
+// SelectByIDs returns the records of ids, in the order of ids, skipping the records that do not
+// exist. The ids are sent in chunks of at most the batch size set with WithMaxBatchSize, one
+// statement per chunk, rather than one request per record.
+func SelectByIDs[TResult any](db *DB, ids []models.RecordID) ([]TResult, error) {
+	return byIDs[TResult](db, "SELECT * FROM $ids", ids)
+}
+
+// DeleteByIDs deletes the records of ids, in chunks like SelectByIDs. The chunks deleted before an
+// error stay deleted.
+func DeleteByIDs(db *DB, ids []models.RecordID) error {
+	_, err := byIDs[struct{}](db, "DELETE $ids", ids)
+	return err
+}
+
+// byIDs runs sql once per chunk of ids, bound to $ids, and returns the records of all chunks.
+func byIDs[TResult any](db *DB, sql string, ids []models.RecordID) ([]TResult, error) {
+	size := db.maxBatchSize
+	if size <= 0 {
+		size = defaultMaxBatchSize
+	}
+
+	records := make([]TResult, 0, len(ids))
+	for len(ids) > 0 {
+		n := size
+		if n > len(ids) {
+			n = len(ids)
+		}
+		chunk, err := querySingle[[]TResult](db, sql, map[string]interface{}{"ids": ids[:n]})
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *chunk...)
+		ids = ids[n:]
+	}
+
+	return records, nil
+}
@@ -0,0 +1,53 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestSelectByIDs(t *testing.T) {
+	var statements []string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		statements = append(statements, req.Params[0].(string))
+
+		// every record exists, and holds its id
+		var records []interface{}
+		for _, id := range req.Params[1].(map[interface{}]interface{})["ids"].([]interface{}) {
+			records = append(records, map[string]interface{}{"id": id})
+		}
+		return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": records}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithMaxBatchSize(2),
+	)
+	require.NoError(t, err)
+
+	ids := []models.RecordID{
+		models.NewRecordID("user", "a"),
+		models.NewRecordID("user", "b"),
+		models.NewRecordID("user", "c"),
+	}
+	users, err := surrealdb.SelectByIDs[testUser](db, ids)
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	require.Equal(t, ids[2], *users[2].ID)
+	require.Equal(t, []string{"SELECT * FROM $ids", "SELECT * FROM $ids"}, statements)
+
+	statements = nil
+	require.NoError(t, surrealdb.DeleteByIDs(db, ids))
+	require.Equal(t, []string{"DELETE $ids", "DELETE $ids"}, statements)
+
+	statements = nil
+	users, err = surrealdb.SelectByIDs[testUser](db, nil)
+	require.NoError(t, err)
+	require.Empty(t, users)
+	require.Empty(t, statements)
+}
@@ -0,0 +1,50 @@
+package surrealdb
+
+import (
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// clockSkewSamples is the number of round trips made by MeasureClockSkew
+const clockSkewSamples = 5
+
+// MeasureClockSkew measures how far the server clock is ahead of the client clock, negative when it
+// is behind. The server time is read with time::now() over several round trips, and the sample with
+// the shortest round trip is kept, assuming the server read its clock halfway through it.
+// The result is kept on the DB and returned by ClockSkew.
+func (db *DB) MeasureClockSkew() (time.Duration, error) {
+	var skew time.Duration
+	bestRoundTrip := time.Duration(-1)
+
+	for i := 0; i < clockSkewSamples; i++ {
+		sent := time.Now()
+		serverTime, err := querySingle[models.CustomDateTime](db, "RETURN time::now()", nil)
+		if err != nil {
+			return 0, err
+		}
+		received := time.Now()
+
+		roundTrip := received.Sub(sent)
+		if bestRoundTrip < 0 || roundTrip < bestRoundTrip {
+			bestRoundTrip = roundTrip
+			skew = serverTime.Sub(sent.Add(roundTrip / 2))
+		}
+	}
+
+	db.clockSkew.Store(int64(skew))
+	return skew, nil
+}
+
+// ClockSkew returns the offset last measured by MeasureClockSkew, or zero when it was never measured.
+func (db *DB) ClockSkew() time.Duration {
+	return time.Duration(db.clockSkew.Load())
+}
+
+// toServerTime converts a client time to the server clock, when skew compensation is enabled.
+func (db *DB) toServerTime(t time.Time) time.Time {
+	if !db.compensateClockSkew {
+		return t
+	}
+	return t.Add(db.ClockSkew())
+}
@@ -0,0 +1,15 @@
+package surrealdb_test
+
+import (
+	"time"
+)
+
+func (s *SurrealDBTestSuite) TestMeasureClockSkew() {
+	s.Zero(s.db.ClockSkew(), "the skew is zero until measured")
+
+	skew, err := s.db.MeasureClockSkew()
+	s.Require().NoError(err)
+	s.Equal(skew, s.db.ClockSkew())
+	// the test server runs next to the tests
+	s.Less(skew.Abs(), time.Minute)
+}
@@ -0,0 +1,51 @@
+package surrealdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ConnectCloud connects like Connect to a SurrealDB Cloud instance, authenticating with token,
+// an access token created for the instance, rather than with credentials. endpoint is the
+// hostname of the instance or its full URL. A hostname is connected to over wss, and URLs must
+// use wss or https, as Cloud instances only accept TLS connections.
+//
+//	db, err := surrealdb.ConnectCloud(ctx, "my-instance-06abc.aws-euw1.surreal.cloud", token,
+//		surrealdb.WithNamespace("app", "main"),
+//	)
+func ConnectCloud(ctx context.Context, endpoint, token string, opts ...Option) (*DB, error) {
+	connectionURL, err := CloudURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return Connect(ctx, connectionURL, append([]Option{WithToken(token)}, opts...)...)
+}
+
+// CloudURL returns the connection URL of a SurrealDB Cloud instance, given its hostname or URL.
+// It fails for URLs that do not use TLS.
+func CloudURL(endpoint string) (string, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return "", fmt.Errorf("cloud endpoint must not be empty")
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "wss://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid cloud endpoint %q: %w", endpoint, err)
+	}
+	if u.Scheme != "wss" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid cloud endpoint %q: SurrealDB Cloud only accepts wss and https connections", endpoint)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid cloud endpoint %q: missing hostname", endpoint)
+	}
+
+	u.Path = ""
+	return u.String(), nil
+}
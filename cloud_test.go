@@ -0,0 +1,52 @@
+package surrealdb_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudURL(t *testing.T) {
+	cases := map[string]string{
+		"my-instance.aws-euw1.surreal.cloud":                   "wss://my-instance.aws-euw1.surreal.cloud",
+		" wss://my-instance.aws-euw1.surreal.cloud/rpc ":       "wss://my-instance.aws-euw1.surreal.cloud",
+		"https://my-instance.aws-euw1.surreal.cloud?ns=a&db=b": "https://my-instance.aws-euw1.surreal.cloud?ns=a&db=b",
+	}
+	for endpoint, expected := range cases {
+		u, err := surrealdb.CloudURL(endpoint)
+		require.NoError(t, err, endpoint)
+		require.Equal(t, expected, u)
+	}
+
+	for _, endpoint := range []string{"", "ws://my-instance.aws-euw1.surreal.cloud", "http://localhost:8000", "wss://"} {
+		_, err := surrealdb.CloudURL(endpoint)
+		require.Error(t, err, endpoint)
+	}
+
+	_, err := surrealdb.ConnectCloud(context.Background(), "http://localhost:8000", "token")
+	require.ErrorContains(t, err, "only accepts wss and https")
+}
+
+// ExampleConnectCloud connects to a SurrealDB Cloud instance with an access token. It is compiled
+// but not run by go test, as it needs a Cloud instance.
+func ExampleConnectCloud() {
+	ctx := context.Background()
+	db, err := surrealdb.ConnectCloud(ctx, os.Getenv("SURREALDB_CLOUD_ENDPOINT"), os.Getenv("SURREALDB_TOKEN"),
+		surrealdb.WithNamespace("app", "main"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	version, err := db.Version()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(version.Version)
+}
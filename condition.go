@@ -0,0 +1,42 @@
+package surrealdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Condition is a WHERE condition along with the variables it binds, to be used in a query or as
+// the Where and Vars of a Pagination.
+type Condition struct {
+	SQL  string
+	Vars map[string]interface{}
+}
+
+// And returns the conjunction of conditions. It fails when two conditions bind the same variable,
+// such as two conditions of the same kind on the same field.
+func And(conditions ...Condition) (Condition, error) {
+	and := Condition{Vars: map[string]interface{}{}}
+	parts := make([]string, 0, len(conditions))
+	for _, c := range conditions {
+		for name, value := range c.Vars {
+			if _, bound := and.Vars[name]; bound {
+				return Condition{}, fmt.Errorf("variable $%s is bound by several conditions", name)
+			}
+			and.Vars[name] = value
+		}
+		parts = append(parts, "("+c.SQL+")")
+	}
+	and.SQL = strings.Join(parts, " AND ")
+	return and, nil
+}
+
+// fieldVarName returns field with the characters that are not allowed in a variable name replaced
+// by underscores, to name the variables bound by a condition on field.
+func fieldVarName(field string) string {
+	return strings.Map(func(c rune) rune {
+		if c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' {
+			return c
+		}
+		return '_'
+	}, field)
+}
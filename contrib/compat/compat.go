@@ -0,0 +1,276 @@
+// Package compat exposes the v0.2 API of the SDK on top of the current one, so that code written
+// against it can be upgraded incrementally. Every function is deprecated in favour of its
+// replacement in the surrealdb package, named in its documentation.
+//
+// Results are returned as interface{} values, as they were in v0.2, and are decoded with Unmarshal
+// or SmartUnmarshal.
+package compat
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Patch is a JSON Patch operation.
+//
+// Deprecated: use surrealdb.PatchData.
+type Patch = surrealdb.PatchData
+
+// DB is a client with the v0.2 API.
+//
+// Deprecated: use surrealdb.DB.
+type DB struct {
+	db *surrealdb.DB
+}
+
+// New connects to url. The /rpc path used by v0.2 urls is accepted and ignored.
+//
+// Deprecated: use surrealdb.New or surrealdb.Connect.
+func New(url string) (*DB, error) {
+	db, err := surrealdb.New(strings.TrimSuffix(url, "/rpc"))
+	if err != nil {
+		return nil, err
+	}
+	db.WarnDeprecated("compat.New")
+	return &DB{db: db}, nil
+}
+
+// Wrap returns a v0.2 client using an existing connection, for code mixing both APIs.
+func Wrap(db *surrealdb.DB) *DB {
+	return &DB{db: db}
+}
+
+// Unwrap returns the connection used by the client, to move code to the new API piece by piece.
+func (db *DB) Unwrap() *surrealdb.DB {
+	return db.db
+}
+
+// Close closes the connection.
+//
+// Deprecated: use surrealdb.DB.Close.
+func (db *DB) Close() {
+	db.db.WarnDeprecated("compat.DB.Close")
+	_ = db.db.Close()
+}
+
+// Use selects the namespace and database.
+//
+// Deprecated: use surrealdb.DB.Use.
+func (db *DB) Use(ns, database string) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Use")
+	return nil, db.db.Use(ns, database)
+}
+
+// Signin signs in with credentials given as a map or struct with the user, pass, NS, DB and SC keys,
+// and returns the token.
+//
+// Deprecated: use surrealdb.DB.SignIn.
+func (db *DB) Signin(vars interface{}) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Signin")
+	auth, err := toAuth(vars)
+	if err != nil {
+		return nil, err
+	}
+	return db.db.SignIn(auth)
+}
+
+// Signup signs up with credentials given as a map or struct, and returns the token.
+//
+// Deprecated: use surrealdb.DB.SignUp.
+func (db *DB) Signup(vars interface{}) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Signup")
+	auth, err := toAuth(vars)
+	if err != nil {
+		return nil, err
+	}
+	return db.db.SignUp(auth)
+}
+
+// Invalidate signs out.
+//
+// Deprecated: use surrealdb.DB.Invalidate.
+func (db *DB) Invalidate() (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Invalidate")
+	return nil, db.db.Invalidate()
+}
+
+// Authenticate authenticates with a token.
+//
+// Deprecated: use surrealdb.DB.Authenticate.
+func (db *DB) Authenticate(token string) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Authenticate")
+	return nil, db.db.Authenticate(token)
+}
+
+// Let defines a session variable.
+//
+// Deprecated: use surrealdb.DB.Let.
+func (db *DB) Let(key string, val interface{}) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Let")
+	return nil, db.db.Let(key, val)
+}
+
+// Info returns the record of the authenticated user.
+//
+// Deprecated: use surrealdb.DB.Info.
+func (db *DB) Info() (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Info")
+	return db.db.Info()
+}
+
+// Query runs SurrealQL statements and returns the result of each of them.
+//
+// Deprecated: use surrealdb.Query.
+func (db *DB) Query(sql string, vars interface{}) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Query")
+	queryVars, err := toVars(vars)
+	if err != nil {
+		return nil, err
+	}
+	res, err := surrealdb.Query[interface{}](db.db, sql, queryVars)
+	if err != nil {
+		return nil, err
+	}
+	return *res, nil
+}
+
+// Select returns the records of a table, or a record given as "table:id".
+//
+// Deprecated: use surrealdb.Select.
+func (db *DB) Select(what string) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Select")
+	if id, ok := parseWhat(what); ok {
+		return deref(surrealdb.Select[interface{}](db.db, id))
+	}
+	return deref(surrealdb.Select[interface{}](db.db, models.Table(what)))
+}
+
+// Create creates a record in a table, or a record given as "table:id".
+//
+// Deprecated: use surrealdb.Create.
+func (db *DB) Create(thing string, data interface{}) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Create")
+	if id, ok := parseWhat(thing); ok {
+		return deref(surrealdb.Create[interface{}](db.db, id, data))
+	}
+	return deref(surrealdb.Create[interface{}](db.db, models.Table(thing), data))
+}
+
+// Update replaces the content of the records of a table, or of a record given as "table:id".
+//
+// Deprecated: use surrealdb.Update.
+func (db *DB) Update(what string, data interface{}) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Update")
+	if id, ok := parseWhat(what); ok {
+		return deref(surrealdb.Update[interface{}](db.db, id, data))
+	}
+	return deref(surrealdb.Update[interface{}](db.db, models.Table(what), data))
+}
+
+// Change merges data into the records of a table, or into a record given as "table:id".
+//
+// Deprecated: use surrealdb.Merge.
+func (db *DB) Change(what string, data interface{}) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Change")
+	if id, ok := parseWhat(what); ok {
+		return deref(surrealdb.Merge[interface{}](db.db, id, data))
+	}
+	return deref(surrealdb.Merge[interface{}](db.db, models.Table(what), data))
+}
+
+// Modify applies JSON Patch operations to the records of a table, or to a record given as "table:id".
+//
+// Deprecated: use surrealdb.Patch.
+func (db *DB) Modify(what string, data []Patch) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Modify")
+	target := interface{}(models.Table(what))
+	if id, ok := parseWhat(what); ok {
+		target = id
+	}
+	return deref(surrealdb.Patch(db.db, target, data))
+}
+
+// Delete deletes the records of a table, or a record given as "table:id".
+//
+// Deprecated: use surrealdb.Delete.
+func (db *DB) Delete(what string) (interface{}, error) {
+	db.db.WarnDeprecated("compat.DB.Delete")
+	if id, ok := parseWhat(what); ok {
+		return deref(surrealdb.Delete[interface{}](db.db, id))
+	}
+	return deref(surrealdb.Delete[interface{}](db.db, models.Table(what)))
+}
+
+// Unmarshal decodes a result returned by the client into v.
+//
+// Deprecated: use the typed functions of the surrealdb package, which decode results directly.
+func Unmarshal(data, v interface{}) error {
+	encoded, err := models.CborMarshaler{}.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return models.CborUnmarshaler{}.Unmarshal(encoded, v)
+}
+
+// SmartUnmarshal decodes a result returned by the client, passing on the error it came with.
+//
+//	user, err := compat.SmartUnmarshal[User](db.Select("users:john"))
+//
+// Deprecated: use the typed functions of the surrealdb package, which decode results directly.
+func SmartUnmarshal[T any](respond interface{}, wrapperError error) (T, error) {
+	var result T
+	if wrapperError != nil {
+		return result, wrapperError
+	}
+	err := Unmarshal(respond, &result)
+	return result, err
+}
+
+// parseWhat returns the record id held by what, when it is not a table name.
+func parseWhat(what string) (models.RecordID, bool) {
+	if !strings.Contains(what, ":") {
+		return models.RecordID{}, false
+	}
+	return *models.ParseRecordID(what), true
+}
+
+func deref[T any](res *T, err error) (interface{}, error) {
+	if err != nil || res == nil {
+		return nil, err
+	}
+	return *res, nil
+}
+
+// toAuth converts v0.2 credentials, which share their keys with surrealdb.Auth.
+func toAuth(vars interface{}) (*surrealdb.Auth, error) {
+	if auth, ok := vars.(*surrealdb.Auth); ok {
+		return auth, nil
+	}
+	encoded, err := json.Marshal(vars)
+	if err != nil {
+		return nil, err
+	}
+	var auth surrealdb.Auth
+	if err := json.Unmarshal(encoded, &auth); err != nil {
+		return nil, err
+	}
+	return &auth, nil
+}
+
+// toVars converts query variables given as a map or struct.
+func toVars(vars interface{}) (map[string]interface{}, error) {
+	switch v := vars.(type) {
+	case nil:
+		return map[string]interface{}{}, nil
+	case map[string]interface{}:
+		return v, nil
+	}
+	var queryVars map[string]interface{}
+	if err := Unmarshal(vars, &queryVars); err != nil {
+		return nil, err
+	}
+	return queryVars, nil
+}
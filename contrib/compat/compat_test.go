@@ -0,0 +1,60 @@
+package compat
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+type user struct {
+	ID       *models.RecordID `json:"id,omitempty"`
+	Username string           `json:"username"`
+}
+
+func TestSmartUnmarshal(t *testing.T) {
+	result := map[string]interface{}{
+		"id":       models.NewRecordID("users", "john"),
+		"username": "john",
+	}
+
+	u, err := SmartUnmarshal[user](result, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "john", u.Username)
+	assert.Equal(t, "users", u.ID.Table)
+
+	failure := errors.New("failure")
+	_, err = SmartUnmarshal[user](nil, failure)
+	assert.ErrorIs(t, err, failure)
+}
+
+func TestParseWhat(t *testing.T) {
+	_, ok := parseWhat("users")
+	assert.False(t, ok)
+
+	id, ok := parseWhat("users:john")
+	assert.True(t, ok)
+	assert.Equal(t, models.NewRecordID("users", "john"), id)
+}
+
+func TestToAuth(t *testing.T) {
+	auth, err := toAuth(map[string]interface{}{"user": "root", "pass": "secret", "NS": "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, "root", auth.Username)
+	assert.Equal(t, "secret", auth.Password)
+	assert.Equal(t, "test", auth.Namespace)
+}
+
+func TestToVars(t *testing.T) {
+	vars, err := toVars(struct {
+		Name string `json:"name"`
+	}{Name: "john"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "john"}, vars)
+
+	vars, err = toVars(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, vars)
+}
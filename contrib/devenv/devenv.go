@@ -0,0 +1,181 @@
+// Package devenv starts SurrealDB servers in Docker containers from Go, for tests and local
+// development. It runs the docker command, which must be installed.
+//
+//	func TestMain(m *testing.M) {
+//		server, err := devenv.Start(context.Background(), devenv.Options{Version: "v2.1.0"})
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		code := m.Run()
+//		_ = server.Stop()
+//		os.Exit(code)
+//	}
+package devenv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+)
+
+const (
+	DefaultImage        = "surrealdb/surrealdb"
+	DefaultVersion      = "latest"
+	DefaultUsername     = "root"
+	DefaultPassword     = "root"
+	DefaultStorage      = "memory"
+	DefaultStartTimeout = 30 * time.Second
+)
+
+// Options configures the server started by Start. The zero value of every field selects its
+// default.
+type Options struct {
+	Image string
+	// Version is the tag of the image, such as v2.1.0.
+	Version string
+	// Username and Password are the credentials of the root user.
+	Username string
+	Password string
+	// Unauthenticated lets every request through without signing in.
+	Unauthenticated bool
+	// Capabilities are the capabilities allowed to the server, such as all, scripting or net,
+	// passed as --allow-<capability> flags.
+	Capabilities []string
+	// Storage is the storage of the server, such as memory or rocksdb:/data/db.
+	Storage string
+	// StartTimeout bounds the wait for the server to be ready.
+	StartTimeout time.Duration
+}
+
+// Server is a SurrealDB server running in a Docker container.
+type Server struct {
+	// URL is the websocket endpoint of the server, and HTTPURL its http endpoint.
+	URL      string
+	HTTPURL  string
+	Username string
+	Password string
+
+	container string
+}
+
+// runDocker runs the docker command with args and returns its output, replaced by tests.
+var runDocker = func(ctx context.Context, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Start starts a server in a new container, and returns once the server is ready. The container
+// is removed once stopped. Its port is published on a random port of the loopback interface.
+func Start(ctx context.Context, opts Options) (*Server, error) {
+	if opts.Image == "" {
+		opts.Image = DefaultImage
+	}
+	if opts.Version == "" {
+		opts.Version = DefaultVersion
+	}
+	if opts.Username == "" {
+		opts.Username = DefaultUsername
+	}
+	if opts.Password == "" {
+		opts.Password = DefaultPassword
+	}
+	if opts.Storage == "" {
+		opts.Storage = DefaultStorage
+	}
+	if opts.StartTimeout <= 0 {
+		opts.StartTimeout = DefaultStartTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.StartTimeout)
+	defer cancel()
+
+	args := []string{
+		"run", "--detach", "--rm", "--publish", "127.0.0.1::8000",
+		opts.Image + ":" + opts.Version,
+		"start", "--bind", "0.0.0.0:8000", "--user", opts.Username, "--pass", opts.Password,
+	}
+	if opts.Unauthenticated {
+		args = append(args, "--unauthenticated")
+	}
+	for _, capability := range opts.Capabilities {
+		args = append(args, "--allow-"+capability)
+	}
+	args = append(args, opts.Storage)
+
+	container, err := runDocker(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{Username: opts.Username, Password: opts.Password, container: container}
+
+	if err := server.wait(ctx); err != nil {
+		_ = server.Stop()
+		return nil, err
+	}
+	return server, nil
+}
+
+// wait waits for the server to listen and be ready.
+func (s *Server) wait(ctx context.Context) error {
+	address, err := runDocker(ctx, "port", s.container, "8000/tcp")
+	if err != nil {
+		return err
+	}
+	// docker lists a line per address the port is published on
+	address = strings.SplitN(address, "\n", 2)[0]
+	s.URL = "ws://" + address
+	s.HTTPURL = "http://" + address
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.HTTPURL+"/health", http.NoBody)
+		if err != nil {
+			return err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("server not ready: %w", ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// Connect connects to the server as the root user, with opts applied after the credentials.
+func (s *Server) Connect(ctx context.Context, opts ...surrealdb.Option) (*surrealdb.DB, error) {
+	auth := surrealdb.WithAuth(&surrealdb.Auth{Username: s.Username, Password: s.Password})
+	return surrealdb.Connect(ctx, s.URL, append([]surrealdb.Option{auth}, opts...)...)
+}
+
+// Env returns the environment variables read by surrealdb.FromEnv to connect to the server.
+func (s *Server) Env() map[string]string {
+	return map[string]string{
+		surrealdb.EnvURL:  s.URL,
+		surrealdb.EnvUser: s.Username,
+		surrealdb.EnvPass: s.Password,
+	}
+}
+
+// Stop stops the server and removes its container.
+func (s *Server) Stop() error {
+	_, err := runDocker(context.Background(), "rm", "--force", s.container)
+	return err
+}
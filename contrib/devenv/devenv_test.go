@@ -0,0 +1,51 @@
+package devenv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStart(t *testing.T) {
+	var ready bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready {
+			// still starting on the first health check
+			ready = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var commands []string
+	runDocker = func(ctx context.Context, args ...string) (string, error) {
+		commands = append(commands, strings.Join(args, " "))
+		switch args[0] {
+		case "run":
+			return "f00d", nil
+		case "port":
+			return strings.TrimPrefix(server.URL, "http://") + "\n[::1]:1", nil
+		default:
+			return "", nil
+		}
+	}
+
+	s, err := Start(context.Background(), Options{Version: "v2.1.0", Capabilities: []string{"all"}, StartTimeout: time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, "ws://"+strings.TrimPrefix(server.URL, "http://"), s.URL)
+	assert.Equal(t, server.URL, s.HTTPURL)
+	assert.Equal(t, "root", s.Env()["SURREALDB_USER"])
+	require.NoError(t, s.Stop())
+
+	assert.Equal(t, []string{
+		"run --detach --rm --publish 127.0.0.1::8000 surrealdb/surrealdb:v2.1.0 start --bind 0.0.0.0:8000 --user root --pass root --allow-all memory",
+		"port f00d 8000/tcp",
+		"rm --force f00d",
+	}, commands)
+}
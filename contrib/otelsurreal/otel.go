@@ -0,0 +1,152 @@
+// Package otelsurreal instruments the connection of a client with OpenTelemetry: a client span
+// per request, tagged like the spans of contrib/surrealtrace, and histograms of the request
+// latency, of the request and response sizes, and of the lag of live query notifications.
+//
+//	hooks, err := otelsurreal.Hooks()
+//	if err != nil {
+//		return err
+//	}
+//	db, err := surrealdb.Connect(ctx, "ws://localhost:8000", surrealdb.WithHooks(hooks))
+//
+// It is a module of its own, so that the SDK does not depend on OpenTelemetry.
+package otelsurreal
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/surrealdb/surrealdb.go/contrib/surrealtrace"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+)
+
+// InstrumentationName is the name of the tracer and meter of the hooks.
+const InstrumentationName = "github.com/surrealdb/surrealdb.go/contrib/otelsurreal"
+
+// The names of the metrics recorded by the hooks.
+const (
+	MetricRequestDuration = "surrealdb.client.request.duration"
+	MetricRequestSize     = "surrealdb.client.request.size"
+	MetricResponseSize    = "surrealdb.client.response.size"
+	MetricNotificationLag = "surrealdb.client.notification.lag"
+)
+
+const (
+	attributeRequestSize  = "surrealdb.request.size"
+	attributeResponseSize = "surrealdb.response.size"
+	attributeAction       = "surrealdb.notification.action"
+	attributeError        = "error"
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the hooks returned by Hooks.
+type Option func(c *config)
+
+// WithTracerProvider sets the provider of the tracer, the global one by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the provider of the meter, the global one by default.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+type instruments struct {
+	tracer          trace.Tracer
+	duration        metric.Float64Histogram
+	requestSize     metric.Int64Histogram
+	responseSize    metric.Int64Histogram
+	notificationLag metric.Float64Histogram
+}
+
+// Hooks returns the hooks instrumenting a connection, to be given to surrealdb.WithHooks. The
+// hooks are called for every message sent on the connection, so a request retried by the client
+// is traced once per attempt.
+func Hooks(opts ...Option) (connection.Hooks, error) {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	meter := c.meterProvider.Meter(InstrumentationName)
+	i := &instruments{tracer: c.tracerProvider.Tracer(InstrumentationName)}
+	var err error
+	if i.duration, err = meter.Float64Histogram(MetricRequestDuration,
+		metric.WithUnit("s"), metric.WithDescription("Duration of the requests sent to SurrealDB")); err != nil {
+		return connection.Hooks{}, err
+	}
+	if i.requestSize, err = meter.Int64Histogram(MetricRequestSize,
+		metric.WithUnit("By"), metric.WithDescription("Size of the encoded requests")); err != nil {
+		return connection.Hooks{}, err
+	}
+	if i.responseSize, err = meter.Int64Histogram(MetricResponseSize,
+		metric.WithUnit("By"), metric.WithDescription("Size of the encoded responses")); err != nil {
+		return connection.Hooks{}, err
+	}
+	if i.notificationLag, err = meter.Float64Histogram(MetricNotificationLag,
+		metric.WithUnit("s"), metric.WithDescription("Time between the reception and the delivery of live query notifications")); err != nil {
+		return connection.Hooks{}, err
+	}
+
+	return connection.Hooks{
+		OnRequestStart: i.requestStart,
+		OnRequestEnd:   i.requestEnd,
+		OnNotification: i.notification,
+	}, nil
+}
+
+func (i *instruments) requestStart(ctx context.Context, req connection.RequestStart) context.Context {
+	tags := surrealtrace.Tags(req)
+	attributes := make([]attribute.KeyValue, 0, len(tags)+1)
+	for name, value := range tags {
+		attributes = append(attributes, attribute.String(name, value))
+	}
+	attributes = append(attributes, attribute.Int(attributeRequestSize, req.RequestSize))
+
+	ctx, _ = i.tracer.Start(ctx, "surrealdb."+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+	return ctx
+}
+
+func (i *instruments) requestEnd(ctx context.Context, req connection.RequestEnd) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int(attributeResponseSize, req.ResponseSize))
+	if req.Err != nil {
+		span.RecordError(req.Err)
+		span.SetStatus(codes.Error, req.Err.Error())
+	}
+	span.End()
+
+	attributes := metric.WithAttributes(
+		attribute.String(surrealtrace.TagSystem, "surrealdb"),
+		attribute.String(surrealtrace.TagOperation, req.Method),
+		attribute.Bool(attributeError, req.Err != nil),
+	)
+	i.duration.Record(ctx, req.Duration.Seconds(), attributes)
+	i.requestSize.Record(ctx, int64(req.RequestSize), attributes)
+	if req.ResponseSize > 0 {
+		i.responseSize.Record(ctx, int64(req.ResponseSize), attributes)
+	}
+}
+
+func (i *instruments) notification(n connection.NotificationDelivery) {
+	i.notificationLag.Record(context.Background(), n.Lag.Seconds(), metric.WithAttributes(
+		attribute.String(attributeAction, string(n.Action)),
+	))
+}
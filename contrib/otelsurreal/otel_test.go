@@ -0,0 +1,74 @@
+package otelsurreal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/contrib/surrealtrace"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestHooks(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		if req.Method == "delete" {
+			return nil, &connection.RPCError{Code: -32000, Message: "Not enough permissions to perform this action"}
+		}
+		return []interface{}{}, nil
+	})
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	hooks, err := Hooks(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	require.NoError(t, err)
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithHooks(hooks),
+	)
+	require.NoError(t, err)
+
+	_, err = surrealdb.Select[[]interface{}](db, models.NewRecordID("user", "john"))
+	require.NoError(t, err)
+	_, err = surrealdb.Delete[interface{}](db, models.Table("user"))
+	require.Error(t, err)
+
+	ended := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans.Ended() {
+		ended[span.Name()] = span
+	}
+
+	require.Contains(t, ended, "surrealdb.select")
+	selectSpan := ended["surrealdb.select"]
+	assert.Contains(t, selectSpan.Attributes(), attribute.String(surrealtrace.TagTable, "user"))
+	assert.Contains(t, selectSpan.Attributes(), attribute.String(surrealtrace.TagTarget, "user:john"))
+	assert.Equal(t, codes.Unset, selectSpan.Status().Code)
+
+	require.Contains(t, ended, "surrealdb.delete")
+	assert.Equal(t, codes.Error, ended["surrealdb.delete"].Status().Code)
+
+	var collected metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &collected))
+	require.Len(t, collected.ScopeMetrics, 1)
+	names := map[string]bool{}
+	for _, m := range collected.ScopeMetrics[0].Metrics {
+		names[m.Name] = true
+	}
+	assert.True(t, names[MetricRequestDuration])
+	assert.True(t, names[MetricRequestSize])
+	assert.True(t, names[MetricResponseSize])
+}
@@ -0,0 +1,54 @@
+// Package surrealdatadog traces the requests of a client as Datadog spans, tagged like the spans
+// of contrib/surrealtrace, with the statement of queries as the resource name.
+//
+//	tracer.Start()
+//	defer tracer.Stop()
+//	db, err := surrealdb.Connect(ctx, "ws://localhost:8000", surrealdb.WithHooks(surrealdatadog.Hooks("surrealdb")))
+//
+// It is a module of its own, so that the SDK does not depend on the Datadog tracer.
+package surrealdatadog
+
+import (
+	"context"
+
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+
+	"github.com/surrealdb/surrealdb.go/contrib/surrealtrace"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+)
+
+// DefaultServiceName is the service of the spans when none is given.
+const DefaultServiceName = "surrealdb"
+
+// Hooks returns the hooks tracing every request as a span of service, to be given to
+// surrealdb.WithHooks.
+func Hooks(service string) connection.Hooks {
+	return surrealtrace.Hooks(Start(service))
+}
+
+// Start returns the function starting the Datadog spans of service, to be given to surrealtrace.
+func Start(service string) surrealtrace.StartFunc {
+	if service == "" {
+		service = DefaultServiceName
+	}
+
+	return func(ctx context.Context, operation string, tags map[string]string) (context.Context, func(error)) {
+		resource := tags[surrealtrace.TagStatement]
+		if resource == "" {
+			resource = operation
+		}
+
+		span, ctx := tracer.StartSpanFromContext(ctx, operation,
+			tracer.ServiceName(service),
+			tracer.SpanType(ext.SpanTypeSQL),
+			tracer.ResourceName(resource),
+		)
+		for name, value := range tags {
+			span.SetTag(name, value)
+		}
+		return ctx, func(err error) {
+			span.Finish(tracer.WithError(err))
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package surrealdatadog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/mocktracer"
+
+	"github.com/surrealdb/surrealdb.go/contrib/surrealtrace"
+)
+
+func TestStart(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	start := Start("")
+	_, end := start(context.Background(), "surrealdb.query", map[string]string{
+		surrealtrace.TagOperation: "query",
+		surrealtrace.TagStatement: "SELECT * FROM user",
+	})
+	end(nil)
+	_, end = start(context.Background(), "surrealdb.delete", map[string]string{
+		surrealtrace.TagOperation: "delete",
+		surrealtrace.TagTable:     "user",
+	})
+	end(errors.New("not enough permissions"))
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "surrealdb.query", spans[0].OperationName())
+	assert.Equal(t, DefaultServiceName, spans[0].Tag(ext.ServiceName))
+	assert.Equal(t, "SELECT * FROM user", spans[0].Tag(ext.ResourceName))
+	assert.Equal(t, "query", spans[0].Tag(surrealtrace.TagOperation))
+	assert.Nil(t, spans[0].Tag(ext.Error))
+
+	assert.Equal(t, "surrealdb.delete", spans[1].Tag(ext.ResourceName))
+	assert.Equal(t, "user", spans[1].Tag(surrealtrace.TagTable))
+	assert.NotNil(t, spans[1].Tag(ext.Error))
+}
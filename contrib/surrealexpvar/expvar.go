@@ -0,0 +1,59 @@
+// Package surrealexpvar publishes the counters of a client with the expvar package, to be served
+// as JSON at /debug/vars and scraped by monitoring tools.
+package surrealexpvar
+
+import (
+	"expvar"
+
+	"github.com/surrealdb/surrealdb.go"
+)
+
+// Publish publishes the counters returned by db.Stats under name. Like expvar.Publish, it panics
+// when name is already published.
+func Publish(name string, db *surrealdb.DB) {
+	expvar.Publish(name, Var(db))
+}
+
+// Var returns an expvar.Var reporting the counters returned by db.Stats, for example to add them
+// to an expvar.Map.
+func Var(db *surrealdb.DB) expvar.Var {
+	return expvar.Func(func() interface{} {
+		return Report(db.Stats())
+	})
+}
+
+// Report converts stats into a value encoded as JSON by expvar, with durations in seconds and the
+// mean latency and error rate of every method.
+func Report(stats surrealdb.Stats) map[string]interface{} {
+	methods := make(map[string]interface{}, len(stats.Methods))
+	for method, m := range stats.Methods {
+		methods[method] = map[string]interface{}{
+			"requests":              m.Requests,
+			"errors":                m.Errors,
+			"error_rate":            m.ErrorRate(),
+			"latency_seconds_total": m.TotalLatency.Seconds(),
+			"latency_seconds_mean":  m.MeanLatency().Seconds(),
+			"latency_seconds_max":   m.MaxLatency.Seconds(),
+		}
+	}
+
+	report := map[string]interface{}{
+		"methods":                        methods,
+		"in_flight":                      stats.InFlight,
+		"connect_retries":                stats.ConnectRetries,
+		"decode_errors":                  stats.DecodeErrors,
+		"live_notifications":             stats.LiveNotifications,
+		"notification_lag_seconds_total": stats.NotificationLag.Seconds(),
+		"dropped_notifications":          stats.DroppedNotifications,
+	}
+	if stats.HTTP != nil {
+		report["http"] = map[string]interface{}{
+			"requests":           stats.HTTP.Requests,
+			"http2_requests":     stats.HTTP.HTTP2Requests,
+			"new_connections":    stats.HTTP.NewConnections,
+			"reused_connections": stats.HTTP.ReusedConnections,
+			"idle_connections":   stats.HTTP.IdleConnections,
+		}
+	}
+	return report
+}
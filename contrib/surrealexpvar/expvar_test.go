@@ -0,0 +1,31 @@
+package surrealexpvar
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/surrealdb/surrealdb.go"
+)
+
+func TestReport(t *testing.T) {
+	report := Report(surrealdb.Stats{
+		Methods: map[string]surrealdb.MethodStats{
+			"select": {Requests: 4, Errors: 1, TotalLatency: 2 * time.Second, MaxLatency: time.Second},
+		},
+		DecodeErrors: map[string]uint64{"select": 1},
+	})
+
+	encoded, err := json.Marshal(report)
+	assert.NoError(t, err)
+
+	var decoded struct {
+		Methods map[string]map[string]float64 `json:"methods"`
+	}
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, 0.25, decoded.Methods["select"]["error_rate"])
+	assert.Equal(t, 0.5, decoded.Methods["select"]["latency_seconds_mean"])
+	assert.NotContains(t, string(encoded), `"http"`)
+}
@@ -0,0 +1,57 @@
+// Command surrealfmt formats SurrealQL, read from the files given as arguments or from the
+// standard input, and writes it to the standard output.
+//
+//	surrealfmt [-n] [file ...]
+//
+// The -n flag normalizes the queries to one line per statement instead, for diffing generated
+// queries and grouping the queries found in logs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/surrealdb/surrealdb.go"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "surrealfmt:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("surrealfmt", flag.ContinueOnError)
+	normalize := flags.Bool("n", false, "normalize to one line per statement")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	format := surrealdb.FormatSurrealQL
+	if *normalize {
+		format = surrealdb.NormalizeSurrealQL
+	}
+
+	if flags.NArg() == 0 {
+		sql, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(stdout, format(string(sql)))
+		return err
+	}
+
+	for _, name := range flags.Args() {
+		sql, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(stdout, format(string(sql))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var out strings.Builder
+	assert.NoError(t, run(nil, strings.NewReader("select * from person where age > 18"), &out))
+	assert.Equal(t, "SELECT *\n  FROM person\n  WHERE age > 18;\n", out.String())
+
+	file := filepath.Join(t.TempDir(), "query.surql")
+	assert.NoError(t, os.WriteFile(file, []byte("select *\nfrom person;\n\ndelete person"), 0o600))
+	out.Reset()
+	assert.NoError(t, run([]string{"-n", file}, strings.NewReader(""), &out))
+	assert.Equal(t, "SELECT * FROM person;\nDELETE person;\n", out.String())
+
+	assert.Error(t, run([]string{filepath.Join(t.TempDir(), "missing.surql")}, strings.NewReader(""), &out))
+}
@@ -0,0 +1,289 @@
+// Package surrealfsnotify watches the records of a table: it sends the current records of the
+// table, then the changes made to them, without missing or repeating a change in between.
+package surrealfsnotify
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// EventKind is the kind of an Event.
+type EventKind string
+
+const (
+	// Snapshot events hold the records of the table when the watch started.
+	Snapshot EventKind = "snapshot"
+	// Synced follows the last Snapshot event, and precedes the changes.
+	Synced EventKind = "synced"
+	Create EventKind = "create"
+	Update EventKind = "update"
+	Delete EventKind = "delete"
+)
+
+// Event is a record of the table, or a change made to it.
+type Event[T any] struct {
+	Kind EventKind
+	ID   models.RecordID
+	// Record is the record, as it was before being deleted for Delete events. It is nil for
+	// Synced events.
+	Record *T
+	// Err is set when the watch failed, in the last event sent.
+	Err error
+}
+
+// WatchTable sends the records of table as Snapshot events, then a Synced event, then the changes
+// made to the records, until ctx is done. The changes are notified by a live query, which is
+// polled over the http engine, see surrealdb.StartLiveQuery.
+//
+// The live query starts before the records are read, and the changes notified while they are read
+// are reconciled with them: a change the records already reflect is not sent again, and no change
+// made after the records were read is lost.
+func WatchTable[T any](ctx context.Context, db *surrealdb.DB, table models.Table) (<-chan Event[T], error) {
+	lq, err := surrealdb.StartLiveQuery[cbor.RawMessage](ctx, db, "LIVE SELECT * FROM $table",
+		map[string]interface{}{"table": table})
+	if err != nil {
+		return nil, err
+	}
+
+	w := &watcher[T]{
+		events:  make(chan Event[T]),
+		changed: make(chan struct{}, 1),
+	}
+	go w.collect(lq.Notifications())
+	go func() {
+		defer close(w.events)
+		defer func() { _ = lq.Kill() }()
+		w.run(ctx, db, table)
+	}()
+
+	return w.events, nil
+}
+
+type watcher[T any] struct {
+	events chan Event[T]
+
+	// pending holds the notifications received and not handled yet
+	mu      sync.Mutex
+	pending []change
+	closed  bool
+	changed chan struct{}
+}
+
+// change is a notification of the live query
+type change struct {
+	action connection.Action
+	id     models.RecordID
+	record cbor.RawMessage
+	err    error
+}
+
+func (w *watcher[T]) collect(notifications <-chan surrealdb.LiveNotification[cbor.RawMessage]) {
+	for n := range notifications {
+		c := change{action: n.Action, err: n.Err}
+		if n.After != nil {
+			c.record = *n.After
+		} else if n.Before != nil {
+			c.record = *n.Before
+		}
+		if c.err == nil {
+			c.id, c.err = recordID(c.record)
+		}
+
+		w.mu.Lock()
+		w.pending = append(w.pending, c)
+		w.mu.Unlock()
+		w.signal()
+	}
+
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	w.signal()
+}
+
+func (w *watcher[T]) signal() {
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the pending notifications, and whether more may come.
+func (w *watcher[T]) take() ([]change, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	pending := w.pending
+	w.pending = nil
+	return pending, !w.closed
+}
+
+func (w *watcher[T]) run(ctx context.Context, db *surrealdb.DB, table models.Table) {
+	snapshot, ok := w.snapshot(ctx, db, table)
+	if !ok {
+		return
+	}
+	if !w.send(ctx, Event[T]{Kind: Synced}) {
+		return
+	}
+
+	// the notifications received while the records were read may already be reflected by them
+	buffered, open := w.take()
+	for _, c := range reconcile(snapshot, buffered) {
+		if !w.sendChange(ctx, c) {
+			return
+		}
+	}
+
+	for open {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.changed:
+		}
+
+		var changes []change
+		changes, open = w.take()
+		for _, c := range changes {
+			if !w.sendChange(ctx, c) {
+				return
+			}
+		}
+	}
+}
+
+// snapshot sends the records of the table, and returns the digests of their encoding by id.
+func (w *watcher[T]) snapshot(ctx context.Context, db *surrealdb.DB, table models.Table) (map[string][sha256.Size]byte, bool) {
+	rows, err := surrealdb.QueryStream[cbor.RawMessage](ctx, db, "SELECT * FROM $table",
+		map[string]interface{}{"table": table})
+	if err != nil {
+		w.send(ctx, Event[T]{Err: err})
+		return nil, false
+	}
+	defer rows.Close()
+
+	digests := map[string][sha256.Size]byte{}
+	for rows.Next() {
+		raw := rows.Value()
+		event, err := decode[T](Snapshot, raw)
+		if err != nil {
+			w.send(ctx, Event[T]{Err: err})
+			return nil, false
+		}
+		digests[event.ID.String()] = sha256.Sum256(raw)
+		if !w.send(ctx, event) {
+			return nil, false
+		}
+	}
+	if err := rows.Err(); err != nil {
+		w.send(ctx, Event[T]{Err: err})
+		return nil, false
+	}
+	return digests, true
+}
+
+func (w *watcher[T]) sendChange(ctx context.Context, c change) bool {
+	if c.err != nil {
+		w.send(ctx, Event[T]{Err: c.err})
+		return false
+	}
+
+	kind := Update
+	switch c.action {
+	case connection.CreateAction:
+		kind = Create
+	case connection.DeleteAction:
+		kind = Delete
+	}
+	event, err := decode[T](kind, c.record)
+	if err != nil {
+		w.send(ctx, Event[T]{Err: err})
+		return false
+	}
+	return w.send(ctx, event)
+}
+
+func (w *watcher[T]) send(ctx context.Context, event Event[T]) bool {
+	select {
+	case w.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// reconcile returns the changes of buffered that the records of the snapshot, given by the digests
+// of their encoding, do not reflect. For each record, the changes up to the last one leading to the
+// state of the record in the snapshot are dropped. The kind of the changes left is adjusted to the
+// state of the records, so that for example a record created after being read is sent as updated.
+func reconcile(snapshot map[string][sha256.Size]byte, buffered []change) []change {
+	// skip[i] is true when the i-th change is already reflected by the snapshot
+	skip := make([]bool, len(buffered))
+	last := map[string]int{}
+	for i, c := range buffered {
+		if c.err != nil {
+			continue
+		}
+		digest, exists := snapshot[c.id.String()]
+		if c.action == connection.DeleteAction && !exists ||
+			c.action != connection.DeleteAction && exists && digest == sha256.Sum256(c.record) {
+			last[c.id.String()] = i
+		}
+	}
+	for i, c := range buffered {
+		if at, ok := last[c.id.String()]; ok && c.err == nil && i <= at {
+			skip[i] = true
+		}
+	}
+
+	var changes []change
+	for i, c := range buffered {
+		if skip[i] {
+			continue
+		}
+		if c.err == nil {
+			key := c.id.String()
+			_, exists := snapshot[key]
+			switch {
+			case c.action == connection.DeleteAction:
+				if !exists {
+					continue
+				}
+				delete(snapshot, key)
+			case exists:
+				c.action = connection.UpdateAction
+				snapshot[key] = sha256.Sum256(c.record)
+			default:
+				c.action = connection.CreateAction
+				snapshot[key] = sha256.Sum256(c.record)
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func decode[T any](kind EventKind, raw cbor.RawMessage) (Event[T], error) {
+	id, err := recordID(raw)
+	if err != nil {
+		return Event[T]{}, err
+	}
+	var record T
+	if err := (models.CborUnmarshaler{}).Unmarshal(raw, &record); err != nil {
+		return Event[T]{}, err
+	}
+	return Event[T]{Kind: kind, ID: id, Record: &record}, nil
+}
+
+func recordID(raw cbor.RawMessage) (models.RecordID, error) {
+	var record struct {
+		ID models.RecordID `json:"id"`
+	}
+	err := (models.CborUnmarshaler{}).Unmarshal(raw, &record)
+	return record.ID, err
+}
@@ -0,0 +1,60 @@
+package surrealfsnotify
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+type note struct {
+	ID   models.RecordID `json:"id"`
+	Text string          `json:"text"`
+}
+
+func encode(t *testing.T, id, text string) cbor.RawMessage {
+	data, err := models.CborMarshaler{}.Marshal(note{ID: models.NewRecordID("note", id), Text: text})
+	assert.NoError(t, err)
+	return data
+}
+
+func notified(t *testing.T, action connection.Action, id, text string) change {
+	return change{action: action, id: models.NewRecordID("note", id), record: encode(t, id, text)}
+}
+
+func TestReconcile(t *testing.T) {
+	snapshot := map[string][sha256.Size]byte{
+		"note:a": sha256.Sum256(encode(t, "a", "a2")),
+		"note:b": sha256.Sum256(encode(t, "b", "b1")),
+	}
+
+	changes := reconcile(snapshot, []change{
+		// reflected by the snapshot of a
+		notified(t, connection.UpdateAction, "a", "a1"),
+		notified(t, connection.UpdateAction, "a", "a2"),
+		// made after b was read
+		notified(t, connection.UpdateAction, "b", "b2"),
+		// c was created and deleted before being read
+		notified(t, connection.CreateAction, "c", "c1"),
+		notified(t, connection.DeleteAction, "c", "c1"),
+		// d was created after the records were read
+		notified(t, connection.CreateAction, "d", "d1"),
+		// b was deleted after being read, then created again
+		notified(t, connection.DeleteAction, "b", "b2"),
+		notified(t, connection.CreateAction, "b", "b3"),
+		// a is updated after being read
+		notified(t, connection.UpdateAction, "a", "a3"),
+	})
+
+	var got []string
+	for _, c := range changes {
+		var n note
+		assert.NoError(t, models.CborUnmarshaler{}.Unmarshal(c.record, &n))
+		got = append(got, string(c.action)+" "+n.Text)
+	}
+	assert.Equal(t, []string{"UPDATE b2", "CREATE d1", "DELETE b2", "CREATE b3", "UPDATE a3"}, got)
+}
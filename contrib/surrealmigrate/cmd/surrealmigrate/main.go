@@ -0,0 +1,102 @@
+// Command surrealmigrate applies the migration scripts of a directory to the database of
+// SURREALDB_URL, see surrealdb.FromEnv and the surrealmigrate package.
+//
+//	surrealmigrate [-dir migrations] [-table _migrations] [-n] up
+//	surrealmigrate [-dir migrations] [-table _migrations] [-n] down [steps]
+//	surrealmigrate [-dir migrations] [-table _migrations] status
+//
+// The -n flag prints the migrations that would be applied or rolled back, without changing the
+// database. down rolls back the last migration unless given a number of steps.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/contrib/surrealmigrate"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func main() {
+	ctx := context.Background()
+	connect := func() (*surrealdb.DB, error) { return surrealdb.FromEnv(ctx) }
+	if err := run(ctx, os.Args[1:], connect, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "surrealmigrate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, connect func() (*surrealdb.DB, error), stdout io.Writer) error {
+	flags := flag.NewFlagSet("surrealmigrate", flag.ContinueOnError)
+	dir := flags.String("dir", "migrations", "directory of the migration scripts")
+	table := flags.String("table", surrealmigrate.DefaultTable, "table recording the applied migrations")
+	dryRun := flags.Bool("n", false, "print the migrations without applying them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	command, steps := flags.Arg(0), 1
+	switch {
+	case (command == "up" || command == "down" || command == "status") && flags.NArg() == 1:
+	case command == "down" && flags.NArg() == 2:
+		var err error
+		if steps, err = strconv.Atoi(flags.Arg(1)); err != nil || steps <= 0 {
+			return fmt.Errorf("invalid number of steps %q", flags.Arg(1))
+		}
+	default:
+		return fmt.Errorf("usage: surrealmigrate [flags] up | down [steps] | status")
+	}
+
+	migrations, err := surrealmigrate.Load(os.DirFS(*dir), ".")
+	if err != nil {
+		return err
+	}
+	db, err := connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := surrealmigrate.New(db, migrations, surrealmigrate.Options{
+		Table:  models.Table(*table),
+		DryRun: *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	var done []surrealmigrate.Migration
+	switch command {
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(stdout, "%s\t%s\n", s.Migration, state)
+		}
+		return nil
+	case "up":
+		done, err = migrator.Up(ctx)
+	case "down":
+		done, err = migrator.Down(ctx, steps)
+	}
+
+	verb := map[string]string{"up": "applied", "down": "rolled back"}[command]
+	if *dryRun {
+		verb = "would have " + verb
+	}
+	for _, m := range done {
+		fmt.Fprintf(stdout, "%s %s\n", verb, m)
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestRun(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		// no migration is applied yet
+		return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": []interface{}{}}}, nil
+	})
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1_create_users.up.surql"), []byte("DEFINE TABLE user"), 0o600))
+	connect := func() (*surrealdb.DB, error) {
+		return surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), []string{"-dir", dir, "-n", "up"}, connect, &out))
+	assert.Equal(t, "would have applied 1_create_users\n", out.String())
+
+	out.Reset()
+	require.NoError(t, run(context.Background(), []string{"-dir", dir, "status"}, connect, &out))
+	assert.Equal(t, "1_create_users\tpending\n", out.String())
+
+	assert.Error(t, run(context.Background(), []string{"-dir", dir, "sideways"}, connect, &out))
+	assert.Error(t, run(context.Background(), []string{"-dir", dir, "down", "0"}, connect, &out))
+}
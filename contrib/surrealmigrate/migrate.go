@@ -0,0 +1,358 @@
+// Package surrealmigrate applies versioned schema migrations to a SurrealDB database.
+//
+// Migrations are SurrealQL scripts or Go functions, applied in the order of their versions. The
+// applied migrations are recorded in a table, _migrations by default, so that each is applied
+// once. A script is applied along with its record in a single transaction, and must therefore not
+// hold transaction statements of its own. Go functions are recorded once they returned, which is
+// not atomic.
+//
+// Runners take a lock before applying migrations, so that concurrent runners, such as the
+// replicas of a service starting together, do not apply the same migration twice. The lock
+// expires after Options.LockTimeout, for a crashed runner not to block the others forever.
+package surrealmigrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+const (
+	// DefaultTable is the table recording the applied migrations when Options.Table is not set.
+	DefaultTable = "_migrations"
+
+	DefaultLockTimeout = 15 * time.Minute
+)
+
+var (
+	// ErrLocked is returned when another runner holds the lock of the migrations.
+	ErrLocked = errors.New("migrations are locked by another runner")
+	// ErrIrreversible is returned when rolling back a migration without down migration.
+	ErrIrreversible = errors.New("migration cannot be rolled back")
+)
+
+// Migration is a migration of a database. Up applies it, with either a SurrealQL script or a Go
+// function, and Down rolls it back. Down is optional.
+type Migration struct {
+	// Version orders the migrations, and must be positive and unique.
+	Version int64
+	Name    string
+
+	Up   string
+	Down string
+
+	UpFunc   func(ctx context.Context, db *surrealdb.DB) error
+	DownFunc func(ctx context.Context, db *surrealdb.DB) error
+}
+
+func (m Migration) String() string {
+	if m.Name == "" {
+		return strconv.FormatInt(m.Version, 10)
+	}
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+func (m Migration) reversible() bool {
+	return m.Down != "" || m.DownFunc != nil
+}
+
+// Options configures a Migrator. The zero value of every field selects its default.
+type Options struct {
+	// Table records the applied migrations, DefaultTable when empty.
+	Table models.Table
+	// LockTable holds the lock of the runners, Table suffixed with _lock when empty.
+	LockTable   models.Table
+	LockTimeout time.Duration
+	// DryRun makes Up and Down return the migrations they would apply, without applying them.
+	DryRun bool
+}
+
+// Status is the state of a migration. The migrations applied to the database but unknown to the
+// Migrator only have their version and name set.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *surrealdb.DB
+	migrations []Migration
+	opts       Options
+}
+
+// applied is the record of an applied migration.
+type applied struct {
+	Version   int64                 `json:"version"`
+	Name      string                `json:"name"`
+	AppliedAt models.CustomDateTime `json:"applied_at"`
+}
+
+// New returns a Migrator applying migrations to db.
+func New(db *surrealdb.DB, migrations []Migration, opts Options) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migration %s: version must be positive", m)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", sorted[i-1], m)
+		}
+		if (m.Up == "") == (m.UpFunc == nil) {
+			return nil, fmt.Errorf("migration %s: exactly one of Up and UpFunc must be set", m)
+		}
+		if m.Down != "" && m.DownFunc != nil {
+			return nil, fmt.Errorf("migration %s: Down and DownFunc cannot both be set", m)
+		}
+	}
+
+	if opts.Table == "" {
+		opts.Table = DefaultTable
+	}
+	if opts.LockTable == "" {
+		opts.LockTable = opts.Table + "_lock"
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = DefaultLockTimeout
+	}
+
+	return &Migrator{db: db, migrations: sorted, opts: opts}, nil
+}
+
+// Load reads the migration scripts of fsys, in the directory dir. Scripts are named
+// <version>_<name>.up.surql and <version>_<name>.down.surql, the down script being optional.
+// Other files are ignored.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		base, up := strings.CutSuffix(name, ".up.surql")
+		if !up {
+			var down bool
+			if base, down = strings.CutSuffix(name, ".down.surql"); !down {
+				continue
+			}
+		}
+
+		prefix, label, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: name does not start with a version", name)
+		}
+		script, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		}
+		if m.Name != label {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", m, base)
+		}
+		if up {
+			m.Up = string(script)
+		} else {
+			m.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %s has no up script", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Status returns the state of every migration, ordered by version.
+func (m *Migrator) Status() ([]Status, error) {
+	records, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]applied{}
+	for _, r := range records {
+		byVersion[r.Version] = r
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		r, ok := byVersion[migration.Version]
+		delete(byVersion, migration.Version)
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: r.AppliedAt.Time})
+	}
+	for _, r := range byVersion {
+		statuses = append(statuses, Status{
+			Migration: Migration{Version: r.Version, Name: r.Name},
+			Applied:   true,
+			AppliedAt: r.AppliedAt.Time,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Up applies the migrations not applied yet, in order, and returns them. It stops at the first
+// migration that fails, or once ctx is done, the migrations applied until then staying applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.run(ctx, func(statuses []Status) ([]Migration, error) {
+		var pending []Migration
+		for _, s := range statuses {
+			if !s.Applied {
+				pending = append(pending, s.Migration)
+			}
+		}
+		return pending, nil
+	}, m.up)
+}
+
+// Down rolls back the last steps applied migrations, the latest first, and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	return m.run(ctx, func(statuses []Status) ([]Migration, error) {
+		var rollback []Migration
+		for i := len(statuses) - 1; i >= 0 && len(rollback) < steps; i-- {
+			if !statuses[i].Applied {
+				continue
+			}
+			if !statuses[i].reversible() {
+				return nil, fmt.Errorf("%w: %s has no down migration", ErrIrreversible, statuses[i].Migration)
+			}
+			rollback = append(rollback, statuses[i].Migration)
+		}
+		return rollback, nil
+	}, m.down)
+}
+
+// run applies the migrations selected by plan with apply, holding the lock.
+func (m *Migrator) run(ctx context.Context, plan func([]Status) ([]Migration, error), apply func(context.Context, Migration) error) ([]Migration, error) {
+	if m.opts.DryRun {
+		statuses, err := m.Status()
+		if err != nil {
+			return nil, err
+		}
+		return plan(statuses)
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// the status is read once locked, as another runner may have applied migrations meanwhile
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := plan(statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, migration := range migrations {
+		if err := ctx.Err(); err != nil {
+			return migrations[:i], err
+		}
+		if err := apply(ctx, migration); err != nil {
+			return migrations[:i], fmt.Errorf("migration %s: %w", migration, err)
+		}
+	}
+	return migrations, nil
+}
+
+func (m *Migrator) up(ctx context.Context, migration Migration) error {
+	record := `CREATE type::thing($migration_table, $migration_version)
+		CONTENT { version: $migration_version, name: $migration_name, applied_at: time::now() } RETURN NONE`
+	if migration.UpFunc != nil {
+		if err := migration.UpFunc(ctx, m.db); err != nil {
+			return err
+		}
+		return m.exec(record, migration)
+	}
+	return m.exec(transaction(migration.Up, record), migration)
+}
+
+func (m *Migrator) down(ctx context.Context, migration Migration) error {
+	record := "DELETE type::thing($migration_table, $migration_version)"
+	if migration.DownFunc != nil {
+		if err := migration.DownFunc(ctx, m.db); err != nil {
+			return err
+		}
+		return m.exec(record, migration)
+	}
+	return m.exec(transaction(migration.Down, record), migration)
+}
+
+// lock takes the lock of the migrations, replacing the lock of a runner that expired, and returns
+// the function releasing it.
+func (m *Migrator) lock() (func(), error) {
+	owner := uuid.Must(uuid.NewV4()).String()
+	vars := map[string]interface{}{
+		"lock_table": string(m.opts.LockTable),
+		"owner":      owner,
+		"timeout":    &models.CustomDuration{Duration: m.opts.LockTimeout},
+	}
+
+	_, err := surrealdb.QueryAll[interface{}](m.db, `DELETE type::thing($lock_table, 'lock') WHERE expires_at <= time::now();
+		CREATE type::thing($lock_table, 'lock') CONTENT { owner: $owner, expires_at: time::now() + $timeout } RETURN NONE`,
+		vars)
+	if errors.Is(err, constants.ErrAlreadyExists) {
+		return nil, ErrLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_, _ = surrealdb.QueryAll[interface{}](m.db, "DELETE type::thing($lock_table, 'lock') WHERE owner = $owner", vars)
+	}, nil
+}
+
+func (m *Migrator) applied() ([]applied, error) {
+	return surrealdb.QueryAll[applied](m.db, "SELECT version, name, applied_at FROM type::table($migration_table) ORDER BY version",
+		map[string]interface{}{"migration_table": string(m.opts.Table)})
+}
+
+func (m *Migrator) exec(sql string, migration Migration) error {
+	_, err := surrealdb.QueryAll[interface{}](m.db, sql, map[string]interface{}{
+		"migration_table":   string(m.opts.Table),
+		"migration_version": migration.Version,
+		"migration_name":    migration.Name,
+	})
+	return err
+}
+
+// transaction returns the statements of script and record, run in a single transaction.
+func transaction(script, record string) string {
+	script = strings.TrimRight(strings.TrimSpace(script), ";")
+	return "BEGIN TRANSACTION;\n" + script + ";\n" + record + ";\nCOMMIT TRANSACTION"
+}
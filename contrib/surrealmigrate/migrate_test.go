@@ -0,0 +1,137 @@
+package surrealmigrate
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/2_add_index.up.surql":      {Data: []byte("DEFINE INDEX email ON user FIELDS email UNIQUE;")},
+		"migrations/1_create_users.up.surql":   {Data: []byte("DEFINE TABLE user SCHEMALESS;")},
+		"migrations/1_create_users.down.surql": {Data: []byte("REMOVE TABLE user;")},
+		"migrations/README.md":                 {Data: []byte("# Migrations")},
+	}
+
+	migrations, err := Load(fsys, "migrations")
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "create_users", Up: "DEFINE TABLE user SCHEMALESS;", Down: "REMOVE TABLE user;"},
+		{Version: 2, Name: "add_index", Up: "DEFINE INDEX email ON user FIELDS email UNIQUE;"},
+	}, migrations)
+
+	fsys["migrations/3_orphan.down.surql"] = &fstest.MapFile{Data: []byte("REMOVE TABLE orphan;")}
+	_, err = Load(fsys, "migrations")
+	assert.Error(t, err, "a down script without up script")
+
+	_, err = New(nil, []Migration{{Version: 1, Up: "DEFINE TABLE a"}, {Version: 1, Up: "DEFINE TABLE b"}}, Options{})
+	assert.Error(t, err, "duplicate versions")
+	_, err = New(nil, []Migration{{Version: 1}}, Options{})
+	assert.Error(t, err, "neither Up nor UpFunc")
+}
+
+// fakeServer answers queries as a database where the migrations of versions are applied, and
+// records the queries it received.
+type fakeServer struct {
+	lock     sync.Mutex
+	queries  []string
+	versions []int64
+	locked   bool
+}
+
+func (f *fakeServer) serve(t *testing.T) *httptest.Server {
+	return mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		sql := req.Params[0].(string)
+		f.queries = append(f.queries, sql)
+
+		statements := []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": []interface{}{}}}
+		switch {
+		case strings.HasPrefix(sql, "SELECT version"):
+			var records []interface{}
+			for _, v := range f.versions {
+				records = append(records, map[string]interface{}{
+					"version":    v,
+					"name":       "applied",
+					"applied_at": models.CustomDateTime{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+				})
+			}
+			statements[0] = map[string]interface{}{"status": "OK", "time": "1ms", "result": records}
+		case strings.Contains(sql, "CREATE type::thing($lock_table") && f.locked:
+			statements = append(statements, map[string]interface{}{
+				"status": "ERR", "time": "1ms", "result": "Database record `_migrations_lock:lock` already exists",
+			})
+		}
+		return statements, nil
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	fake := &fakeServer{versions: []int64{1}}
+	server := fake.serve(t)
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	var funcCalls int
+	migrations := []Migration{
+		{Version: 3, Name: "backfill", UpFunc: func(ctx context.Context, db *surrealdb.DB) error {
+			funcCalls++
+			return nil
+		}},
+		{Version: 1, Name: "create_users", Up: "DEFINE TABLE user SCHEMALESS"},
+		{Version: 2, Name: "add_index", Up: "DEFINE INDEX email ON user FIELDS email UNIQUE;", Down: "REMOVE INDEX email ON user"},
+	}
+
+	dryRun, err := New(db, migrations, Options{DryRun: true})
+	require.NoError(t, err)
+	planned, err := dryRun.Up(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, versions(planned))
+	assert.Len(t, fake.queries, 1, "a dry run only reads the applied migrations")
+	assert.Zero(t, funcCalls)
+
+	migrator, err := New(db, migrations, Options{})
+	require.NoError(t, err)
+	fake.queries = nil
+	applied, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, versions(applied))
+	assert.Equal(t, 1, funcCalls)
+
+	require.Len(t, fake.queries, 5)
+	assert.Contains(t, fake.queries[0], "CREATE type::thing($lock_table, 'lock')")
+	assert.True(t, strings.HasPrefix(fake.queries[2], "BEGIN TRANSACTION;\nDEFINE INDEX email ON user FIELDS email UNIQUE;\nCREATE"))
+	assert.True(t, strings.HasSuffix(fake.queries[2], "RETURN NONE;\nCOMMIT TRANSACTION"))
+	assert.True(t, strings.HasPrefix(fake.queries[3], "CREATE type::thing($migration_table"), "the function is recorded once run")
+	assert.Contains(t, fake.queries[4], "WHERE owner = $owner")
+
+	fake.versions = []int64{1, 2, 3}
+	_, err = migrator.Down(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrIrreversible)
+
+	fake.locked = true
+	_, err = migrator.Up(context.Background())
+	assert.ErrorIs(t, err, ErrLocked)
+}
+
+func versions(migrations []Migration) []int64 {
+	var v []int64
+	for _, m := range migrations {
+		v = append(v, m.Version)
+	}
+	return v
+}
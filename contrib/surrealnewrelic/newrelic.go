@@ -0,0 +1,50 @@
+// Package surrealnewrelic records the requests of a client as New Relic datastore segments of the
+// transaction held by the context of the request, as set with surrealdb.DB.WithContext and
+// newrelic.NewContext. Requests made outside of a transaction are not recorded.
+//
+//	db, err := surrealdb.Connect(ctx, "ws://localhost:8000", surrealdb.WithHooks(surrealnewrelic.Hooks()))
+//	users, err := surrealdb.Select[[]User](db.WithContext(newrelic.NewContext(ctx, txn)), models.Table("user"))
+//
+// It is a module of its own, so that the SDK does not depend on the New Relic agent.
+package surrealnewrelic
+
+import (
+	"context"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+
+	"github.com/surrealdb/surrealdb.go/contrib/surrealtrace"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+)
+
+// Product is the datastore product the segments are reported under.
+const Product newrelic.DatastoreProduct = "SurrealDB"
+
+// Hooks returns the hooks recording every request as a datastore segment, to be given to
+// surrealdb.WithHooks.
+func Hooks() connection.Hooks {
+	return surrealtrace.Hooks(Start)
+}
+
+// Start starts the datastore segment of a request in the transaction of ctx. It is the
+// surrealtrace.StartFunc of New Relic.
+func Start(ctx context.Context, operation string, tags map[string]string) (context.Context, func(error)) {
+	txn := newrelic.FromContext(ctx)
+	if txn == nil {
+		return ctx, func(error) {}
+	}
+
+	segment := &newrelic.DatastoreSegment{
+		StartTime:          txn.StartSegmentNow(),
+		Product:            Product,
+		Collection:         tags[surrealtrace.TagTable],
+		Operation:          tags[surrealtrace.TagOperation],
+		ParameterizedQuery: tags[surrealtrace.TagStatement],
+	}
+	return ctx, func(err error) {
+		if err != nil {
+			segment.AddAttribute("error", err.Error())
+		}
+		segment.End()
+	}
+}
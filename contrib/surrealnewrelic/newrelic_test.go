@@ -0,0 +1,39 @@
+package surrealnewrelic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surrealdb/surrealdb.go/contrib/surrealtrace"
+)
+
+func TestStart(t *testing.T) {
+	tags := map[string]string{
+		surrealtrace.TagOperation: "select",
+		surrealtrace.TagTable:     "user",
+	}
+
+	t.Run("without transaction", func(t *testing.T) {
+		ctx := context.Background()
+		segmentCtx, end := Start(ctx, "surrealdb.select", tags)
+		assert.Equal(t, ctx, segmentCtx)
+		end(nil)
+	})
+
+	t.Run("within a transaction", func(t *testing.T) {
+		app, err := newrelic.NewApplication(newrelic.ConfigAppName("surrealnewrelic"), newrelic.ConfigEnabled(false))
+		require.NoError(t, err)
+		txn := app.StartTransaction("request")
+		defer txn.End()
+
+		ctx := newrelic.NewContext(context.Background(), txn)
+		segmentCtx, end := Start(ctx, "surrealdb.select", tags)
+		assert.Equal(t, txn, newrelic.FromContext(segmentCtx))
+		end(errors.New("not enough permissions"))
+	})
+}
@@ -0,0 +1,312 @@
+// Package surrealqueue implements a job queue stored in SurrealDB tables.
+//
+// Jobs are claimed with a lease: a claimed job stays invisible to other consumers for the
+// visibility timeout, and is delivered again once the timeout expires without the job being
+// acknowledged, for example because its consumer crashed. Failed jobs are retried with an
+// exponential backoff, and moved to a dead letter table after too many attempts.
+//
+// Each consumer group receives every job enqueued once its queue was created with the group,
+// and within a group each job is delivered to a single consumer.
+package surrealqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+const (
+	// DefaultTable is the table holding the jobs of every queue when Options.Table is not set.
+	DefaultTable = "queue_job"
+	// DefaultGroup is the consumer group of the queues created without groups.
+	DefaultGroup = "default"
+
+	DefaultVisibilityTimeout = 30 * time.Second
+	DefaultMaxAttempts       = 5
+	DefaultInitialBackoff    = time.Second
+	DefaultMaxBackoff        = 5 * time.Minute
+	DefaultPollInterval      = time.Second
+)
+
+// ErrLeaseLost is returned when acknowledging a job whose lease expired, meaning that the job may
+// have been delivered to another consumer.
+var ErrLeaseLost = errors.New("the lease of the job expired")
+
+// Options configures a queue. The zero value of every field selects its default.
+type Options struct {
+	// Table holds the jobs, DefaultTable when empty. Queues may share a table.
+	Table models.Table
+	// DeadLetterTable holds the jobs that failed MaxAttempts times, Table suffixed with _dead
+	// when empty.
+	DeadLetterTable models.Table
+	// Groups are the consumer groups of the queue, each receiving every job. A queue without
+	// groups has the single group DefaultGroup.
+	Groups []string
+
+	VisibilityTimeout time.Duration
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	// PollInterval is how often Consume looks for jobs when it cannot be notified of new jobs
+	// with a live query, such as over the http engine.
+	PollInterval time.Duration
+}
+
+// Job is a job of a queue, holding a payload of type T.
+type Job[T any] struct {
+	ID      *models.RecordID `json:"id,omitempty"`
+	Queue   string           `json:"queue"`
+	Group   string           `json:"group"`
+	Payload T                `json:"payload"`
+	// Attempts is the number of times the job was delivered, including the current delivery.
+	Attempts   int                    `json:"attempts"`
+	Lease      string                 `json:"lease,omitempty"`
+	LastError  string                 `json:"last_error,omitempty"`
+	EnqueuedAt *models.CustomDateTime `json:"enqueued_at,omitempty"`
+	VisibleAt  *models.CustomDateTime `json:"visible_at,omitempty"`
+	// FailedAt is when the job was moved to the dead letter table.
+	FailedAt *models.CustomDateTime `json:"failed_at,omitempty"`
+}
+
+// Queue is a named queue of jobs holding payloads of type T.
+type Queue[T any] struct {
+	db   *surrealdb.DB
+	name string
+	opts Options
+}
+
+// New returns the queue name, stored in the tables of opts.
+func New[T any](db *surrealdb.DB, name string, opts Options) (*Queue[T], error) {
+	if name == "" {
+		return nil, fmt.Errorf("queue name must not be empty")
+	}
+	if opts.Table == "" {
+		opts.Table = DefaultTable
+	}
+	if opts.DeadLetterTable == "" {
+		opts.DeadLetterTable = opts.Table + "_dead"
+	}
+	if len(opts.Groups) == 0 {
+		opts.Groups = []string{DefaultGroup}
+	}
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = DefaultVisibilityTimeout
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+
+	return &Queue[T]{db: db, name: name, opts: opts}, nil
+}
+
+// Enqueue adds a job holding payload for every consumer group of the queue.
+func (q *Queue[T]) Enqueue(payload T) error {
+	jobs := make([]map[string]interface{}, len(q.opts.Groups))
+	for i, group := range q.opts.Groups {
+		jobs[i] = map[string]interface{}{"queue": q.name, "group": group, "payload": payload, "attempts": 0}
+	}
+
+	_, err := surrealdb.QueryAll[Job[T]](q.db,
+		"INSERT INTO $table (SELECT *, time::now() AS enqueued_at, time::now() AS visible_at FROM $jobs)",
+		map[string]interface{}{"table": q.opts.Table, "jobs": jobs})
+	return err
+}
+
+// Dequeue claims the next job of group that is ready, and returns nil when there is none. The job
+// must be acknowledged with Ack once processed, or released with Nack, before the visibility
+// timeout expires.
+//
+// Jobs whose visibility timeout expired after MaxAttempts deliveries are moved to the dead letter
+// table instead of being delivered again.
+func (q *Queue[T]) Dequeue(group string) (*Job[T], error) {
+	for {
+		jobs, err := surrealdb.QueryAll[Job[T]](q.db, `UPDATE (
+				SELECT VALUE id FROM $table
+				WHERE queue = $queue AND group = $group AND visible_at <= time::now()
+				ORDER BY visible_at LIMIT 1
+			)
+			SET attempts += 1, lease = rand::uuid(), visible_at = time::now() + $timeout
+			WHERE visible_at <= time::now()
+			RETURN AFTER`,
+			map[string]interface{}{
+				"table":   q.opts.Table,
+				"queue":   q.name,
+				"group":   group,
+				"timeout": &models.CustomDuration{Duration: q.opts.VisibilityTimeout},
+			})
+		if err != nil || len(jobs) == 0 {
+			return nil, err
+		}
+
+		job := &jobs[0]
+		if job.Attempts <= q.opts.MaxAttempts {
+			return job, nil
+		}
+		if err := q.deadLetter(job, "visibility timeout expired after the last attempt"); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Ack acknowledges a job claimed with Dequeue, removing it from the queue.
+func (q *Queue[T]) Ack(job *Job[T]) error {
+	deleted, err := surrealdb.QueryAll[Job[T]](q.db, "DELETE $id WHERE lease = $lease RETURN BEFORE",
+		map[string]interface{}{"id": job.ID, "lease": job.Lease})
+	if err != nil {
+		return err
+	}
+	if len(deleted) == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Nack releases a job claimed with Dequeue after it failed with cause. The job is delivered again
+// after a backoff, or moved to the dead letter table once it failed MaxAttempts times.
+func (q *Queue[T]) Nack(job *Job[T], cause error) error {
+	if job.Attempts >= q.opts.MaxAttempts {
+		return q.deadLetter(job, cause.Error())
+	}
+
+	released, err := surrealdb.QueryAll[Job[T]](q.db,
+		"UPDATE $id SET lease = NONE, last_error = $cause, visible_at = time::now() + $backoff WHERE lease = $lease RETURN AFTER",
+		map[string]interface{}{
+			"id":      job.ID,
+			"lease":   job.Lease,
+			"cause":   cause.Error(),
+			"backoff": &models.CustomDuration{Duration: q.backoff(job.Attempts)},
+		})
+	if err != nil {
+		return err
+	}
+	if len(released) == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// DeadLetters returns the jobs of the queue moved to the dead letter table.
+func (q *Queue[T]) DeadLetters() ([]Job[T], error) {
+	return surrealdb.QueryAll[Job[T]](q.db, "SELECT * FROM $table WHERE queue = $queue ORDER BY enqueued_at",
+		map[string]interface{}{"table": q.opts.DeadLetterTable, "queue": q.name})
+}
+
+// Consume processes the jobs of group with handle until ctx is done, acknowledging the jobs handled
+// without error and releasing the others with Nack. It waits for new jobs with a live query when
+// the connection supports them, and otherwise polls for jobs every PollInterval.
+//
+// Several consumers may run Consume on the same group, each job being handled by one of them.
+func (q *Queue[T]) Consume(ctx context.Context, group string, handle func(ctx context.Context, job *Job[T]) error) error {
+	wakeup, stop := q.watch()
+	defer stop()
+
+	for {
+		job, err := q.Dequeue(group)
+		if err != nil && !surrealdb.IsTransient(err) {
+			return err
+		}
+
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-wakeup:
+			case <-time.After(q.opts.PollInterval):
+			}
+			continue
+		}
+
+		if err := handle(ctx, job); err != nil {
+			err = q.Nack(job, err)
+			if err != nil && !errors.Is(err, ErrLeaseLost) {
+				return err
+			}
+			continue
+		}
+		if err := q.Ack(job); err != nil && !errors.Is(err, ErrLeaseLost) {
+			return err
+		}
+	}
+}
+
+// watch returns a channel receiving a value when jobs are created, or nil when the connection
+// does not support live queries.
+func (q *Queue[T]) watch() (<-chan struct{}, func()) {
+	id, err := surrealdb.Live(q.db, q.opts.Table, false)
+	if err != nil {
+		return nil, func() {}
+	}
+	notifications, err := q.db.LiveNotifications(id.String())
+	if err != nil {
+		_ = surrealdb.Kill(q.db, id.String())
+		return nil, func() {}
+	}
+
+	wakeup := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case _, ok := <-notifications:
+				if !ok {
+					return
+				}
+				select {
+				case wakeup <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return wakeup, func() {
+		close(done)
+		_ = surrealdb.Kill(q.db, id.String())
+	}
+}
+
+// deadLetter moves job to the dead letter table, unless its lease expired.
+func (q *Queue[T]) deadLetter(job *Job[T], cause string) error {
+	_, err := surrealdb.QueryAll[interface{}](q.db, `BEGIN TRANSACTION;
+		LET $job = (DELETE $id WHERE lease = $lease RETURN BEFORE)[0];
+		IF $job != NONE {
+			CREATE type::thing($dead, $id.id) CONTENT {
+				queue: $job.queue,
+				group: $job.group,
+				payload: $job.payload,
+				attempts: $job.attempts,
+				enqueued_at: $job.enqueued_at,
+				last_error: $cause,
+				failed_at: time::now()
+			}
+		};
+		COMMIT TRANSACTION`,
+		map[string]interface{}{"id": job.ID, "lease": job.Lease, "dead": string(q.opts.DeadLetterTable), "cause": cause})
+	return err
+}
+
+func (q *Queue[T]) backoff(attempts int) time.Duration {
+	backoff := q.opts.InitialBackoff
+	for i := 1; i < attempts && backoff < q.opts.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > q.opts.MaxBackoff {
+		backoff = q.opts.MaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,48 @@
+package surrealqueue
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestBackoff(t *testing.T) {
+	q, err := New[string](nil, "emails", Options{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Table("queue_job_dead"), q.opts.DeadLetterTable)
+	assert.Equal(t, []string{DefaultGroup}, q.opts.Groups)
+
+	assert.Equal(t, time.Second, q.backoff(1))
+	assert.Equal(t, 2*time.Second, q.backoff(2))
+	assert.Equal(t, 8*time.Second, q.backoff(4))
+	assert.Equal(t, 10*time.Second, q.backoff(5))
+	assert.Equal(t, 10*time.Second, q.backoff(50))
+
+	_, err = New[string](nil, "", Options{})
+	assert.Error(t, err)
+}
+
+func TestAckLeaseLost(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		// the lease of the job expired, so the conditional delete matches nothing
+		assert.True(t, strings.HasPrefix(req.Params[0].(string), "DELETE $id WHERE lease = $lease"))
+		return []surrealdb.QueryResult[interface{}]{{Status: "OK", Result: []interface{}{}}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	assert.NoError(t, err)
+
+	q, err := New[string](db, "emails", Options{})
+	assert.NoError(t, err)
+
+	id := models.NewRecordID("queue_job", "a")
+	err = q.Ack(&Job[string]{ID: &id, Lease: "expired"})
+	assert.ErrorIs(t, err, ErrLeaseLost)
+}
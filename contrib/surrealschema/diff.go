@@ -0,0 +1,96 @@
+package surrealschema
+
+import (
+	"strings"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Diff returns the statements converging the schema from to the schema to, in the order they
+// must be run in. Definitions missing from to are removed, definitions missing from from are
+// defined, and changed definitions are defined again with OVERWRITE, which requires SurrealDB
+// 2.0 or later.
+//
+// Removing a table deletes its records, so the statements are meant to be reviewed before being
+// run. Tables are compared by name: a renamed table is removed and defined again.
+func Diff(from, to *Schema) []string {
+	var statements []string
+
+	// removals first, dependent definitions before what they depend on
+	for _, name := range sortedKeys(from.Tables) {
+		old := from.Tables[name]
+		table, ok := to.Tables[name]
+		if !ok {
+			statements = append(statements, "REMOVE TABLE "+ident(name))
+			continue
+		}
+		on := " ON " + ident(name)
+		for _, event := range sortedKeys(old.Events) {
+			if _, ok := table.Events[event]; !ok {
+				statements = append(statements, "REMOVE EVENT "+ident(event)+on)
+			}
+		}
+		for _, index := range sortedKeys(old.Indexes) {
+			if _, ok := table.Indexes[index]; !ok {
+				statements = append(statements, "REMOVE INDEX "+ident(index)+on)
+			}
+		}
+		fields := sortedKeys(old.Fields)
+		for i := len(fields) - 1; i >= 0; i-- {
+			// nested fields, sorted after their parent, are removed first
+			if _, ok := table.Fields[fields[i]]; !ok {
+				statements = append(statements, "REMOVE FIELD "+fields[i]+on)
+			}
+		}
+	}
+	for _, name := range sortedKeys(from.Analyzers) {
+		if _, ok := to.Analyzers[name]; !ok {
+			statements = append(statements, "REMOVE ANALYZER "+ident(name))
+		}
+	}
+
+	// definitions next, analyzers and tables before the indexes and fields using them
+	for _, name := range sortedKeys(to.Analyzers) {
+		statements = appendDefinition(statements, from.Analyzers[name].Definition, to.Analyzers[name].Definition)
+	}
+	for _, name := range sortedKeys(to.Tables) {
+		statements = appendDefinition(statements, from.Tables[name].Definition, to.Tables[name].Definition)
+	}
+	for _, name := range sortedKeys(to.Tables) {
+		old, table := from.Tables[name], to.Tables[name]
+		for _, field := range sortedKeys(table.Fields) {
+			statements = appendDefinition(statements, old.Fields[field].Definition, table.Fields[field].Definition)
+		}
+		for _, index := range sortedKeys(table.Indexes) {
+			statements = appendDefinition(statements, old.Indexes[index].Definition, table.Indexes[index].Definition)
+		}
+		for _, event := range sortedKeys(table.Events) {
+			statements = appendDefinition(statements, old.Events[event].Definition, table.Events[event].Definition)
+		}
+	}
+
+	return statements
+}
+
+// appendDefinition appends the statement replacing the definition old with definition, if they
+// differ. old is empty when there is no such definition yet.
+func appendDefinition(statements []string, old, definition string) []string {
+	switch {
+	case old == "":
+		return append(statements, definition)
+	case normalize(old) == normalize(definition):
+		return statements
+	}
+
+	words := definitionWords(definition)
+	return append(statements, strings.Join(append([]string{words[0], words[1], "OVERWRITE"}, words[2:]...), " "))
+}
+
+func normalize(definition string) string {
+	return strings.TrimSuffix(surrealdb.NormalizeSurrealQL(definition), ";")
+}
+
+func ident(name string) string {
+	return models.Table(name).SurrealString()
+}
@@ -0,0 +1,355 @@
+// Package surrealschema reads the schema of a SurrealDB database into Go structs, with INFO FOR
+// DB and INFO FOR TABLE, and computes the statements converging a schema to another.
+//
+// Definitions are compared as SurrealQL normalized with surrealdb.NormalizeSurrealQL. The server
+// adds the default clauses of a statement, such as PERMISSIONS, to the definitions it reports, so
+// the schemas compared are best both read with Inspect, for example by applying the target schema
+// to a scratch database:
+//
+//	current, err := surrealschema.Inspect(db)
+//	target, err := surrealschema.Inspect(scratch)
+//	for _, statement := range surrealschema.Diff(current, target) {
+//		fmt.Println(statement + ";")
+//	}
+package surrealschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Schema is the schema of a database.
+type Schema struct {
+	Tables    map[string]Table
+	Analyzers map[string]Analyzer
+}
+
+// Table is the definition of a table, along with the definitions of its fields, indexes and
+// events.
+type Table struct {
+	Name string
+	// Kind is NORMAL, RELATION or ANY, and empty for servers that do not report it.
+	Kind       string
+	Schemafull bool
+	Definition string
+
+	Fields  map[string]Field
+	Indexes map[string]Index
+	Events  map[string]Event
+}
+
+// Field is the definition of a field of a table.
+type Field struct {
+	// Name is the path of the field as written in SurrealQL, such as address.city or tags[*].
+	Name  string
+	Table string
+	// Type is the type of the field, such as option<string>, and empty when it has none.
+	Type       string
+	Definition string
+}
+
+// Index is the definition of an index of a table.
+type Index struct {
+	Name   string
+	Table  string
+	Fields []string
+	// Kind is UNIQUE, SEARCH, MTREE or HNSW, and empty for a regular index.
+	Kind       string
+	Definition string
+}
+
+// Event is the definition of an event of a table.
+type Event struct {
+	Name       string
+	Table      string
+	Definition string
+}
+
+// Analyzer is the definition of a full-text analyzer.
+type Analyzer struct {
+	Name       string
+	Tokenizers []string
+	Filters    []string
+	Definition string
+}
+
+type dbInfo struct {
+	Tables    map[string]string `json:"tables"`
+	Analyzers map[string]string `json:"analyzers"`
+}
+
+type tableInfo struct {
+	Fields  map[string]string `json:"fields"`
+	Indexes map[string]string `json:"indexes"`
+	Events  map[string]string `json:"events"`
+}
+
+// Inspect reads the schema of the database selected on db.
+func Inspect(db *surrealdb.DB) (*Schema, error) {
+	infos, err := surrealdb.QueryAll[dbInfo](db, "INFO FOR DB", nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) != 1 {
+		return nil, fmt.Errorf("INFO FOR DB returned %d results", len(infos))
+	}
+	info := infos[0]
+
+	schema := &Schema{Tables: map[string]Table{}, Analyzers: map[string]Analyzer{}}
+	for _, definition := range info.Analyzers {
+		analyzer, err := parseAnalyzer(definition)
+		if err != nil {
+			return nil, err
+		}
+		schema.Analyzers[analyzer.Name] = analyzer
+	}
+
+	names := sortedKeys(info.Tables)
+	if len(names) == 0 {
+		return schema, nil
+	}
+
+	statements := make([]string, len(names))
+	for i, name := range names {
+		statements[i] = "INFO FOR TABLE " + models.Table(name).SurrealString()
+	}
+	tables, err := surrealdb.QueryAll[tableInfo](db, strings.Join(statements, ";\n"), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) != len(names) {
+		return nil, fmt.Errorf("INFO FOR TABLE returned %d results for %d tables", len(tables), len(names))
+	}
+
+	for i, name := range names {
+		table, err := parseTable(info.Tables[name], tables[i])
+		if err != nil {
+			return nil, err
+		}
+		schema.Tables[table.Name] = table
+	}
+	return schema, nil
+}
+
+// Statements returns the statements defining the schema, in an order they can be run in.
+func (s *Schema) Statements() []string {
+	return Diff(&Schema{}, s)
+}
+
+func parseTable(definition string, info tableInfo) (Table, error) {
+	words := definitionWords(definition)
+	if len(words) < 3 {
+		return Table{}, fmt.Errorf("cannot parse table definition %q", definition)
+	}
+
+	table := Table{
+		Name:       unescape(words[2]),
+		Definition: definition,
+		Fields:     map[string]Field{},
+		Indexes:    map[string]Index{},
+		Events:     map[string]Event{},
+	}
+	for i, word := range words {
+		switch {
+		case strings.EqualFold(word, "SCHEMAFULL"):
+			table.Schemafull = true
+		case strings.EqualFold(word, "TYPE") && i+1 < len(words):
+			table.Kind = strings.ToUpper(words[i+1])
+		}
+	}
+
+	for _, definition := range info.Fields {
+		words := definitionWords(definition)
+		name, on, ok := nameOn(words)
+		if !ok {
+			return Table{}, fmt.Errorf("cannot parse field definition %q", definition)
+		}
+		table.Fields[name] = Field{
+			Name:       name,
+			Table:      on,
+			Type:       strings.Join(clause(words, "TYPE"), " "),
+			Definition: definition,
+		}
+	}
+
+	for _, definition := range info.Indexes {
+		words := definitionWords(definition)
+		name, on, ok := nameOn(words)
+		if !ok {
+			return Table{}, fmt.Errorf("cannot parse index definition %q", definition)
+		}
+		index := Index{Name: unescape(name), Table: on, Definition: definition}
+		fields := clause(words, "FIELDS")
+		if fields == nil {
+			fields = clause(words, "COLUMNS")
+		}
+		for _, field := range strings.Split(strings.Join(fields, ""), ",") {
+			if field != "" {
+				index.Fields = append(index.Fields, field)
+			}
+		}
+		for _, word := range words {
+			switch kind := strings.ToUpper(word); kind {
+			case "UNIQUE", "SEARCH", "MTREE", "HNSW":
+				index.Kind = kind
+			}
+		}
+		table.Indexes[index.Name] = index
+	}
+
+	for _, definition := range info.Events {
+		name, on, ok := nameOn(definitionWords(definition))
+		if !ok {
+			return Table{}, fmt.Errorf("cannot parse event definition %q", definition)
+		}
+		table.Events[unescape(name)] = Event{Name: unescape(name), Table: on, Definition: definition}
+	}
+
+	return table, nil
+}
+
+func parseAnalyzer(definition string) (Analyzer, error) {
+	words := definitionWords(definition)
+	if len(words) < 3 {
+		return Analyzer{}, fmt.Errorf("cannot parse analyzer definition %q", definition)
+	}
+
+	analyzer := Analyzer{Name: unescape(words[2]), Definition: definition}
+	for _, part := range []struct {
+		keyword   string
+		functions *[]string
+	}{{"TOKENIZERS", &analyzer.Tokenizers}, {"FILTERS", &analyzer.Filters}} {
+		for _, f := range splitTopLevel(strings.Join(clause(words, part.keyword), ""), ',') {
+			*part.functions = append(*part.functions, strings.ToLower(f))
+		}
+	}
+	return analyzer, nil
+}
+
+// clauseKeywords end the clauses read by clause.
+var clauseKeywords = map[string]bool{
+	"ASSERT": true, "BM25": true, "COLUMNS": true, "COMMENT": true, "CONCURRENTLY": true, "DEFAULT": true,
+	"DIMENSION": true, "DISTANCE": true, "FIELDS": true, "FILTERS": true, "FLEXIBLE": true,
+	"FUNCTION": true, "HIGHLIGHTS": true, "HNSW": true, "MTREE": true, "PERMISSIONS": true,
+	"READONLY": true, "REFERENCE": true, "SEARCH": true, "TOKENIZERS": true, "TYPE": true,
+	"UNIQUE": true, "VALUE": true,
+}
+
+// clause returns the words following keyword in a definition, up to the next clause, or nil when
+// the definition has no such clause.
+func clause(words []string, keyword string) []string {
+	for i, word := range words {
+		if !strings.EqualFold(word, keyword) {
+			continue
+		}
+		end := i + 1
+		for end < len(words) && !clauseKeywords[strings.ToUpper(words[end])] {
+			end++
+		}
+		return words[i+1 : end]
+	}
+	return nil
+}
+
+// nameOn returns the name and table of a DEFINE ... name ON [TABLE] table statement.
+func nameOn(words []string) (name, table string, ok bool) {
+	if len(words) < 5 || !strings.EqualFold(words[3], "ON") {
+		return "", "", false
+	}
+	table = words[4]
+	if strings.EqualFold(table, "TABLE") && len(words) > 5 {
+		table = words[5]
+	}
+	return words[2], unescape(table), true
+}
+
+// definitionWords returns the words of a DEFINE statement, without the OVERWRITE or IF NOT EXISTS
+// clauses, so that the name of the definition is its third word.
+func definitionWords(definition string) []string {
+	words := splitTopLevel(surrealdb.NormalizeSurrealQL(definition), ' ')
+	if n := len(words); n > 0 {
+		words[n-1] = strings.TrimSuffix(words[n-1], ";")
+	}
+	if len(words) > 3 && strings.EqualFold(words[2], "OVERWRITE") {
+		return append(words[:2:2], words[3:]...)
+	}
+	if len(words) > 5 && strings.EqualFold(words[2], "IF") && strings.EqualFold(words[3], "NOT") && strings.EqualFold(words[4], "EXISTS") {
+		return append(words[:2:2], words[5:]...)
+	}
+	return words
+}
+
+// splitTopLevel splits s on sep, outside of strings, escaped identifiers and brackets.
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+	var b strings.Builder
+	flush := func() {
+		if part := strings.TrimSpace(b.String()); part != "" {
+			parts = append(parts, part)
+		}
+		b.Reset()
+	}
+
+	// angles counts the brackets of types such as option<string>, as opposed to comparisons
+	depth, angles := 0, 0
+	var closing, previous rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case closing != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == closing:
+				closing = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			closing = r
+		case r == '⟨':
+			closing = '⟩'
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == '<' && unicode.IsLetter(previous):
+			angles++
+		case r == '>' && angles > 0:
+			angles--
+		case depth <= 0 && angles == 0 && r == sep:
+			flush()
+			previous = r
+			continue
+		}
+		b.WriteRune(r)
+		previous = r
+	}
+	flush()
+	return parts
+}
+
+// unescape returns an identifier without the ⟨⟩ or backticks escaping it.
+func unescape(ident string) string {
+	if strings.HasPrefix(ident, "⟨") && strings.HasSuffix(ident, "⟩") {
+		return strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(ident, "⟨"), "⟩"), `\⟩`, "⟩")
+	}
+	if len(ident) > 1 && ident[0] == '`' && ident[len(ident)-1] == '`' {
+		return strings.ReplaceAll(ident[1:len(ident)-1], "\\`", "`")
+	}
+	return ident
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
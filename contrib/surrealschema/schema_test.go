@@ -0,0 +1,123 @@
+package surrealschema
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestInspect(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		var statements []interface{}
+		ok := func(result interface{}) {
+			statements = append(statements, map[string]interface{}{"status": "OK", "time": "1ms", "result": result})
+		}
+		switch sql := req.Params[0].(string); sql {
+		case "INFO FOR DB":
+			ok(map[string]interface{}{
+				"analyzers": map[string]interface{}{
+					"simple": "DEFINE ANALYZER simple TOKENIZERS BLANK,CLASS FILTERS LOWERCASE,SNOWBALL(ENGLISH)",
+				},
+				"tables": map[string]interface{}{
+					"person": "DEFINE TABLE person TYPE NORMAL SCHEMAFULL PERMISSIONS NONE",
+					"likes":  "DEFINE TABLE likes TYPE RELATION IN person OUT person SCHEMALESS PERMISSIONS NONE",
+				},
+			})
+		case "INFO FOR TABLE likes;\nINFO FOR TABLE person":
+			ok(map[string]interface{}{"fields": map[string]interface{}{}, "indexes": map[string]interface{}{}, "events": map[string]interface{}{}})
+			ok(map[string]interface{}{
+				"fields": map[string]interface{}{
+					"email":   "DEFINE FIELD email ON person TYPE string ASSERT string::is::email($value) PERMISSIONS FULL",
+					"friends": "DEFINE FIELD friends ON person TYPE option<array<record<person | org>>> DEFAULT [] PERMISSIONS FULL",
+				},
+				"indexes": map[string]interface{}{
+					"email": "DEFINE INDEX email ON person FIELDS email UNIQUE",
+					"bio":   "DEFINE INDEX bio ON person FIELDS bio SEARCH ANALYZER simple BM25(1.2,0.75) HIGHLIGHTS",
+				},
+				"events": map[string]interface{}{
+					"audit": "DEFINE EVENT audit ON person WHEN $event = 'UPDATE' THEN { CREATE log SET at = time::now(); }",
+				},
+			})
+		default:
+			t.Errorf("unexpected query %q", sql)
+		}
+		return statements, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	schema, err := Inspect(db)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"blank", "class"}, schema.Analyzers["simple"].Tokenizers)
+	assert.Equal(t, []string{"lowercase", "snowball(english)"}, schema.Analyzers["simple"].Filters)
+
+	person := schema.Tables["person"]
+	assert.True(t, person.Schemafull)
+	assert.Equal(t, "NORMAL", person.Kind)
+	assert.Equal(t, "RELATION", schema.Tables["likes"].Kind)
+	assert.False(t, schema.Tables["likes"].Schemafull)
+
+	assert.Equal(t, "string", person.Fields["email"].Type)
+	assert.Equal(t, "option<array<record<person | org>>>", person.Fields["friends"].Type)
+	assert.Equal(t, "person", person.Fields["friends"].Table)
+	assert.Equal(t, Index{
+		Name:       "email",
+		Table:      "person",
+		Fields:     []string{"email"},
+		Kind:       "UNIQUE",
+		Definition: "DEFINE INDEX email ON person FIELDS email UNIQUE",
+	}, person.Indexes["email"])
+	assert.Equal(t, "SEARCH", person.Indexes["bio"].Kind)
+	assert.Equal(t, "person", person.Events["audit"].Table)
+
+	statements := schema.Statements()
+	require.Len(t, statements, 8)
+	assert.True(t, strings.HasPrefix(statements[0], "DEFINE ANALYZER simple"), "analyzers are defined before the indexes using them")
+	assert.True(t, strings.HasPrefix(statements[1], "DEFINE TABLE likes"))
+	assert.True(t, strings.HasPrefix(statements[2], "DEFINE TABLE person"))
+}
+
+func TestDiff(t *testing.T) {
+	table := func(definition string, fields, indexes map[string]string) Table {
+		parsed, err := parseTable(definition, tableInfo{Fields: fields, Indexes: indexes})
+		require.NoError(t, err)
+		return parsed
+	}
+
+	from := &Schema{Tables: map[string]Table{
+		"person": table("DEFINE TABLE person SCHEMALESS", map[string]string{
+			"name":         "DEFINE FIELD name ON person TYPE string",
+			"address":      "DEFINE FIELD address ON person TYPE object",
+			"address.city": "DEFINE FIELD address.city ON person TYPE string",
+		}, map[string]string{
+			"name": "DEFINE INDEX name ON person FIELDS name",
+		}),
+		"legacy": table("DEFINE TABLE legacy SCHEMALESS", nil, nil),
+	}}
+	to := &Schema{Tables: map[string]Table{
+		"person": table("DEFINE TABLE person SCHEMAFULL", map[string]string{
+			"name":  "define field name on person TYPE string",
+			"email": "DEFINE FIELD email ON person TYPE string",
+		}, nil),
+		"first name": table("DEFINE TABLE ⟨first name⟩ SCHEMALESS", nil, nil),
+	}}
+
+	assert.Equal(t, []string{
+		"REMOVE TABLE legacy",
+		"REMOVE INDEX name ON person",
+		"REMOVE FIELD address.city ON person",
+		"REMOVE FIELD address ON person",
+		"DEFINE TABLE ⟨first name⟩ SCHEMALESS",
+		"DEFINE TABLE OVERWRITE person SCHEMAFULL",
+		"DEFINE FIELD email ON person TYPE string",
+	}, Diff(from, to))
+	assert.Empty(t, Diff(to, to))
+}
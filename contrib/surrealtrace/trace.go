@@ -0,0 +1,67 @@
+// Package surrealtrace maps connection.Hooks to the spans of APM agents, starting a span per
+// request tagged with the method, table and query of the request. The agent is plugged in with a
+// StartFunc, so that this package does not depend on any agent. The contrib/surrealdatadog and
+// contrib/surrealnewrelic modules provide the StartFunc of Datadog and New Relic, and
+// contrib/otelsurreal instruments the hooks with OpenTelemetry.
+//
+//	db, err := surrealdb.Connect(ctx, "ws://localhost:8000", surrealdb.WithHooks(surrealtrace.Hooks(start)))
+//
+// The hooks are called for every message sent on the connection, so a request retried by the DB
+// is traced once per attempt.
+package surrealtrace
+
+import (
+	"context"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+)
+
+// The tags of the spans, named after the OpenTelemetry database conventions recognized by APM
+// agents.
+const (
+	TagSystem    = "db.system"
+	TagOperation = "db.operation"
+	TagTable     = "db.sql.table"
+	TagStatement = "db.statement"
+	TagTarget    = "surrealdb.target"
+)
+
+// StartFunc starts a span named operation with tags, and returns the context carrying it along
+// with the function ending it with the error of the request, nil on success.
+type StartFunc func(ctx context.Context, operation string, tags map[string]string) (context.Context, func(err error))
+
+type endKey struct{}
+
+// Hooks returns the hooks starting a span named surrealdb.<method> for every request with start.
+func Hooks(start StartFunc) connection.Hooks {
+	return connection.Hooks{
+		OnRequestStart: func(ctx context.Context, req connection.RequestStart) context.Context {
+			ctx, end := start(ctx, "surrealdb."+req.Method, Tags(req))
+			return context.WithValue(ctx, endKey{}, end)
+		},
+		OnRequestEnd: func(ctx context.Context, req connection.RequestEnd) {
+			if end, ok := ctx.Value(endKey{}).(func(error)); ok {
+				end(req.Err)
+			}
+		},
+	}
+}
+
+// Tags returns the tags of the span of a request. The table, statement and target are left out
+// when empty.
+func Tags(req connection.RequestStart) map[string]string {
+	tags := map[string]string{
+		TagSystem:    "surrealdb",
+		TagOperation: req.Method,
+	}
+	for name, value := range map[string]string{
+		TagTable:     req.Table(),
+		TagStatement: req.Statement(),
+		TagTarget:    req.Target(),
+	} {
+		if value != "" {
+			tags[name] = value
+		}
+	}
+	return tags
+}
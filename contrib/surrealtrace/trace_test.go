@@ -0,0 +1,82 @@
+package surrealtrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+type span struct {
+	operation string
+	tags      map[string]string
+	ended     bool
+	err       error
+}
+
+func TestHooks(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		if req.Method == "delete" {
+			return nil, &connection.RPCError{Code: -32000, Message: "not allowed"}
+		}
+		return []interface{}{}, nil
+	})
+
+	var spans []*span
+	type spanKey struct{}
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithHooks(Hooks(func(ctx context.Context, operation string, tags map[string]string) (context.Context, func(error)) {
+			s := &span{operation: operation, tags: tags}
+			spans = append(spans, s)
+			return context.WithValue(ctx, spanKey{}, s), func(err error) {
+				s.ended, s.err = true, err
+			}
+		})),
+	)
+	require.NoError(t, err)
+
+	_, err = surrealdb.Select[[]interface{}](db, models.NewRecordID("user", "john"))
+	require.NoError(t, err)
+	_, err = surrealdb.Query[interface{}](db, "SELECT * FROM user", nil)
+	require.NoError(t, err)
+	_, err = surrealdb.Delete[interface{}](db, models.Table("user"))
+	require.Error(t, err)
+
+	var selects, queries, deletes []*span
+	for _, s := range spans {
+		switch s.operation {
+		case "surrealdb.select":
+			selects = append(selects, s)
+		case "surrealdb.query":
+			queries = append(queries, s)
+		case "surrealdb.delete":
+			deletes = append(deletes, s)
+		}
+	}
+	require.Len(t, selects, 1)
+	assert.Equal(t, map[string]string{
+		TagSystem:    "surrealdb",
+		TagOperation: "select",
+		TagTable:     "user",
+		TagTarget:    "user:john",
+	}, selects[0].tags)
+	assert.True(t, selects[0].ended)
+	assert.NoError(t, selects[0].err)
+
+	require.Len(t, queries, 1)
+	assert.Equal(t, "SELECT * FROM user", queries[0].tags[TagStatement])
+	assert.NotContains(t, queries[0].tags, TagTable)
+
+	require.Len(t, deletes, 1)
+	assert.True(t, deletes[0].ended)
+	var rpcErr *connection.RPCError
+	assert.True(t, errors.As(deletes[0].err, &rpcErr))
+}
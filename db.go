@@ -2,13 +2,17 @@ package surrealdb
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log/slog"
 	"net/url"
-	"os"
+	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/internal/codec"
 
 	"github.com/surrealdb/surrealdb.go/pkg/connection"
 	"github.com/surrealdb/surrealdb.go/pkg/constants"
@@ -22,33 +26,173 @@ type VersionData struct {
 	Timestamp string `json:"timestamp"`
 }
 
-// DB is a client for the SurrealDB database that holds the connection.
+// DB is a client for the SurrealDB database that holds the connection. The handles returned by
+// WithContext and WithRetryPolicy share the connection, session and settings of the DB they were
+// derived from.
 type DB struct {
+	*client
+
+	// ctx bounds the requests made through this handle only
 	ctx context.Context
+	// retryPolicy retries the requests made through this handle only, see WithRetryPolicy
+	retryPolicy *RetryPolicy
+}
+
+// client is the state shared by a DB and the handles derived from it with WithContext.
+type client struct {
 	con connection.Connection
+	// marshaler is the codec of the requests, which prepared queries check before sending their
+	// query encoded once
+	marshaler codec.Marshaler
+	logger    logger.Logger
+	auditHook AuditHook
+
+	sessionLock sync.RWMutex
+	session     SessionState
+
+	// indexAdvice records the tables and fields ListModifiedIDs already checked for an index
+	indexAdvice sync.Map
+
+	clockSkew           atomic.Int64
+	compensateClockSkew bool
+
+	stats *stats
+
+	propagateDeadline bool
+
+	// redactedVars are the variables whose values are redacted in the request logs
+	redactedVars map[string]bool
+
+	// methodPolicies are the timeouts and retry policies set by RPC method
+	methodPolicies map[string]MethodPolicy
+
+	// protocol maps the methods of the client to the RPC methods of the server
+	protocol protocol
+
+	// defaultVars are bound in every query, and replaced rather than modified when changed
+	defaultVarsLock sync.RWMutex
+	defaultVars     map[string]interface{}
+
+	// maxBatchSize is the number of record ids sent in one statement by SelectByIDs and DeleteByIDs
+	maxBatchSize int
+
+	// livePollInterval is how often the live queries of the http engine poll their records
+	livePollInterval time.Duration
+
+	// deprecationWarnings records the deprecated APIs WarnDeprecated already logged a warning for
+	deprecationWarnings sync.Map
+
+	// reconnect opens another connection to the same server with the same options, without
+	// signing in nor selecting a namespace
+	reconnect func() (*DB, error)
 }
 
 // New creates a new SurrealDB client.
+//
+// The connection url may carry options as query parameters:
+//   - codec: the wire format, only "cbor" is supported
+//   - timeout: how long to wait for a response, e.g. "5s"
+//   - pool: the number of pooled connections, http and https only
+//   - ns and db: the namespace and database to use once connected
 func New(connectionURL string) (*DB, error) {
+	return Connect(context.Background(), connectionURL)
+}
+
+// Connect creates a new SurrealDB client, then signs in and selects the namespace and database
+// as configured by opts, so that the client is ready to use. ctx bounds the connection attempts
+// only: once connected, the client is not affected by it. Use WithContext to bound requests.
+//
+//	db, err := surrealdb.Connect(ctx, "ws://localhost:8000",
+//		surrealdb.WithAuth(&surrealdb.Auth{Username: "root", Password: "root"}),
+//		surrealdb.WithNamespace("test", "test"),
+//	)
+func Connect(ctx context.Context, connectionURL string, opts ...Option) (*DB, error) {
+	cfg := newConfig()
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+
 	u, err := url.ParseRequestURI(connectionURL)
 	if err != nil {
 		return nil, err
 	}
 
+	endpointOpts, err := parseEndpointOptions(u.Query())
+	if err != nil {
+		return nil, err
+	}
+	if cfg.namespace == "" {
+		cfg.namespace = endpointOpts.namespace
+		cfg.database = endpointOpts.database
+	}
+
+	for attempt := 1; ; attempt++ {
+		db, err := connect(u, endpointOpts, cfg)
+		if err == nil {
+			db.stats.connectRetries = attempt - 1
+			return db, nil
+		}
+		if attempt >= cfg.connectAttempts {
+			return nil, err
+		}
+
+		cfg.logger.Warn("connection attempt failed", "attempt", attempt, "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(cfg.connectDelay):
+		}
+	}
+}
+
+func connect(u *url.URL, opts *endpointOptions, cfg *config) (*DB, error) {
 	scheme := u.Scheme
+	st := &stats{}
 
 	newParams := connection.NewConnectionParams{
-		Marshaler:   models.CborMarshaler{},
-		Unmarshaler: models.CborUnmarshaler{},
+		Marshaler:   cfg.marshaler,
+		Unmarshaler: cfg.unmarshaler,
 		BaseURL:     fmt.Sprintf("%s://%s", u.Scheme, u.Host),
-		Logger:      logger.New(slog.NewTextHandler(os.Stdout, nil)),
+		Logger:      cfg.logger,
+		Hooks:       st.hooks(cfg.hooks),
 	}
 
 	var con connection.Connection
 	if scheme == "http" || scheme == "https" {
-		con = connection.NewHTTPConnection(newParams)
+		if cfg.notificationBuffer != nil {
+			return nil, fmt.Errorf("notification buffer is only supported by the ws engine")
+		}
+		httpCon := connection.NewHTTPConnection(newParams)
+		if opts.timeout > 0 {
+			httpCon.SetTimeout(opts.timeout)
+		}
+		if opts.poolSize > 0 {
+			httpCon.SetPoolSize(opts.poolSize)
+		}
+		if cfg.maxConcurrentStreams > 0 {
+			httpCon.SetMaxConcurrentStreams(cfg.maxConcurrentStreams)
+		}
+		if cfg.warmConnections > 0 {
+			httpCon.SetWarmPool(cfg.warmConnections)
+		}
+		con = httpCon
 	} else if scheme == "ws" || scheme == "wss" {
-		con = connection.NewWebSocketConnection(newParams)
+		if !websocketSupported {
+			return nil, fmt.Errorf("the ws engine is not supported under js/wasm, connect with http or https")
+		}
+		if opts.poolSize > 0 || cfg.maxConcurrentStreams > 0 || cfg.warmConnections > 0 {
+			return nil, fmt.Errorf("pool, max concurrent streams and warm pool are only supported by the http engine")
+		}
+		wsCon := connection.NewWebSocketConnection(newParams)
+		if opts.timeout > 0 {
+			wsCon.SetTimeOut(opts.timeout)
+		}
+		if cfg.notificationBuffer != nil {
+			wsCon.SetNotificationBuffer(*cfg.notificationBuffer)
+		}
+		con = wsCon
 	} else if scheme == "memory" || scheme == "mem" || scheme == "surrealkv" {
 		return nil, fmt.Errorf("embedded database not enabled")
 		// con = connection.NewEmbeddedConnection(newParams)
@@ -56,22 +200,76 @@ func New(connectionURL string) (*DB, error) {
 		return nil, fmt.Errorf("invalid connection url")
 	}
 
-	err = con.Connect()
+	err := con.Connect()
 	if err != nil {
 		return nil, err
 	}
 
-	return &DB{con: con}, nil
+	db := &DB{ctx: context.Background(), client: &client{
+		con:                 con,
+		marshaler:           cfg.marshaler,
+		logger:              cfg.logger,
+		auditHook:           cfg.auditHook,
+		compensateClockSkew: cfg.compensateClockSkew,
+		propagateDeadline:   cfg.propagateDeadline,
+		redactedVars:        cfg.redactedVars,
+		defaultVars:         cfg.defaultVars,
+		maxBatchSize:        cfg.maxBatchSize,
+		livePollInterval:    cfg.livePollInterval,
+		methodPolicies:      cfg.methodPolicies,
+		protocol:            cfg.protocol,
+		stats:               st,
+	}}
+	db.reconnect = func() (*DB, error) {
+		unauthenticated := *cfg
+		unauthenticated.auth, unauthenticated.token = nil, ""
+		unauthenticated.namespace, unauthenticated.database = "", ""
+		unauthenticated.compensateClockSkew = false
+		return connect(u, opts, &unauthenticated)
+	}
+
+	if cfg.namespace != "" {
+		if err := db.Use(cfg.namespace, cfg.database); err != nil {
+			_ = con.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.auth != nil {
+		if _, err := db.SignIn(cfg.auth); err != nil {
+			_ = con.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.token != "" {
+		if err := db.Authenticate(cfg.token); err != nil {
+			_ = con.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.compensateClockSkew {
+		if _, err := db.MeasureClockSkew(); err != nil {
+			_ = con.Close()
+			return nil, err
+		}
+	}
+
+	return db, nil
 }
 
 // --------------------------------------------------
 // Public methods
 // --------------------------------------------------
 
-// WithContext
+// WithContext returns a handle of db whose requests are bounded by ctx: the wait for a response
+// stops once ctx is done, and its deadline is sent to the server along with queries when the DB
+// was created with WithDeadlinePropagation. The handle shares the connection and session of db,
+// whose own context is left unchanged, so that concurrent requests can each have their own
+// deadline. The handle keeps the retry policy of db.
 func (db *DB) WithContext(ctx context.Context) *DB {
-	db.ctx = ctx
-	return db
+	return &DB{client: db.client, ctx: ctx, retryPolicy: db.retryPolicy}
 }
 
 // Close closes the underlying WebSocket connection.
@@ -81,25 +279,33 @@ func (db *DB) Close() error {
 
 // Use is a method to select the namespace and table to use.
 func (db *DB) Use(ns, database string) error {
-	return db.con.Use(ns, database)
+	if err := db.con.Use(ns, database); err != nil {
+		return err
+	}
+	db.updateSession(func(s *SessionState) {
+		s.Namespace = ns
+		s.Database = database
+	})
+	return nil
 }
 
 func (db *DB) Info() (map[string]interface{}, error) {
 	var info connection.RPCResponse[map[string]interface{}]
-	err := db.con.Send(&info, "info")
+	err := db.send(&info, "info")
 	return *info.Result, err
 }
 
 // SignUp is a helper method for signing up a new user.
 func (db *DB) SignUp(authData *Auth) (string, error) {
 	var token connection.RPCResponse[string]
-	if err := db.con.Send(&token, "signup", authData); err != nil {
+	if err := db.send(&token, "signup", authData); err != nil {
 		return "", err
 	}
 
-	if err := db.con.Let(constants.AuthTokenKey, token.Result); err != nil {
+	if err := db.con.Let(constants.AuthTokenKey, *token.Result); err != nil {
 		return "", err
 	}
+	db.setAuthentication(*token.Result, authData.Username)
 
 	return *token.Result, nil
 }
@@ -107,52 +313,65 @@ func (db *DB) SignUp(authData *Auth) (string, error) {
 // SignIn is a helper method for signing in a user.
 func (db *DB) SignIn(authData *Auth) (string, error) {
 	var token connection.RPCResponse[string]
-	if err := db.con.Send(&token, "signin", authData); err != nil {
+	if err := db.send(&token, "signin", authData); err != nil {
 		return "", err
 	}
 
-	if err := db.con.Let(constants.AuthTokenKey, token.Result); err != nil {
+	if err := db.con.Let(constants.AuthTokenKey, *token.Result); err != nil {
 		return "", err
 	}
+	db.setAuthentication(*token.Result, authData.Username)
 
 	return *token.Result, nil
 }
 
 func (db *DB) Invalidate() error {
-	if err := db.con.Send(nil, "invalidate"); err != nil {
+	if err := db.send(nil, "invalidate"); err != nil {
 		return err
 	}
 
 	if err := db.con.Unset(constants.AuthTokenKey); err != nil {
 		return err
 	}
+	db.setAuthentication("", "")
 
 	return nil
 }
 
 func (db *DB) Authenticate(token string) error {
-	if err := db.con.Send(nil, "authenticate", token); err != nil {
+	if err := db.send(nil, "authenticate", token); err != nil {
 		return err
 	}
 
 	if err := db.con.Let(constants.AuthTokenKey, token); err != nil {
 		return err
 	}
+	db.setAuthentication(token, "")
 
 	return nil
 }
 
 func (db *DB) Let(key string, val interface{}) error {
-	return db.con.Let(key, val)
+	if err := db.con.Let(key, val); err != nil {
+		return err
+	}
+	db.setVariable(key, val)
+	return nil
 }
 
 func (db *DB) Unset(key string) error {
-	return db.con.Unset(key)
+	if err := db.con.Unset(key); err != nil {
+		return err
+	}
+	db.updateSession(func(s *SessionState) {
+		delete(s.Variables, key)
+	})
+	return nil
 }
 
 func (db *DB) Version() (*VersionData, error) {
 	var ver connection.RPCResponse[VersionData]
-	if err := db.con.Send(&ver, "version"); err != nil {
+	if err := db.send(&ver, "version"); err != nil {
 		return nil, err
 	}
 	return ver.Result, nil
@@ -173,22 +392,45 @@ func (db *DB) Send(res interface{}, method string, params ...interface{}) error
 		return fmt.Errorf("provided method is not allowed")
 	}
 
-	return db.con.Send(&res, method, params...)
+	return db.send(res, method, params...)
+}
+
+// DecodeResult decodes the raw result of a method called with Send into TResult, handling NONE,
+// NULL, record ids and the other SurrealDB types like the typed helpers do, including the decode
+// options of db. Failures are reported as a *connection.DecodeError.
+//
+//	var res connection.RPCResponse[cbor.RawMessage]
+//	err := db.Send(&res, "select", models.NewRecordID("user", "john"))
+//	user, err := surrealdb.DecodeResult[User](db, *res.Result)
+func DecodeResult[TResult any](db *DB, raw cbor.RawMessage) (*TResult, error) {
+	var result TResult
+	if err := connection.DecodeResult(db.con.GetUnmarshaler(), "send", raw, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
 }
 
 func (db *DB) LiveNotifications(liveQueryID string) (chan connection.Notification, error) {
 	return db.con.LiveNotifications(liveQueryID)
 }
 
+// RawNotifications subscribes to the undecoded envelope of every live query notification on the
+// connection, for callers that dispatch notifications themselves. Call the returned function to
+// unsubscribe. See connection.BaseConnection.RawNotifications for the ordering guarantees.
+func (db *DB) RawNotifications() (chan connection.RawNotification, func()) {
+	return db.con.RawNotifications()
+}
+
 //-------------------------------------------------------------------------------------------------------------------//
 
 func Kill(db *DB, id string) error {
-	return db.con.Send(nil, "kill", id)
+	return db.send(nil, "kill", id)
 }
 
 func Live(db *DB, table models.Table, diff bool) (*models.UUID, error) {
 	var res connection.RPCResponse[models.UUID]
-	if err := db.con.Send(&res, "live", table, diff); err != nil {
+	if err := db.send(&res, "live", table, diff); err != nil {
 		return nil, err
 	}
 
@@ -197,7 +439,26 @@ func Live(db *DB, table models.Table, diff bool) (*models.UUID, error) {
 
 func Query[TResult any](db *DB, sql string, vars map[string]interface{}) (*[]QueryResult[TResult], error) {
 	var res connection.RPCResponse[[]QueryResult[TResult]]
-	if err := db.con.Send(&res, "query", sql, vars); err != nil {
+	if err := db.send(&res, "query", sql, vars); err != nil {
+		return nil, err
+	}
+
+	return res.Result, nil
+}
+
+// Run calls the function name, a built-in function such as string::lowercase or a function
+// defined with DEFINE FUNCTION such as fn::compute_score, with args, and returns its result
+// decoded into TResult. Run needs SurrealDB 1.5 or newer.
+//
+//	score, err := surrealdb.Run[float64](db, "fn::compute_score", models.NewRecordID("user", "john"), 0.5)
+func Run[TResult any](db *DB, name string, args ...interface{}) (*TResult, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	var res connection.RPCResponse[TResult]
+	// the version is only used by machine learning models, called as ml::name<version>
+	if err := db.send(&res, "run", name, nil, args); err != nil {
 		return nil, err
 	}
 
@@ -206,35 +467,185 @@ func Query[TResult any](db *DB, sql string, vars map[string]interface{}) (*[]Que
 
 func Create[TResult any, TWhat TableOrRecord](db *DB, what TWhat, data interface{}) (*TResult, error) {
 	var res connection.RPCResponse[TResult]
-	if err := db.con.Send(&res, "create", what, data); err != nil {
+	if err := db.send(&res, "create", what, data); err != nil {
 		return nil, err
 	}
 
 	return res.Result, nil
 }
 
-func Select[TResult any, TWhat TableOrRecord](db *DB, what TWhat) (*TResult, error) {
+// CreateOrSkip creates a record like Create, unless it already exists: the record is then left
+// unchanged, and CreateOrSkip returns a nil record and false rather than an error, for migration
+// and sync code to tell both outcomes apart without treating the error as control flow.
+func CreateOrSkip[TResult any, TWhat TableOrRecord](db *DB, what TWhat, data interface{}) (*TResult, bool, error) {
+	record, err := Create[TResult](db, what, data)
+	if errors.Is(err, constants.ErrAlreadyExists) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return record, true, nil
+}
+
+// Select returns the records of a table, or a record. Several tables or records can be selected
+// at once by passing a []models.Table or []models.RecordID, in which case the records of all
+// of them are returned in a single slice, within a single round trip.
+func Select[TResult any, TWhat TablesOrRecords](db *DB, what TWhat) (*TResult, error) {
+	if isMultiTarget(what) {
+		return querySingle[TResult](db, "SELECT * FROM $what", map[string]interface{}{"what": what})
+	}
+
 	var res connection.RPCResponse[TResult]
 
-	if err := db.con.Send(&res, "select", what); err != nil {
+	if err := db.send(&res, "select", what); err != nil {
 		return nil, err
 	}
 
 	return res.Result, nil
 }
 
+// SelectByTable returns the records of several tables grouped by table, within a single round trip.
+func SelectByTable[TResult any](db *DB, tables ...models.Table) (map[models.Table][]TResult, error) {
+	statements := make([]string, len(tables))
+	vars := make(map[string]interface{}, len(tables))
+	for i, table := range tables {
+		statements[i] = fmt.Sprintf("SELECT * FROM $table%d", i)
+		vars[fmt.Sprintf("table%d", i)] = table
+	}
+
+	var res connection.RPCResponse[[]QueryResult[cbor.RawMessage]]
+	if err := db.send(&res, "query", strings.Join(statements, "; "), vars); err != nil {
+		return nil, err
+	}
+	if res.Result == nil || len(*res.Result) != len(tables) {
+		return nil, constants.InvalidResponse
+	}
+
+	byTable := make(map[models.Table][]TResult, len(tables))
+	for i, table := range tables {
+		records, err := decodeQueryResult[[]TResult](db, (*res.Result)[i])
+		if err != nil {
+			return nil, err
+		}
+		byTable[table] = *records
+	}
+
+	return byTable, nil
+}
+
+// SelectValue returns the values of field in the records of what, with SELECT VALUE, decoded
+// into TResult rather than into records holding the field. where, which may be nil, restricts the
+// records read.
+//
+//	names, err := surrealdb.SelectValue[string](db, models.Table("user"), "name", nil)
+func SelectValue[TResult any, TWhat TablesOrRecords](db *DB, what TWhat, field string, where *Condition) ([]TResult, error) {
+	escaped, err := models.EscapeFieldPath(field)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, vars := selectStatement("VALUE "+escaped, what, where)
+	values, err := querySingle[[]TResult](db, sql, vars)
+	if err != nil {
+		return nil, err
+	}
+	return *values, nil
+}
+
+// SelectFields returns the records of what with only the fields of TResult, a struct whose field
+// names are read from its tags like when decoding, so that wide records are not sent in full.
+// where, which may be nil, restricts the records read. Fields missing from a record are returned
+// as NONE, which decodes into pointers as nil with models.DecodeOptions.NoneAsNil.
+//
+//	type userName struct {
+//		ID   *models.RecordID `json:"id"`
+//		Name string           `json:"name"`
+//	}
+//	names, err := surrealdb.SelectFields[userName](db, models.Table("user"), nil)
+func SelectFields[TResult any, TWhat TablesOrRecords](db *DB, what TWhat, where *Condition) ([]TResult, error) {
+	names, err := models.FieldNames(reflect.TypeOf((*TResult)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%T has no fields to select", *new(TResult))
+	}
+
+	fields := make([]string, len(names))
+	for i, name := range names {
+		fields[i] = models.EscapeIdent(name)
+	}
+
+	sql, vars := selectStatement(strings.Join(fields, ", "), what, where)
+	records, err := querySingle[[]TResult](db, sql, vars)
+	if err != nil {
+		return nil, err
+	}
+	return *records, nil
+}
+
+// selectStatement returns a SELECT statement of projection from what, restricted by where when
+// it is not nil, along with its variables.
+func selectStatement[TWhat TablesOrRecords](projection string, what TWhat, where *Condition) (string, map[string]interface{}) {
+	vars := map[string]interface{}{}
+	sql := "SELECT " + projection + " FROM $select_what"
+	if where != nil {
+		sql += " WHERE " + where.SQL
+		for name, value := range where.Vars {
+			vars[name] = value
+		}
+	}
+	vars["select_what"] = queryTarget(what)
+	return sql, vars
+}
+
+// SelectOnly returns a record with SELECT * FROM ONLY, decoded into TResult rather than into a
+// slice holding it. It returns nil when the record does not exist.
+func SelectOnly[TResult any](db *DB, record models.RecordID) (*TResult, error) {
+	raw, err := querySingle[cbor.RawMessage](db, "SELECT * FROM ONLY $record", map[string]interface{}{"record": record})
+	if err != nil {
+		return nil, err
+	}
+	if isNoneOrNull(*raw) {
+		return nil, nil
+	}
+
+	var result TResult
+	if err := connection.DecodeResult(db.con.GetUnmarshaler(), "query", *raw, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// isNoneOrNull reports whether raw encodes NONE or NULL, which the CBOR library would otherwise
+// decode into a pointer to a zero value.
+func isNoneOrNull(raw cbor.RawMessage) bool {
+	var v interface{}
+	if err := (models.CborUnmarshaler{}).Unmarshal(raw, &v); err != nil {
+		return false
+	}
+	return v == nil || v == models.None
+}
+
 func Patch(db *DB, what interface{}, patches []PatchData) (*[]PatchData, error) {
 	var patchRes connection.RPCResponse[[]PatchData]
-	if err := db.con.Send(&patchRes, "patch", what, patches, true); err != nil {
+	if err := db.send(&patchRes, "patch", what, patches, true); err != nil {
 		return nil, err
 	}
 
 	return patchRes.Result, nil
 }
 
-func Delete[TResult any, TWhat TableOrRecord](db *DB, what TWhat) (*TResult, error) {
+// Delete deletes the records of a table, or a record, and returns them. Like Select, it accepts
+// a []models.Table or []models.RecordID to delete several tables or records at once.
+func Delete[TResult any, TWhat TablesOrRecords](db *DB, what TWhat) (*TResult, error) {
+	if isMultiTarget(what) {
+		return querySingle[TResult](db, "DELETE $what RETURN BEFORE", map[string]interface{}{"what": what})
+	}
+
 	var res connection.RPCResponse[TResult]
-	if err := db.con.Send(&res, "delete", what); err != nil {
+	if err := db.send(&res, "delete", what); err != nil {
 		return nil, err
 	}
 
@@ -243,7 +654,7 @@ func Delete[TResult any, TWhat TableOrRecord](db *DB, what TWhat) (*TResult, err
 
 func Upsert[TResult any, TWhat TableOrRecord](db *DB, what TWhat, data interface{}) (*TResult, error) {
 	var res connection.RPCResponse[TResult]
-	if err := db.con.Send(&res, "upsert", what, data); err != nil {
+	if err := db.send(&res, "upsert", what, data); err != nil {
 		return nil, err
 	}
 
@@ -253,7 +664,7 @@ func Upsert[TResult any, TWhat TableOrRecord](db *DB, what TWhat, data interface
 // Update a table or record in the database like a PUT request.
 func Update[TResult any, TWhat TableOrRecord](db *DB, what TWhat, data interface{}) (*TResult, error) {
 	var res connection.RPCResponse[TResult]
-	if err := db.con.Send(&res, "update", what, data); err != nil {
+	if err := db.send(&res, "update", what, data); err != nil {
 		return nil, err
 	}
 
@@ -263,26 +674,54 @@ func Update[TResult any, TWhat TableOrRecord](db *DB, what TWhat, data interface
 // Merge a table or record in the database like a PATCH request.
 func Merge[TResult any, TWhat TableOrRecord](db *DB, what TWhat, data interface{}) (*TResult, error) {
 	var res connection.RPCResponse[TResult]
-	if err := db.con.Send(&res, "merge", what, data); err != nil {
+	if err := db.send(&res, "merge", what, data); err != nil {
 		return nil, err
 	}
 
 	return res.Result, nil
 }
 
+// UpdateDiff updates a table or record like Update, but returns for each updated record the
+// JSON Patch operations describing what changed instead of the full record.
+func UpdateDiff[TWhat TableOrRecord](db *DB, what TWhat, data interface{}) (*[][]PatchData, error) {
+	return querySingle[[][]PatchData](db, "UPDATE $what CONTENT $data RETURN DIFF", map[string]interface{}{
+		"what": queryTarget(what),
+		"data": data,
+	})
+}
+
+// MergeDiff merges data into a table or record like Merge, but returns for each updated record
+// the JSON Patch operations describing what changed instead of the full record.
+func MergeDiff[TWhat TableOrRecord](db *DB, what TWhat, data interface{}) (*[][]PatchData, error) {
+	return querySingle[[][]PatchData](db, "UPDATE $what MERGE $data RETURN DIFF", map[string]interface{}{
+		"what": queryTarget(what),
+		"data": data,
+	})
+}
+
 // Insert a table or a row from the database like a POST request.
 func Insert[TResult any](db *DB, what models.Table, data interface{}) (*[]TResult, error) {
 	var res connection.RPCResponse[[]TResult]
-	if err := db.con.Send(&res, "insert", what, data); err != nil {
+	if err := db.send(&res, "insert", what, data); err != nil {
 		return nil, err
 	}
 
 	return res.Result, nil
 }
 
+// InsertIgnore inserts the records of data, a record or a slice of records, into a table like
+// Insert, with an INSERT IGNORE statement: the records whose id already exists are left unchanged
+// instead of failing the whole insert.
+func InsertIgnore[TResult any](db *DB, what models.Table, data interface{}) (*[]TResult, error) {
+	return querySingle[[]TResult](db, "INSERT IGNORE INTO $table $data", map[string]interface{}{
+		"table": what,
+		"data":  data,
+	})
+}
+
 func Relate(db *DB, rel *Relationship) error {
 	var res connection.RPCResponse[connection.ResponseID[models.RecordID]]
-	if err := db.con.Send(&res, "relate", rel.In, rel.Relation, rel.Out, rel.Data); err != nil {
+	if err := db.send(&res, "relate", rel.In, rel.Relation, rel.Out, rel.Data); err != nil {
 		return err
 	}
 
@@ -304,7 +743,7 @@ func InsertRelation(db *DB, relationship *Relationship) error {
 		rel[k] = v
 	}
 
-	if err := db.con.Send(&res, "insert_relation", relationship.Relation, rel); err != nil {
+	if err := db.send(&res, "insert_relation", relationship.Relation, rel); err != nil {
 		return err
 	}
 
@@ -312,6 +751,20 @@ func InsertRelation(db *DB, relationship *Relationship) error {
 	return nil
 }
 
+// InsertRelations inserts the edges of data, an edge or a slice of edges holding their in and out
+// fields, into the relation table with the insert_relation method, and returns them decoded into
+// TEdge. Unlike InsertRelation, it inserts any number of edges in a single request.
+//
+//	edges, err := surrealdb.InsertRelations[Follows](db, "follows", []Follows{{In: alice, Out: bob}, {In: bob, Out: alice}})
+func InsertRelations[TEdge any](db *DB, relation models.Table, data interface{}) (*[]TEdge, error) {
+	var res connection.RPCResponse[[]TEdge]
+	if err := db.send(&res, "insert_relation", relation, data); err != nil {
+		return nil, err
+	}
+
+	return res.Result, nil
+}
+
 func QueryRaw(db *DB, queries *[]QueryStmt) error {
 	preparedQuery := ""
 	parameters := map[string]interface{}{}
@@ -328,7 +781,7 @@ func QueryRaw(db *DB, queries *[]QueryStmt) error {
 	}
 
 	var res connection.RPCResponse[[]QueryResult[cbor.RawMessage]]
-	if err := db.con.Send(&res, "query", preparedQuery, parameters); err != nil {
+	if err := db.send(&res, "query", preparedQuery, parameters); err != nil {
 		return err
 	}
 
@@ -340,3 +793,142 @@ func QueryRaw(db *DB, queries *[]QueryStmt) error {
 
 	return nil
 }
+
+// send is the path taken by every request the client makes to the server.
+func (db *DB) send(res interface{}, method string, params ...interface{}) error {
+	params, err := db.withDefaultVars(method, params)
+	if err != nil {
+		return err
+	}
+	rpcMethod, err := db.protocol.rpcMethod(method)
+	if err != nil {
+		return err
+	}
+
+	retryPolicy, retryAnyMethod := db.retryPolicyFor(method)
+	retries := func(attempt int, err error) bool {
+		if retryAnyMethod {
+			return retryPolicy.retriesAfter(attempt, err)
+		}
+		return retryPolicy.retries(method, attempt, err)
+	}
+
+	start := time.Now()
+	db.stats.started()
+	err = db.sendOnce(db.ctx, res, method, rpcMethod, params)
+	for attempt := 1; retries(attempt, err); attempt++ {
+		wait, ok := retryPolicy.backoffAfter(attempt, err)
+		if !ok {
+			break
+		}
+		db.logger.Warn("retrying request", "method", method, "attempt", attempt, "error", err.Error())
+		if !db.wait(wait) {
+			break
+		}
+		err = db.sendOnce(db.ctx, res, method, rpcMethod, params)
+	}
+	latency := time.Since(start)
+	db.logRequest(method, params, latency, err)
+	db.audit(method, params, err)
+
+	var decodeErr *connection.DecodeError
+	if errors.As(err, &decodeErr) {
+		decodeErr.Target = requestTarget(method, params)
+	}
+	db.stats.completed(method, latency, err)
+
+	return err
+}
+
+// sendOnce makes an attempt of a request within ctx, the context of the DB. method is the method
+// of the client, and rpcMethod the one called on the server.
+func (db *DB) sendOnce(ctx context.Context, res interface{}, method, rpcMethod string, params []interface{}) error {
+	attemptCtx, cancel := db.attemptContext(ctx, method)
+	defer cancel()
+
+	params = db.withDeadline(attemptCtx, method, params)
+	if sender, ok := db.con.(connection.ContextSender); ok && attemptCtx != nil {
+		return db.attemptError(ctx, method, sender.SendContext(attemptCtx, res, rpcMethod, params...))
+	}
+	return db.con.Send(res, rpcMethod, params...)
+}
+
+// wait waits for d, and returns false when the context of the DB is done first, or right away
+// when its deadline is sooner than d.
+func (db *DB) wait(d time.Duration) bool {
+	var done <-chan struct{}
+	if db.ctx != nil {
+		if deadline, ok := db.ctx.Deadline(); ok && time.Until(deadline) < d {
+			return false
+		}
+		done = db.ctx.Done()
+	}
+	select {
+	case <-time.After(d):
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// querySingle runs sql, which must hold a single statement, and decodes the statement result.
+// Unlike Query, a statement that failed on the server is returned as an error.
+func querySingle[TResult any](db *DB, sql string, vars map[string]interface{}) (*TResult, error) {
+	var res connection.RPCResponse[[]QueryResult[cbor.RawMessage]]
+	if err := db.send(&res, "query", sql, vars); err != nil {
+		return nil, err
+	}
+
+	if res.Result == nil || len(*res.Result) == 0 {
+		return nil, constants.InvalidResponse
+	}
+
+	return decodeQueryResult[TResult](db, (*res.Result)[0])
+}
+
+func decodeQueryResult[TResult any](db *DB, qr QueryResult[cbor.RawMessage]) (*TResult, error) {
+	var result TResult
+	if err := decodeQueryResultInto(db, qr, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func decodeQueryResultInto(db *DB, qr QueryResult[cbor.RawMessage], dst interface{}) error {
+	unmarshaler := db.con.GetUnmarshaler()
+	if qr.Status != "OK" {
+		var msg string
+		if err := unmarshaler.Unmarshal(qr.Result, &msg); err != nil {
+			return constants.ErrQuery
+		}
+		return &QueryError{Message: msg}
+	}
+
+	if err := connection.DecodeResult(unmarshaler, "query", qr.Result, dst); err != nil {
+		db.stats.record(err)
+		return err
+	}
+
+	return nil
+}
+
+// isMultiTarget reports whether what holds several tables or records, which the RPC methods
+// do not accept and are handled with a query instead.
+func isMultiTarget[TWhat TablesOrRecords](what TWhat) bool {
+	switch any(what).(type) {
+	case []models.Table, []models.RecordID:
+		return true
+	default:
+		return false
+	}
+}
+
+// queryTarget converts what into a value that can be bound to a query variable and used as the
+// target of a statement. Plain strings are treated as table names.
+func queryTarget[TWhat TablesOrRecords](what TWhat) interface{} {
+	if s, ok := any(what).(string); ok {
+		return models.Table(s)
+	}
+	return what
+}
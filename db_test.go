@@ -1,16 +1,23 @@
 package surrealdb_test
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/surrealdb/surrealdb.go"
 
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/surrealdb/surrealdb.go/internal/fixture"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
 	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
 	"github.com/surrealdb/surrealdb.go/pkg/models"
 )
 
@@ -323,6 +330,42 @@ func (s *SurrealDBTestSuite) TestCreate() {
 	})
 }
 
+func (s *SurrealDBTestSuite) TestCreateExistingRecord() {
+	id := models.NewRecordID("users", "existing")
+	_, err := surrealdb.Create[testUser](s.db, id, testUser{Username: "john"})
+	s.Require().NoError(err)
+
+	_, err = surrealdb.Create[testUser](s.db, id, testUser{Username: "john"})
+	s.Require().ErrorIs(err, constants.ErrAlreadyExists)
+}
+
+// TestFixtureRoundTrip stores random documents holding every data type, and checks that they are
+// read back unchanged, byte for byte once encoded.
+func (s *SurrealDBTestSuite) TestFixtureRoundTrip() {
+	canonical := models.CborMarshaler{Deterministic: true}
+	for seed := int64(0); seed < 50; seed++ {
+		g := fixture.New(seed)
+		// fields holding NONE are not stored
+		g.Skip = map[fixture.Kind]bool{fixture.KindNone: true}
+		doc := g.Document()
+		id := models.NewRecordID("persons", seed)
+		doc["id"] = id
+
+		created, err := surrealdb.Create[map[string]interface{}](s.db, id, doc)
+		s.Require().NoError(err, "seed %d", seed)
+		selected, err := surrealdb.Select[map[string]interface{}](s.db, id)
+		s.Require().NoError(err, "seed %d", seed)
+
+		expected, err := canonical.Marshal(doc)
+		s.Require().NoError(err)
+		for _, record := range []*map[string]interface{}{created, selected} {
+			actual, err := canonical.Marshal(*record)
+			s.Require().NoError(err)
+			s.Require().Equal(expected, actual, "seed %d: %#v", seed, *record)
+		}
+	}
+}
+
 func (s *SurrealDBTestSuite) TestSelect() {
 	createdUser, err := surrealdb.Create[testUser](s.db, "users", testUser{
 		Username: "johnnyjohn",
@@ -409,6 +452,142 @@ func (s *SurrealDBTestSuite) TestMerge() {
 	s.Equal("456", user.Password)
 }
 
+func (s *SurrealDBTestSuite) TestUpdateAndMergeDiff() {
+	created, err := surrealdb.Create[testUser](s.db, *models.ParseRecordID("users:diff"), map[string]interface{}{
+		"username": "john",
+		"password": "123",
+	})
+	s.Require().NoError(err)
+
+	s.Run("merge returns the changed fields only", func() {
+		diff, err := surrealdb.MergeDiff(s.db, *created.ID, map[string]interface{}{
+			"password": "456",
+		})
+		s.Require().NoError(err)
+		s.Require().Len(*diff, 1)
+		s.Require().Len((*diff)[0], 1)
+		s.Equal("/password", (*diff)[0][0].Path)
+		s.Contains([]surrealdb.PatchOperation{surrealdb.PatchReplace, surrealdb.PatchChange}, (*diff)[0][0].Op)
+	})
+
+	s.Run("update reports removed fields", func() {
+		diff, err := surrealdb.UpdateDiff(s.db, *created.ID, map[string]interface{}{
+			"username": "john",
+		})
+		s.Require().NoError(err)
+		s.Require().Len(*diff, 1)
+		s.Require().Len((*diff)[0], 1)
+		s.Equal(surrealdb.PatchRemove, (*diff)[0][0].Op)
+		s.Equal("/password", (*diff)[0][0].Path)
+	})
+}
+
+func (s *SurrealDBTestSuite) TestDecodeErrors() {
+	_, err := surrealdb.Create[testUser](s.db, models.NewRecordID("users", "drift"), map[string]interface{}{
+		"username": 42,
+	})
+	s.Require().Error(err)
+
+	var decodeErr *connection.DecodeError
+	s.Require().ErrorAs(err, &decodeErr)
+	s.Equal("create", decodeErr.Method)
+	s.Equal("users:drift", decodeErr.Target)
+	s.Equal("result.username", decodeErr.Path)
+	s.GreaterOrEqual(s.db.Stats().DecodeErrors["create"], uint64(1))
+}
+
+func (s *SurrealDBTestSuite) TestMultipleTargets() {
+	_, err := surrealdb.Create[testUser](s.db, models.NewRecordID("users", "john"), testUser{Username: "john"})
+	s.Require().NoError(err)
+	_, err = surrealdb.Create[testUser](s.db, models.NewRecordID("users", "jane"), testUser{Username: "jane"})
+	s.Require().NoError(err)
+	_, err = surrealdb.Create[testPerson](s.db, models.NewRecordID("persons", "doe"), testPerson{FirstName: "doe"})
+	s.Require().NoError(err)
+
+	s.Run("select several tables", func() {
+		records, err := surrealdb.Select[[]map[string]interface{}](s.db, []models.Table{"users", "persons"})
+		s.Require().NoError(err)
+		s.Len(*records, 3)
+	})
+
+	s.Run("select by table", func() {
+		byTable, err := surrealdb.SelectByTable[map[string]interface{}](s.db, "users", "persons")
+		s.Require().NoError(err)
+		s.Len(byTable["users"], 2)
+		s.Len(byTable["persons"], 1)
+	})
+
+	s.Run("delete several records", func() {
+		deleted, err := surrealdb.Delete[[]testUser](s.db, []models.RecordID{
+			models.NewRecordID("users", "john"),
+			models.NewRecordID("persons", "doe"),
+		})
+		s.Require().NoError(err)
+		s.Len(*deleted, 2)
+
+		remaining, err := surrealdb.Select[[]testUser](s.db, []models.Table{"users", "persons"})
+		s.Require().NoError(err)
+		s.Require().Len(*remaining, 1)
+		s.Equal("jane", (*remaining)[0].Username)
+	})
+}
+
+func (s *SurrealDBTestSuite) TestMultiByteIdentifiers() {
+	identifiers := []string{
+		"→owns→Ϭlub",
+		"🦀🚀",
+		"👩‍👩‍👧",
+		"数据库",
+		"שלום",
+		"مرحبا",
+		"été", // decomposed accents, must not be normalized
+	}
+
+	for _, ident := range identifiers {
+		s.Run(ident, func() {
+			id := models.NewRecordID("users", ident)
+			created, err := surrealdb.Create[testUser](s.db, id, testUser{Username: ident, Password: ident})
+			s.Require().NoError(err)
+			s.Equal(ident, created.Username)
+			s.Equal(id, *created.ID)
+
+			selected, err := surrealdb.Select[testUser](s.db, id)
+			s.Require().NoError(err)
+			s.Equal(*created, *selected)
+
+			byVar, err := surrealdb.Query[[]testUser](s.db, "SELECT * FROM users WHERE username = $name", map[string]interface{}{
+				"name": ident,
+			})
+			s.Require().NoError(err)
+			s.Require().Len((*byVar)[0].Result, 1)
+			s.Equal(*created, (*byVar)[0].Result[0])
+
+			// the id rendered by String must be valid SurrealQL for the same record
+			byString, err := surrealdb.Query[[]testUser](s.db, "SELECT * FROM "+id.String(), map[string]interface{}{})
+			s.Require().NoError(err)
+			s.Require().Len((*byString)[0].Result, 1)
+			s.Equal(*created, (*byString)[0].Result[0])
+		})
+	}
+
+	s.Run("multi-byte table name", func() {
+		id := models.NewRecordID("数据库", "Ϭlub")
+		defer func() {
+			_, err := surrealdb.Delete[[]testUser](s.db, models.Table(id.Table))
+			s.Require().NoError(err)
+		}()
+
+		created, err := surrealdb.Create[testUser](s.db, id, testUser{Username: "Ϭlub"})
+		s.Require().NoError(err)
+		s.Equal(id, *created.ID)
+
+		selected, err := surrealdb.Select[[]testUser](s.db, models.Table(id.Table))
+		s.Require().NoError(err)
+		s.Require().Len(*selected, 1)
+		s.Equal(id, *(*selected)[0].ID)
+	})
+}
+
 func (s *SurrealDBTestSuite) TestRelateAndInsertRelation() {
 	persons, err := surrealdb.Insert[testPerson](s.db, "person", []testPerson{
 		{FirstName: "Mary", LastName: "Doe"},
@@ -474,3 +653,225 @@ func (s *SurrealDBTestSuite) TestRPCError() {
 		s.Require().Error(err)
 	})
 }
+
+func TestSelectRecordIDRange(t *testing.T) {
+	var sent models.RecordIDRange
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		require.Equal(t, "select", req.Method)
+
+		// a range param decodes into a RecordID by default, so decode it again as a RecordIDRange
+		var raw struct {
+			Params []cbor.RawMessage `json:"params"`
+		}
+		require.NoError(t, models.CborUnmarshaler{}.Unmarshal(req.Body, &raw))
+		require.NoError(t, models.CborUnmarshaler{}.Unmarshal(raw.Params[0], &sent))
+		return []interface{}{map[string]interface{}{"name": "a"}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	type person struct {
+		Name string `json:"name"`
+	}
+	people, err := surrealdb.Select[[]person](db, models.NewRecordIDRange("person", 1, 1000))
+	require.NoError(t, err)
+	require.Equal(t, []person{{Name: "a"}}, *people)
+	require.Equal(t, "person:1..1000", sent.String())
+}
+
+func TestCreateOrSkip(t *testing.T) {
+	var lock sync.Mutex
+	var queries []string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		bob := map[string]interface{}{"id": models.NewRecordID("user", "b"), "name": "Bob"}
+		switch req.Method {
+		case "create":
+			switch req.Params[0].(models.RecordID).ID {
+			case "a":
+				return nil, &connection.RPCError{Code: -32000, Message: "Database record `user:a` already exists"}
+			case "denied":
+				return nil, &connection.RPCError{Code: -32000, Message: "Not enough permissions to perform this action"}
+			}
+		case "query":
+			lock.Lock()
+			queries = append(queries, req.Params[0].(string))
+			lock.Unlock()
+			return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": []interface{}{bob}}}, nil
+		}
+		return bob, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	type user struct {
+		Name string `json:"name"`
+	}
+	record, created, err := surrealdb.CreateOrSkip[user](db, models.NewRecordID("user", "a"), user{Name: "Ann"})
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Nil(t, record)
+
+	record, created, err = surrealdb.CreateOrSkip[user](db, models.NewRecordID("user", "b"), user{Name: "Bob"})
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, "Bob", record.Name)
+
+	_, _, err = surrealdb.CreateOrSkip[user](db, models.NewRecordID("user", "denied"), user{})
+	require.ErrorIs(t, err, constants.ErrPermissionDenied)
+
+	inserted, err := surrealdb.InsertIgnore[user](db, "user", []user{{Name: "Bob"}})
+	require.NoError(t, err)
+	require.Equal(t, []user{{Name: "Bob"}}, *inserted)
+	lock.Lock()
+	require.Equal(t, []string{"INSERT IGNORE INTO $table $data"}, queries)
+	lock.Unlock()
+}
+
+func TestSelectValue(t *testing.T) {
+	var lock sync.Mutex
+	var requests []connection.RPCRequest
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		requests = append(requests, req.RPCRequest)
+		lock.Unlock()
+
+		var result interface{}
+		switch sql := req.Params[0].(string); {
+		case strings.HasPrefix(sql, "SELECT VALUE"):
+			result = []interface{}{"Ann", "Bob"}
+		case req.Params[1].(map[interface{}]interface{})["record"].(models.RecordID).ID == "missing":
+			result = models.None
+		default:
+			result = map[string]interface{}{"id": models.NewRecordID("user", "a"), "name": "Ann"}
+		}
+		return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": result}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	adult := surrealdb.Condition{SQL: "age >= $age", Vars: map[string]interface{}{"age": 18}}
+	names, err := surrealdb.SelectValue[string](db, models.Table("user"), "name", &adult)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Ann", "Bob"}, names)
+
+	type user struct {
+		ID   *models.RecordID `json:"id"`
+		Name string           `json:"name"`
+	}
+	ann, err := surrealdb.SelectOnly[user](db, models.NewRecordID("user", "a"))
+	require.NoError(t, err)
+	require.Equal(t, "Ann", ann.Name)
+
+	missing, err := surrealdb.SelectOnly[user](db, models.NewRecordID("user", "missing"))
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, "SELECT VALUE name FROM $select_what WHERE age >= $age", requests[0].Params[0])
+	require.Equal(t, "SELECT * FROM ONLY $record", requests[1].Params[0])
+}
+
+func TestDecodeResult(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		return map[string]interface{}{
+			"id":   models.NewRecordID("user", "john"),
+			"name": "John",
+			"age":  "unknown",
+		}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	var res connection.RPCResponse[cbor.RawMessage]
+	require.NoError(t, db.Send(&res, "select", models.NewRecordID("user", "john")))
+
+	type user struct {
+		ID   *models.RecordID `json:"id"`
+		Name string           `json:"name"`
+	}
+	john, err := surrealdb.DecodeResult[user](db, *res.Result)
+	require.NoError(t, err)
+	require.Equal(t, models.NewRecordID("user", "john"), *john.ID)
+	require.Equal(t, "John", john.Name)
+
+	type aged struct {
+		Age int `json:"age"`
+	}
+	_, err = surrealdb.DecodeResult[aged](db, *res.Result)
+	var decodeErr *connection.DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "age", decodeErr.Path)
+}
+
+func TestRun(t *testing.T) {
+	var params []interface{}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		require.Equal(t, "run", req.Method)
+		params = req.Params
+		return 0.75, nil
+	})
+
+	var audited []surrealdb.AuditEntry
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithAuditHook(func(entry surrealdb.AuditEntry) { audited = append(audited, entry) }),
+	)
+	require.NoError(t, err)
+
+	score, err := surrealdb.Run[float64](db, "fn::compute_score", models.NewRecordID("user", "john"), 0.5)
+	require.NoError(t, err)
+	require.Equal(t, 0.75, *score)
+	require.Equal(t, []interface{}{"fn::compute_score", nil, []interface{}{models.NewRecordID("user", "john"), 0.5}}, params)
+
+	_, err = surrealdb.Run[float64](db, "time::now")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"time::now", nil, []interface{}{}}, params)
+
+	require.Len(t, audited, 2)
+	require.Equal(t, "fn::compute_score", audited[0].Target)
+}
+
+func TestSelectFields(t *testing.T) {
+	var queries []string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		queries = append(queries, req.Params[0].(string))
+
+		// a field selected explicitly is returned as NONE when the record lacks it
+		records := []interface{}{
+			map[string]interface{}{"id": models.NewRecordID("user", "a"), "name": "Ann", "nick": "annie"},
+			map[string]interface{}{"id": models.NewRecordID("user", "b"), "name": "Bob", "nick": models.None},
+		}
+		return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": records}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithCodec(models.CborMarshaler{}, models.CborUnmarshaler{Options: models.DecodeOptions{NoneAsNil: true}}),
+	)
+	require.NoError(t, err)
+
+	type userName struct {
+		ID   *models.RecordID `json:"id"`
+		Name string           `json:"name"`
+		Nick *string          `json:"nick"`
+	}
+	active := surrealdb.Condition{SQL: "active = $active", Vars: map[string]interface{}{"active": true}}
+	users, err := surrealdb.SelectFields[userName](db, models.Table("user"), &active)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, "annie", *users[0].Nick)
+	require.Equal(t, "Bob", users[1].Name)
+	require.Nil(t, users[1].Nick)
+	require.Equal(t, "SELECT id, name, nick FROM $select_what WHERE active = $active", queries[0])
+
+	_, err = surrealdb.SelectFields[string](db, models.Table("user"), nil)
+	require.Error(t, err)
+	_, err = surrealdb.SelectFields[struct{}](db, models.Table("user"), nil)
+	require.Error(t, err)
+	require.Len(t, queries, 1)
+}
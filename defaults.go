@@ -0,0 +1,113 @@
+package surrealdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithDefaultVars sets variables bound in every query, such as a tenant id or the version of the
+// application, see DB.SetDefaultVar.
+func WithDefaultVars(vars map[string]interface{}) Option {
+	return func(c *config) error {
+		if c.defaultVars == nil {
+			c.defaultVars = make(map[string]interface{}, len(vars))
+		}
+		for name, value := range vars {
+			name, err := varName(name)
+			if err != nil {
+				return err
+			}
+			c.defaultVars[name] = value
+		}
+		return nil
+	}
+}
+
+// SetDefaultVar binds the variable name to value in every query run with the DB, Query as well as
+// the other functions running queries. A variable passed to a query takes precedence over the
+// default variable of the same name.
+//
+// Unlike Let, default variables are sent along with each query rather than stored in the session,
+// so they are not lost on reconnection and work the same with every engine.
+func (db *DB) SetDefaultVar(name string, value interface{}) error {
+	name, err := varName(name)
+	if err != nil {
+		return err
+	}
+
+	db.defaultVarsLock.Lock()
+	defer db.defaultVarsLock.Unlock()
+	vars := make(map[string]interface{}, len(db.defaultVars)+1)
+	for k, v := range db.defaultVars {
+		vars[k] = v
+	}
+	vars[name] = value
+	db.defaultVars = vars
+	return nil
+}
+
+// UnsetDefaultVar removes a variable set with SetDefaultVar or WithDefaultVars.
+func (db *DB) UnsetDefaultVar(name string) {
+	db.defaultVarsLock.Lock()
+	defer db.defaultVarsLock.Unlock()
+	name = strings.TrimPrefix(name, "$")
+	if _, ok := db.defaultVars[name]; !ok {
+		return
+	}
+	vars := make(map[string]interface{}, len(db.defaultVars))
+	for k, v := range db.defaultVars {
+		if k != name {
+			vars[k] = v
+		}
+	}
+	db.defaultVars = vars
+}
+
+// DefaultVars returns a copy of the default variables of the DB.
+func (db *DB) DefaultVars() map[string]interface{} {
+	db.defaultVarsLock.RLock()
+	defer db.defaultVarsLock.RUnlock()
+	vars := make(map[string]interface{}, len(db.defaultVars))
+	for k, v := range db.defaultVars {
+		vars[k] = v
+	}
+	return vars
+}
+
+// withDefaultVars returns the parameters of a request with the default variables added to those
+// of a query.
+func (db *DB) withDefaultVars(method string, params []interface{}) ([]interface{}, error) {
+	if method != "query" {
+		return params, nil
+	}
+	db.defaultVarsLock.RLock()
+	defaults := db.defaultVars
+	db.defaultVarsLock.RUnlock()
+	if len(defaults) == 0 {
+		return params, nil
+	}
+
+	var vars map[string]interface{}
+	if len(params) > 1 && params[1] != nil {
+		var ok bool
+		if vars, ok = params[1].(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("query variables must be a map[string]interface{} to add the default variables, got %T", params[1])
+		}
+	}
+
+	merged := make(map[string]interface{}, len(defaults)+len(vars))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+
+	withVars := append([]interface{}{}, params...)
+	if len(withVars) < 2 {
+		withVars = append(withVars, merged)
+	} else {
+		withVars[1] = merged
+	}
+	return withVars, nil
+}
@@ -0,0 +1,44 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestDefaultVars(t *testing.T) {
+	var vars map[interface{}]interface{}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		vars = nil
+		if len(req.Params) > 1 {
+			vars, _ = req.Params[1].(map[interface{}]interface{})
+		}
+		return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": []interface{}{}}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithDefaultVars(map[string]interface{}{"$tenant": "acme"}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, db.SetDefaultVar("app_version", "1.2.0"))
+
+	_, err = surrealdb.Query[[]testUser](db, "SELECT * FROM user WHERE tenant = $tenant", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[interface{}]interface{}{"tenant": "acme", "app_version": "1.2.0"}, vars)
+
+	_, err = surrealdb.Query[[]testUser](db, "SELECT * FROM user WHERE tenant = $tenant",
+		map[string]interface{}{"tenant": "other", "limit": 1})
+	require.NoError(t, err)
+	require.Equal(t, map[interface{}]interface{}{"tenant": "other", "app_version": "1.2.0", "limit": uint64(1)}, vars)
+
+	db.UnsetDefaultVar("$app_version")
+	require.Equal(t, map[string]interface{}{"tenant": "acme"}, db.DefaultVars())
+
+	require.Error(t, db.SetDefaultVar("auth", "x"))
+	require.Error(t, db.SetDefaultVar("not valid", "x"))
+}
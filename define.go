@@ -0,0 +1,223 @@
+package surrealdb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Permissions are the PERMISSIONS of a table or a field, by operation. Each is FULL, NONE or a
+// condition the record must match, such as user = $auth.id. An empty permission is left to the
+// default of the server.
+type Permissions struct {
+	Select string
+	Create string
+	Update string
+	// Delete applies to tables only.
+	Delete string
+}
+
+// TableDefinition describes a table, see DefineTableStatement.
+type TableDefinition struct {
+	Table models.Table
+	// Schemafull rejects the fields that are not defined, the table is SCHEMALESS otherwise.
+	Schemafull bool
+	// Changefeed keeps the changes made to the table for the duration, no change feed when zero.
+	Changefeed  time.Duration
+	Permissions Permissions
+}
+
+// FieldDefinition describes a field of a table, see DefineFieldStatement. Default, Value and
+// Assert are SurrealQL expressions, where $value is the value of the field.
+type FieldDefinition struct {
+	Table models.Table
+	// Field is the path of the field, such as address.city.
+	Field string
+	// Type is the type of the field, such as string, option<datetime> or array<record<user>>.
+	Type    string
+	Default string
+	Value   string
+	// Assert is a condition the value must match, such as string::is::email($value).
+	Assert      string
+	Readonly    bool
+	Permissions Permissions
+}
+
+// IndexDefinition describes an index on fields of a table, see DefineIndexStatement. Full-text
+// and vector indexes are described by SearchIndex and VectorIndex.
+type IndexDefinition struct {
+	Name   string
+	Table  models.Table
+	Fields []string
+	// Unique rejects the records whose fields hold the same values as another record.
+	Unique bool
+}
+
+// EventDefinition describes an event of a table, see DefineEventStatement. When and Then are
+// SurrealQL, which may use $event, $before and $after.
+type EventDefinition struct {
+	Name  string
+	Table models.Table
+	// When is the condition triggering the event, every change of a record when empty.
+	When string
+	Then string
+}
+
+// fieldType matches a field type such as option<array<record<user | post>, 10>>
+var fieldType = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_<>|, ]*$`)
+
+// DefineTableStatement returns the DEFINE TABLE statement of def. Like the other Define*Statement
+// functions, the statement replaces a definition of the same name, which requires SurrealDB 2.0 or
+// later.
+func DefineTableStatement(def TableDefinition) (string, error) {
+	if def.Table == "" {
+		return "", fmt.Errorf("table definition needs a table")
+	}
+
+	sql := "DEFINE TABLE OVERWRITE " + def.Table.SurrealString()
+	if def.Schemafull {
+		sql += " SCHEMAFULL"
+	} else {
+		sql += " SCHEMALESS"
+	}
+	if def.Changefeed < 0 {
+		return "", fmt.Errorf("invalid change feed duration %s", def.Changefeed)
+	}
+	if def.Changefeed > 0 {
+		sql += " CHANGEFEED " + models.FormatDuration(int64(def.Changefeed))
+	}
+	return sql + permissionsClause(def.Permissions), nil
+}
+
+// DefineFieldStatement returns the DEFINE FIELD statement of def.
+func DefineFieldStatement(def FieldDefinition) (string, error) {
+	if def.Table == "" {
+		return "", fmt.Errorf("field definition needs a table")
+	}
+	field, err := models.EscapeFieldPath(def.Field)
+	if err != nil {
+		return "", err
+	}
+	if def.Permissions.Delete != "" {
+		return "", fmt.Errorf("fields have no delete permission")
+	}
+
+	sql := fmt.Sprintf("DEFINE FIELD OVERWRITE %s ON TABLE %s", field, def.Table.SurrealString())
+	if def.Type != "" {
+		if !fieldType.MatchString(def.Type) || strings.Count(def.Type, "<") != strings.Count(def.Type, ">") {
+			return "", fmt.Errorf("invalid field type %q", def.Type)
+		}
+		sql += " TYPE " + def.Type
+	}
+	if def.Default != "" {
+		sql += " DEFAULT " + def.Default
+	}
+	if def.Readonly {
+		sql += " READONLY"
+	}
+	if def.Value != "" {
+		sql += " VALUE " + def.Value
+	}
+	if def.Assert != "" {
+		sql += " ASSERT " + def.Assert
+	}
+	return sql + permissionsClause(def.Permissions), nil
+}
+
+// DefineIndexStatement returns the DEFINE INDEX statement of def.
+func DefineIndexStatement(def IndexDefinition) (string, error) {
+	if def.Name == "" || def.Table == "" || len(def.Fields) == 0 {
+		return "", fmt.Errorf("index definition needs a name, a table and fields")
+	}
+	fields, err := escapeFieldPaths(def.Fields)
+	if err != nil {
+		return "", err
+	}
+
+	sql := fmt.Sprintf("DEFINE INDEX OVERWRITE %s ON TABLE %s FIELDS %s",
+		models.EscapeIdent(def.Name), def.Table.SurrealString(), fields)
+	if def.Unique {
+		sql += " UNIQUE"
+	}
+	return sql, nil
+}
+
+// DefineEventStatement returns the DEFINE EVENT statement of def.
+func DefineEventStatement(def EventDefinition) (string, error) {
+	if def.Name == "" || def.Table == "" || def.Then == "" {
+		return "", fmt.Errorf("event definition needs a name, a table and a THEN clause")
+	}
+
+	sql := fmt.Sprintf("DEFINE EVENT OVERWRITE %s ON TABLE %s", models.EscapeIdent(def.Name), def.Table.SurrealString())
+	if def.When != "" {
+		sql += " WHEN " + def.When
+	}
+	return sql + " THEN " + def.Then, nil
+}
+
+// RemoveTableStatement returns the REMOVE TABLE statement of table, which deletes its records.
+// Like the other Remove*Statement functions, removing a definition that does not exist is not an
+// error.
+func RemoveTableStatement(table models.Table) string {
+	return "REMOVE TABLE IF EXISTS " + table.SurrealString()
+}
+
+// RemoveFieldStatement returns the REMOVE FIELD statement of the field of table.
+func RemoveFieldStatement(table models.Table, field string) (string, error) {
+	escaped, err := models.EscapeFieldPath(field)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("REMOVE FIELD IF EXISTS %s ON TABLE %s", escaped, table.SurrealString()), nil
+}
+
+// RemoveIndexStatement returns the REMOVE INDEX statement of the index name of table.
+func RemoveIndexStatement(table models.Table, name string) string {
+	return fmt.Sprintf("REMOVE INDEX IF EXISTS %s ON TABLE %s", models.EscapeIdent(name), table.SurrealString())
+}
+
+// RemoveEventStatement returns the REMOVE EVENT statement of the event name of table.
+func RemoveEventStatement(table models.Table, name string) string {
+	return fmt.Sprintf("REMOVE EVENT IF EXISTS %s ON TABLE %s", models.EscapeIdent(name), table.SurrealString())
+}
+
+// DefineSchema runs the DEFINE and REMOVE statements in a single transaction, so that either all
+// of them or none are applied.
+//
+//	table, err := surrealdb.DefineTableStatement(surrealdb.TableDefinition{Table: "user", Schemafull: true})
+//	email, err := surrealdb.DefineFieldStatement(surrealdb.FieldDefinition{
+//		Table: "user", Field: "email", Type: "string", Assert: "string::is::email($value)",
+//	})
+//	err = surrealdb.DefineSchema(db, table, email)
+func DefineSchema(db *DB, statements ...string) error {
+	if len(statements) == 0 {
+		return nil
+	}
+	sql := "BEGIN TRANSACTION;\n" + strings.Join(statements, ";\n") + ";\nCOMMIT TRANSACTION;"
+	_, err := QueryAll[interface{}](db, sql, nil)
+	return err
+}
+
+// permissionsClause returns the PERMISSIONS clause of p, or an empty string when p is empty.
+func permissionsClause(p Permissions) string {
+	var clauses []string
+	for _, permission := range []struct {
+		operation string
+		value     string
+	}{{"select", p.Select}, {"create", p.Create}, {"update", p.Update}, {"delete", p.Delete}} {
+		switch value := strings.TrimSpace(permission.value); strings.ToUpper(value) {
+		case "":
+		case "FULL", "NONE":
+			clauses = append(clauses, "FOR "+permission.operation+" "+strings.ToUpper(value))
+		default:
+			clauses = append(clauses, "FOR "+permission.operation+" WHERE "+value)
+		}
+	}
+	if len(clauses) == 0 {
+		return ""
+	}
+	return " PERMISSIONS " + strings.Join(clauses, " ")
+}
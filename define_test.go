@@ -0,0 +1,84 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestDefineStatements(t *testing.T) {
+	sql, err := surrealdb.DefineTableStatement(surrealdb.TableDefinition{
+		Table: "post", Schemafull: true, Changefeed: 24 * time.Hour,
+		Permissions: surrealdb.Permissions{Select: "full", Create: "user = $auth.id", Delete: "NONE"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "DEFINE TABLE OVERWRITE post SCHEMAFULL CHANGEFEED 1d "+
+		"PERMISSIONS FOR select FULL FOR create WHERE user = $auth.id FOR delete NONE", sql)
+
+	sql, err = surrealdb.DefineTableStatement(surrealdb.TableDefinition{Table: "log"})
+	require.NoError(t, err)
+	require.Equal(t, "DEFINE TABLE OVERWRITE log SCHEMALESS", sql)
+
+	sql, err = surrealdb.DefineFieldStatement(surrealdb.FieldDefinition{
+		Table: "user", Field: "contact.e-mail", Type: "option<string>", Default: "NONE",
+		Assert: "$value = NONE OR string::is::email($value)", Permissions: surrealdb.Permissions{Update: "id = $auth.id"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "DEFINE FIELD OVERWRITE contact.⟨e-mail⟩ ON TABLE user TYPE option<string> DEFAULT NONE "+
+		"ASSERT $value = NONE OR string::is::email($value) PERMISSIONS FOR update WHERE id = $auth.id", sql)
+
+	sql, err = surrealdb.DefineIndexStatement(surrealdb.IndexDefinition{
+		Name: "user_email", Table: "user", Fields: []string{"contact.e-mail"}, Unique: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "DEFINE INDEX OVERWRITE user_email ON TABLE user FIELDS contact.⟨e-mail⟩ UNIQUE", sql)
+
+	sql, err = surrealdb.VectorIndexStatement(surrealdb.VectorIndex{
+		Name: "doc_embedding", Table: "doc", Field: "embedding", Dimension: 3, Distance: "cosine", Type: "f32",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "DEFINE INDEX OVERWRITE doc_embedding ON TABLE doc FIELDS embedding HNSW DIMENSION 3 DIST COSINE TYPE F32", sql)
+
+	sql, err = surrealdb.DefineEventStatement(surrealdb.EventDefinition{
+		Name: "audit", Table: "user", When: "$event = 'DELETE'", Then: "CREATE audit SET user = $before.id",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "DEFINE EVENT OVERWRITE audit ON TABLE user WHEN $event = 'DELETE' THEN CREATE audit SET user = $before.id", sql)
+
+	require.Equal(t, "REMOVE TABLE IF EXISTS ⟨old log⟩", surrealdb.RemoveTableStatement("old log"))
+	sql, err = surrealdb.RemoveFieldStatement("user", "contact.fax")
+	require.NoError(t, err)
+	require.Equal(t, "REMOVE FIELD IF EXISTS contact.fax ON TABLE user", sql)
+	require.Equal(t, "REMOVE INDEX IF EXISTS user_email ON TABLE user", surrealdb.RemoveIndexStatement("user", "user_email"))
+	require.Equal(t, "REMOVE EVENT IF EXISTS audit ON TABLE user", surrealdb.RemoveEventStatement("user", "audit"))
+
+	_, err = surrealdb.DefineTableStatement(surrealdb.TableDefinition{})
+	require.Error(t, err)
+	_, err = surrealdb.DefineFieldStatement(surrealdb.FieldDefinition{Table: "user", Field: "name", Type: "string; REMOVE TABLE user"})
+	require.Error(t, err)
+	_, err = surrealdb.DefineFieldStatement(surrealdb.FieldDefinition{Table: "user", Field: "name", Permissions: surrealdb.Permissions{Delete: "FULL"}})
+	require.Error(t, err)
+	_, err = surrealdb.DefineIndexStatement(surrealdb.IndexDefinition{Name: "user_email", Table: "user"})
+	require.Error(t, err)
+	_, err = surrealdb.VectorIndexStatement(surrealdb.VectorIndex{Name: "doc_embedding", Table: "doc", Field: "embedding", Dimension: 3, Distance: "cosine x"})
+	require.Error(t, err)
+}
+
+func TestDefineSchema(t *testing.T) {
+	var sql string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		sql = req.Params[0].(string)
+		return []surrealdb.QueryResult[interface{}]{{Status: "OK"}, {Status: "OK"}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	require.NoError(t, surrealdb.DefineSchema(db, "DEFINE TABLE OVERWRITE user SCHEMAFULL", surrealdb.RemoveTableStatement("log")))
+	require.Equal(t, "BEGIN TRANSACTION;\nDEFINE TABLE OVERWRITE user SCHEMAFULL;\nREMOVE TABLE IF EXISTS log;\nCOMMIT TRANSACTION;", sql)
+}
@@ -0,0 +1,60 @@
+package surrealdb
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// endpointOptions holds the settings that can be passed as query parameters of the connection
+// url, e.g. ws://localhost:8000?timeout=5s&ns=app&db=main.
+type endpointOptions struct {
+	timeout   time.Duration
+	poolSize  int
+	namespace string
+	database  string
+}
+
+func parseEndpointOptions(query url.Values) (*endpointOptions, error) {
+	opts := &endpointOptions{}
+
+	for key, values := range query {
+		if len(values) != 1 {
+			return nil, fmt.Errorf("connection url parameter %q must be given once", key)
+		}
+		value := values[0]
+
+		switch key {
+		case "codec":
+			// surrealcbor names the CBOR codec with the SurrealDB tags, which is the one available
+			if value != "cbor" && value != "surrealcbor" {
+				return nil, fmt.Errorf("unsupported codec %q, only cbor is available", value)
+			}
+		case "timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("invalid timeout %q in connection url", value)
+			}
+			opts.timeout = timeout
+		case "pool":
+			size, err := strconv.Atoi(value)
+			if err != nil || size < 1 {
+				return nil, fmt.Errorf("invalid pool size %q in connection url", value)
+			}
+			opts.poolSize = size
+		case "ns":
+			opts.namespace = value
+		case "db":
+			opts.database = value
+		default:
+			return nil, fmt.Errorf("unknown connection url parameter %q", key)
+		}
+	}
+
+	if (opts.namespace == "") != (opts.database == "") {
+		return nil, fmt.Errorf("ns and db must be set together in the connection url")
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,46 @@
+package surrealdb_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+)
+
+func TestNew_InvalidEndpointOptions(t *testing.T) {
+	invalid := map[string]string{
+		"unknown codec":         "ws://localhost:8000?codec=json",
+		"invalid timeout":       "ws://localhost:8000?timeout=soon",
+		"negative timeout":      "http://localhost:8000?timeout=-1s",
+		"invalid pool":          "http://localhost:8000?pool=none",
+		"pool on websocket":     "ws://localhost:8000?pool=8",
+		"namespace without db":  "ws://localhost:8000?ns=app",
+		"repeated parameter":    "ws://localhost:8000?ns=app&ns=other&db=main",
+		"unknown parameter":     "ws://localhost:8000?compression=true",
+		"database without ns":   "http://localhost:8000?db=main",
+		"unsupported transport": "tcp://localhost:8000",
+	}
+
+	for name, endpoint := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := surrealdb.New(endpoint)
+			if err == nil {
+				t.Fatalf("expected an error for %s", endpoint)
+			}
+		})
+	}
+}
+
+func TestNew_EndpointCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	for _, codec := range []string{"cbor", "surrealcbor"} {
+		db, err := surrealdb.New(server.URL + "?codec=" + codec)
+		if err != nil {
+			t.Fatalf("codec %s: %v", codec, err)
+		}
+		_ = db.Close()
+	}
+}
@@ -0,0 +1,8 @@
+//go:build js && wasm
+
+package surrealdb
+
+// websocketSupported reports whether the ws engine can be used. Under js/wasm, the standard
+// library has no sockets: only the http engine works, through the Fetch API of the browser or
+// the JavaScript runtime.
+const websocketSupported = false
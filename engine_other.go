@@ -0,0 +1,6 @@
+//go:build !(js && wasm)
+
+package surrealdb
+
+// websocketSupported reports whether the ws engine can be used.
+const websocketSupported = true
@@ -0,0 +1,54 @@
+package surrealdb
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Environment variables read by FromEnv
+const (
+	EnvURL       = "SURREALDB_URL"
+	EnvUser      = "SURREALDB_USER"
+	EnvPass      = "SURREALDB_PASS"
+	EnvToken     = "SURREALDB_TOKEN"
+	EnvNamespace = "SURREALDB_NS"
+	EnvDatabase  = "SURREALDB_DB"
+)
+
+// FromEnv connects like Connect, reading the endpoint from SURREALDB_URL, the root credentials
+// from SURREALDB_USER and SURREALDB_PASS, or a token from SURREALDB_TOKEN, and the namespace and
+// database from SURREALDB_NS and SURREALDB_DB. Credentials, namespace and database are optional
+// but must be given in pairs. Options passed explicitly take precedence over the environment.
+func FromEnv(ctx context.Context, opts ...Option) (*DB, error) {
+	endpoint := os.Getenv(EnvURL)
+	if endpoint == "" {
+		return nil, fmt.Errorf("%s is not set", EnvURL)
+	}
+
+	var envOpts []Option
+
+	user, pass := os.Getenv(EnvUser), os.Getenv(EnvPass)
+	if (user == "") != (pass == "") {
+		return nil, fmt.Errorf("%s and %s must be set together", EnvUser, EnvPass)
+	}
+	if user != "" {
+		envOpts = append(envOpts, WithAuth(&Auth{Username: user, Password: pass}))
+	}
+	if token := os.Getenv(EnvToken); token != "" {
+		if user != "" {
+			return nil, fmt.Errorf("%s cannot be set along with %s and %s", EnvToken, EnvUser, EnvPass)
+		}
+		envOpts = append(envOpts, WithToken(token))
+	}
+
+	ns, db := os.Getenv(EnvNamespace), os.Getenv(EnvDatabase)
+	if (ns == "") != (db == "") {
+		return nil, fmt.Errorf("%s and %s must be set together", EnvNamespace, EnvDatabase)
+	}
+	if ns != "" {
+		envOpts = append(envOpts, WithNamespace(ns, db))
+	}
+
+	return Connect(ctx, endpoint, append(envOpts, opts...)...)
+}
@@ -0,0 +1,34 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv_InvalidEnvironment(t *testing.T) {
+	t.Run("url is required", func(t *testing.T) {
+		t.Setenv(surrealdb.EnvURL, "")
+		_, err := surrealdb.FromEnv(context.Background())
+		require.ErrorContains(t, err, surrealdb.EnvURL)
+	})
+
+	t.Run("credentials must be paired", func(t *testing.T) {
+		t.Setenv(surrealdb.EnvURL, "ws://127.0.0.1:1")
+		t.Setenv(surrealdb.EnvUser, "root")
+		t.Setenv(surrealdb.EnvPass, "")
+		_, err := surrealdb.FromEnv(context.Background())
+		require.ErrorContains(t, err, surrealdb.EnvPass)
+	})
+
+	t.Run("namespace and database must be paired", func(t *testing.T) {
+		t.Setenv(surrealdb.EnvURL, "ws://127.0.0.1:1")
+		t.Setenv(surrealdb.EnvNamespace, "")
+		t.Setenv(surrealdb.EnvDatabase, "main")
+		_, err := surrealdb.FromEnv(context.Background())
+		require.ErrorContains(t, err, surrealdb.EnvNamespace)
+	})
+}
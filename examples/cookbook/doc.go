@@ -0,0 +1,9 @@
+// Package cookbook holds runnable examples for every RPC method exposed by the SDK. The graphql
+// example is only compiled, as the graphql method needs a server with the experimental GraphQL
+// feature enabled.
+//
+// Each example runs against the server given by SURREALDB_URL (ws://localhost:8000 by default)
+// with the root user, in its own scratch database of the cookbook namespace, and its output is
+// checked by go test. The examples double as regression tests of the protocol surface and as
+// snippets to copy from.
+package cookbook
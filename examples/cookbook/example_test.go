@@ -0,0 +1,526 @@
+package cookbook_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	surrealdb "github.com/surrealdb/surrealdb.go"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+type Person struct {
+	ID      *models.RecordID `json:"id,omitempty"`
+	Name    string           `json:"name"`
+	Surname string           `json:"surname,omitempty"`
+	Age     int              `json:"age,omitempty"`
+}
+
+// connect returns a client signed in as root, using a scratch database dedicated to one example,
+// so that the examples can run in parallel. The returned function removes the database and closes
+// the connections.
+func connect() (*surrealdb.DB, func()) {
+	ctx := context.Background()
+	root, err := surrealdb.Connect(ctx, endpoint(),
+		surrealdb.WithAuth(&surrealdb.Auth{Username: "root", Password: "root"}),
+		surrealdb.WithNamespace("cookbook", "cookbook"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	db, cleanup, err := surrealdb.NewScratchDB(ctx, root)
+	if err != nil {
+		panic(err)
+	}
+
+	return db, func() {
+		if err := cleanup(); err != nil {
+			panic(err)
+		}
+		if err := root.Close(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// connectAnonymous returns a client without authentication, using the scratch database of db, for
+// the examples signing in as a record user.
+func connectAnonymous(db *surrealdb.DB) *surrealdb.DB {
+	state := db.SessionState()
+	client, err := surrealdb.Connect(context.Background(), endpoint(),
+		surrealdb.WithNamespace(state.Namespace, state.Database),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+func endpoint() string {
+	if endpoint := os.Getenv("SURREALDB_URL"); endpoint != "" {
+		return endpoint
+	}
+	return "ws://localhost:8000"
+}
+
+// defineAccount defines the account record access, letting users sign up and sign in with a name
+// and a password.
+func defineAccount(db *surrealdb.DB) {
+	_, err := surrealdb.Query[interface{}](db, `DEFINE ACCESS account ON DATABASE TYPE RECORD
+		SIGNUP (CREATE user SET name = $user, pass = crypto::argon2::generate($pass))
+		SIGNIN (SELECT * FROM user WHERE name = $user AND crypto::argon2::compare(pass, $pass))`, map[string]interface{}{})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func Example_create() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	person, err := surrealdb.Create[Person](db, models.NewRecordID("person", "tobie"), Person{
+		Name:    "Tobie",
+		Surname: "Morgan Hitchcock",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(person.ID.String(), person.Name, person.Surname)
+	// Output: person:tobie Tobie Morgan Hitchcock
+}
+
+func Example_select() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	for _, name := range []string{"jaime", "tobie"} {
+		if _, err := surrealdb.Create[Person](db, models.NewRecordID("person", name), Person{Name: name}); err != nil {
+			panic(err)
+		}
+	}
+
+	// a single record
+	person, err := surrealdb.Select[Person](db, models.NewRecordID("person", "tobie"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(person.Name)
+
+	// a whole table
+	people, err := surrealdb.Select[[]Person](db, models.Table("person"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(*people))
+	// Output:
+	// tobie
+	// 2
+}
+
+func Example_insert() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	people, err := surrealdb.Insert[Person](db, models.Table("person"), []Person{
+		{Name: "Tobie"},
+		{Name: "Jaime"},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(len(*people))
+	// Output: 2
+}
+
+func Example_upsert() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	id := models.NewRecordID("person", "tobie")
+	for _, age := range []int{33, 34} {
+		person, err := surrealdb.Upsert[Person](db, id, Person{Name: "Tobie", Age: age})
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(person.Name, person.Age)
+	}
+	// Output:
+	// Tobie 33
+	// Tobie 34
+}
+
+func Example_update() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	id := models.NewRecordID("person", "tobie")
+	if _, err := surrealdb.Create[Person](db, id, Person{Name: "Tobie", Surname: "Morgan Hitchcock"}); err != nil {
+		panic(err)
+	}
+
+	// update replaces the whole record
+	person, err := surrealdb.Update[Person](db, id, Person{Name: "Tobie"})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%s %q\n", person.Name, person.Surname)
+	// Output: Tobie ""
+}
+
+func Example_merge() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	id := models.NewRecordID("person", "tobie")
+	if _, err := surrealdb.Create[Person](db, id, Person{Name: "Tobie", Surname: "Morgan Hitchcock"}); err != nil {
+		panic(err)
+	}
+
+	// merge only changes the given fields
+	person, err := surrealdb.Merge[Person](db, id, map[string]interface{}{"age": 34})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(person.Name, person.Surname, person.Age)
+	// Output: Tobie Morgan Hitchcock 34
+}
+
+func Example_patch() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	id := models.NewRecordID("person", "tobie")
+	if _, err := surrealdb.Create[Person](db, id, Person{Name: "Tobie"}); err != nil {
+		panic(err)
+	}
+
+	_, err := surrealdb.Patch(db, id, []surrealdb.PatchData{
+		{Op: surrealdb.PatchAdd, Path: "/age", Value: 34},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	person, err := surrealdb.Select[Person](db, id)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(person.Name, person.Age)
+	// Output: Tobie 34
+}
+
+func Example_delete() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	id := models.NewRecordID("person", "tobie")
+	if _, err := surrealdb.Create[Person](db, id, Person{Name: "Tobie"}); err != nil {
+		panic(err)
+	}
+
+	deleted, err := surrealdb.Delete[Person](db, id)
+	if err != nil {
+		panic(err)
+	}
+
+	people, err := surrealdb.Select[[]Person](db, models.Table("person"))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(deleted.Name, len(*people))
+	// Output: Tobie 0
+}
+
+func Example_relate() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	tobie := models.NewRecordID("person", "tobie")
+	jaime := models.NewRecordID("person", "jaime")
+	for _, id := range []models.RecordID{tobie, jaime} {
+		if _, err := surrealdb.Create[Person](db, id, Person{Name: id.ID.(string)}); err != nil {
+			panic(err)
+		}
+	}
+
+	rel := surrealdb.Relationship{In: tobie, Out: jaime, Relation: "knows"}
+	if err := surrealdb.Relate(db, &rel); err != nil {
+		panic(err)
+	}
+
+	known, err := surrealdb.Query[[]models.RecordID](db, "SELECT VALUE ->knows->person FROM ONLY $id", map[string]interface{}{
+		"id": tobie,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(rel.ID.Table, (*known)[0].Result[0].String())
+	// Output: knows person:jaime
+}
+
+func Example_insertRelation() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	rel := surrealdb.Relationship{
+		ID:       &models.RecordID{Table: "knows", ID: "since_2020"},
+		In:       models.NewRecordID("person", "tobie"),
+		Out:      models.NewRecordID("person", "jaime"),
+		Relation: "knows",
+		Data:     map[string]any{"since": 2020},
+	}
+	if err := surrealdb.InsertRelation(db, &rel); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(rel.ID.String())
+	// Output: knows:since_2020
+}
+
+func Example_query() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	res, err := surrealdb.Query[[]Person](db, "CREATE person:tobie SET name = $name; SELECT * FROM person", map[string]interface{}{
+		"name": "Tobie",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, statement := range *res {
+		fmt.Println(statement.Status, len(statement.Result))
+	}
+	// Output:
+	// OK 1
+	// OK 1
+}
+
+func Example_queryRaw() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	queries := []surrealdb.QueryStmt{
+		{SQL: "CREATE person:tobie SET name = 'Tobie'"},
+		{SQL: "SELECT VALUE name FROM type::table($tb)", Vars: map[string]interface{}{"tb": "person"}},
+	}
+	if err := surrealdb.QueryRaw(db, &queries); err != nil {
+		panic(err)
+	}
+
+	var names []string
+	if err := queries[1].GetResult(&names); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(names)
+	// Output: [Tobie]
+}
+
+func Example_letAndUnset() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	if err := db.Let("name", "Tobie"); err != nil {
+		panic(err)
+	}
+
+	res, err := surrealdb.Query[string](db, "RETURN $name", map[string]interface{}{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println((*res)[0].Result)
+
+	if err := db.Unset("name"); err != nil {
+		panic(err)
+	}
+	// Output: Tobie
+}
+
+func Example_liveAndKill() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	live, err := surrealdb.Live(db, "person", false)
+	if err != nil {
+		panic(err)
+	}
+
+	notifications, err := db.LiveNotifications(live.String())
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err := surrealdb.Create[Person](db, models.NewRecordID("person", "tobie"), Person{Name: "Tobie"}); err != nil {
+		panic(err)
+	}
+
+	notification := <-notifications
+	fmt.Println(notification.Action)
+
+	if err := surrealdb.Kill(db, live.String()); err != nil {
+		panic(err)
+	}
+	// Output: CREATE
+}
+
+func Example_version() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	version, err := db.Version()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(version.Version != "")
+	// Output: true
+}
+
+func Example_run() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	upper, err := surrealdb.Run[string](db, "string::uppercase", "tobie")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(*upper)
+
+	_, err = surrealdb.Query[interface{}](db, `DEFINE FUNCTION fn::greet($name: string) { RETURN "Hello, " + $name; }`,
+		map[string]interface{}{})
+	if err != nil {
+		panic(err)
+	}
+	greeting, err := surrealdb.Run[string](db, "fn::greet", "Tobie")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(*greeting)
+	// Output:
+	// TOBIE
+	// Hello, Tobie
+}
+
+func Example_signUpAndSignIn() {
+	db, cleanup := connect()
+	defer cleanup()
+	defineAccount(db)
+
+	state := db.SessionState()
+	auth := &surrealdb.Auth{
+		Namespace: state.Namespace,
+		Database:  state.Database,
+		Access:    "account",
+		Username:  "tobie",
+		Password:  "secret",
+	}
+
+	client := connectAnonymous(db)
+	defer client.Close()
+	if _, err := client.SignUp(auth); err != nil {
+		panic(err)
+	}
+
+	other := connectAnonymous(db)
+	defer other.Close()
+	token, err := other.SignIn(auth)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(token != "")
+	// Output: true
+}
+
+func Example_info() {
+	db, cleanup := connect()
+	defer cleanup()
+	defineAccount(db)
+
+	state := db.SessionState()
+	client := connectAnonymous(db)
+	defer client.Close()
+	_, err := client.SignUp(&surrealdb.Auth{
+		Namespace: state.Namespace,
+		Database:  state.Database,
+		Access:    "account",
+		Username:  "tobie",
+		Password:  "secret",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// info returns the record of the signed in user
+	info, err := client.Info()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(info["name"])
+	// Output: tobie
+}
+
+func Example_authenticateAndInvalidate() {
+	db, cleanup := connect()
+	defer cleanup()
+	defineAccount(db)
+
+	state := db.SessionState()
+	signup := connectAnonymous(db)
+	defer signup.Close()
+	token, err := signup.SignUp(&surrealdb.Auth{
+		Namespace: state.Namespace,
+		Database:  state.Database,
+		Access:    "account",
+		Username:  "tobie",
+		Password:  "secret",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	client := connectAnonymous(db)
+	defer client.Close()
+	if err := client.Authenticate(token); err != nil {
+		panic(err)
+	}
+	// the user is read from the token, which identifies the record of the user
+	fmt.Println(client.SessionState().User[:len("user:")])
+
+	if err := client.Invalidate(); err != nil {
+		panic(err)
+	}
+	fmt.Println(client.SessionState().Token == "")
+	// Output:
+	// user:
+	// true
+}
+
+// The graphql method needs a server started with the experimental GraphQL feature, so this
+// example is compiled but not run.
+func Example_graphQL() {
+	db, cleanup := connect()
+	defer cleanup()
+
+	_, err := surrealdb.Query[interface{}](db, "DEFINE CONFIG GRAPHQL AUTO", map[string]interface{}{})
+	if err != nil {
+		panic(err)
+	}
+	if _, err := surrealdb.Create[Person](db, models.NewRecordID("person", "tobie"), Person{Name: "Tobie"}); err != nil {
+		panic(err)
+	}
+
+	res, err := db.GraphQL("{ person { name } }", nil)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(res.Data["person"])
+}
@@ -0,0 +1,42 @@
+//go:build js && wasm
+
+// Command wasm queries SurrealDB from a browser, with the http engine. Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o main.wasm ./examples/wasm
+//
+// and load main.wasm with the wasm_exec.js support file of the Go distribution, found in
+// $(go env GOROOT)/misc/wasm or $(go env GOROOT)/lib/wasm. The server must accept cross-origin
+// requests from the page.
+package main
+
+import (
+	"context"
+	"fmt"
+
+	surrealdb "github.com/surrealdb/surrealdb.go"
+)
+
+type Person struct {
+	Name string `json:"name"`
+}
+
+func main() {
+	db, err := surrealdb.Connect(context.Background(), "http://localhost:8000",
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithAuth(&surrealdb.Auth{Username: "root", Password: "root"}),
+	)
+	if err != nil {
+		fmt.Println("connecting:", err)
+		return
+	}
+	defer db.Close()
+
+	res, err := surrealdb.Query[[]Person](db, "SELECT name FROM person LIMIT 10", nil)
+	if err != nil {
+		fmt.Println("querying:", err)
+		return
+	}
+	for _, person := range (*res)[0].Result {
+		fmt.Println(person.Name)
+	}
+}
@@ -0,0 +1,77 @@
+package surrealdb
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Fields checks the field paths given to the helpers of this package against the fields of the
+// struct T, named by their tags like when decoding, so that a misspelt field fails when the query
+// is built rather than matching no record. The id field is always valid.
+//
+// Conditions and projections are SurrealQL, which is not checked: build them from the paths
+// returned by Path, with the condition helpers such as GeoWithinDistance or Matches.
+//
+//	users, err := surrealdb.FieldsOf[User]()
+//	location, err := users.Path("address.location")
+//	near, err := surrealdb.GeoWithinDistance(location, point, 1000)
+type Fields[T any] struct {
+	t reflect.Type
+}
+
+// FieldsOf returns the Fields of T, which must be a struct or a pointer to a struct.
+func FieldsOf[T any]() (*Fields[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if _, err := models.FieldNames(t); err != nil {
+		return nil, err
+	}
+	return &Fields[T]{t: t}, nil
+}
+
+// Path returns path once checked to be a field path of T.
+func (f *Fields[T]) Path(path string) (string, error) {
+	if path == "id" {
+		return path, nil
+	}
+	if err := models.CheckFieldPath(f.t, path); err != nil {
+		return "", fmt.Errorf("invalid field path %q: %w", path, err)
+	}
+	return path, nil
+}
+
+// CheckSelection checks the Omit, Split, OrderBy and Fetch fields of sel.
+func (f *Fields[T]) CheckSelection(sel Selection) error {
+	paths := append(append(append([]string{}, sel.Omit...), sel.Split...), sel.Fetch...)
+	if sel.OrderBy != "" {
+		paths = append(paths, sel.OrderBy)
+	}
+	return f.check(paths)
+}
+
+// CheckAssignments checks the fields assigned by assignments.
+func (f *Fields[T]) CheckAssignments(assignments ...Assignment) error {
+	paths := make([]string, len(assignments))
+	for i, a := range assignments {
+		paths[i] = a.Field
+	}
+	return f.check(paths)
+}
+
+// CheckPagination checks the OrderBy field of p.
+func (f *Fields[T]) CheckPagination(p Pagination) error {
+	if p.OrderBy == "" {
+		return nil
+	}
+	return f.check([]string{p.OrderBy})
+}
+
+func (f *Fields[T]) check(paths []string) error {
+	for _, path := range paths {
+		if _, err := f.Path(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
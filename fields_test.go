@@ -0,0 +1,46 @@
+package surrealdb_test
+
+import (
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldsOf(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type user struct {
+		Name    string    `json:"name"`
+		Logins  int       `json:"logins" surreal:"login_count"`
+		Address address   `json:"address"`
+		Past    []address `json:"past"`
+	}
+
+	users, err := surrealdb.FieldsOf[user]()
+	require.NoError(t, err)
+
+	path, err := users.Path("address.city")
+	require.NoError(t, err)
+	require.Equal(t, "address.city", path)
+	for _, valid := range []string{"id", "name", "login_count", "past.city"} {
+		_, err := users.Path(valid)
+		require.NoError(t, err, valid)
+	}
+	for _, invalid := range []string{"nmae", "logins", "address.town", "name.first"} {
+		_, err := users.Path(invalid)
+		require.Error(t, err, invalid)
+	}
+
+	require.NoError(t, users.CheckSelection(surrealdb.Selection{Omit: []string{"past"}, OrderBy: "name", Fetch: []string{"address"}}))
+	require.ErrorContains(t, users.CheckSelection(surrealdb.Selection{OrderBy: "created_at"}), "created_at")
+	require.NoError(t, users.CheckAssignments(surrealdb.SetExpr("login_count", "login_count + 1")))
+	require.Error(t, users.CheckAssignments(surrealdb.Set("name", "ann"), surrealdb.Set("email", "ann@example.com")))
+	require.NoError(t, users.CheckPagination(surrealdb.Pagination{Table: "user", OrderBy: "address.city"}))
+	require.Error(t, users.CheckPagination(surrealdb.Pagination{Table: "user", OrderBy: "city"}))
+
+	_, err = surrealdb.FieldsOf[string]()
+	require.Error(t, err)
+}
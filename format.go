@@ -0,0 +1,288 @@
+package surrealdb
+
+import (
+	"strings"
+	"unicode"
+)
+
+// formatKeywords are the keywords written in upper case by NormalizeSurrealQL and FormatSurrealQL.
+// Words that are also common field names, such as value or type, are left as written.
+var formatKeywords = map[string]bool{
+	"AFTER": true, "ALL": true, "ALLINSIDE": true, "AND": true, "ANYINSIDE": true, "AS": true, "ASC": true,
+	"BEFORE": true, "BEGIN": true, "BREAK": true, "BY": true, "CANCEL": true, "COLLATE": true, "COMMIT": true,
+	"CONTAINS": true, "CONTAINSALL": true, "CONTAINSANY": true, "CONTAINSNONE": true, "CONTAINSNOT": true,
+	"CONTENT": true, "CONTINUE": true, "CREATE": true, "DATABASE": true, "DB": true, "DEFINE": true,
+	"DELETE": true, "DESC": true, "DIFF": true, "DUPLICATE": true, "ELSE": true, "END": true, "EXPLAIN": true,
+	"FETCH": true, "FIELD": true, "FOR": true, "FROM": true, "FULL": true, "GROUP": true, "IF": true,
+	"IGNORE": true, "IN": true, "INDEX": true, "INFO": true, "INSERT": true, "INSIDE": true,
+	"INTERSECTS": true, "INTO": true, "IS": true, "KILL": true, "LET": true, "LIMIT": true, "LIVE": true,
+	"MERGE": true, "NAMESPACE": true, "NOINDEX": true, "NONE": true, "NONEINSIDE": true, "NOT": true,
+	"NS": true, "NULL": true, "NUMERIC": true, "ON": true, "ONLY": true, "OR": true, "ORDER": true,
+	"OUTSIDE": true, "PARALLEL": true, "PATCH": true, "RAND": true, "RELATE": true, "REMOVE": true,
+	"REPLACE": true, "RETURN": true, "SELECT": true, "SET": true, "SLEEP": true, "SPLIT": true,
+	"START": true, "TABLE": true, "THEN": true, "THROW": true, "TIMEOUT": true, "TRANSACTION": true,
+	"UNSET": true, "UPDATE": true, "UPSERT": true, "USE": true, "VALUES": true, "WHERE": true, "WITH": true,
+}
+
+// formatClauses are the keywords FormatSurrealQL starts a new line with, when they follow the
+// first word of a statement outside of any parenthesis or block.
+var formatClauses = map[string]bool{
+	"CONTENT": true, "EXPLAIN": true, "FETCH": true, "FROM": true, "GROUP": true, "LIMIT": true,
+	"MERGE": true, "ORDER": true, "PARALLEL": true, "PATCH": true, "REPLACE": true, "RETURN": true,
+	"SET": true, "SPLIT": true, "START": true, "TIMEOUT": true, "UNSET": true, "WHERE": true,
+	"WITH": true,
+}
+
+type formatTokenKind int
+
+const (
+	formatSpace formatTokenKind = iota
+	formatWord
+	formatString
+	formatIdent
+	formatParam
+	formatPunct
+)
+
+type formatToken struct {
+	kind formatTokenKind
+	text string
+}
+
+// NormalizeSurrealQL rewrites sql in a canonical form, so that queries that differ only by their
+// layout compare equal: comments are removed, runs of spaces and newlines become a single space,
+// keywords are upper case, strings are single quoted and identifiers are escaped with ⟨⟩. Every
+// statement ends with a semicolon, on its own line. Strings, identifiers and the rest of the
+// query are otherwise left as written.
+func NormalizeSurrealQL(sql string) string {
+	return formatSurrealQL(sql, false)
+}
+
+// FormatSurrealQL normalizes sql like NormalizeSurrealQL, and starts the clauses of statements,
+// such as FROM, WHERE or ORDER BY, on a new indented line, for queries to be read by people.
+func FormatSurrealQL(sql string) string {
+	return formatSurrealQL(sql, true)
+}
+
+func formatSurrealQL(sql string, pretty bool) string {
+	tokens := lexSurrealQL(sql)
+
+	var b strings.Builder
+	depth := 0
+	statementStart := true
+	pendingSpace := false
+	for i, tok := range tokens {
+		if tok.kind == formatSpace {
+			pendingSpace = !statementStart
+			continue
+		}
+
+		text := tok.text
+		if tok.kind == formatWord && isFormatKeyword(tokens, i) {
+			text = strings.ToUpper(text)
+		}
+
+		switch {
+		case statementStart:
+		case pretty && depth == 0 && tok.kind == formatWord && formatClauses[text] && isFormatKeyword(tokens, i):
+			b.WriteString("\n  ")
+		case pendingSpace && !noSpaceBefore(text) && !noSpaceAfter(tokens, i):
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		statementStart = false
+
+		if tok.kind == formatPunct {
+			switch text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			case ";":
+				if depth <= 0 {
+					b.WriteString(";\n")
+					depth = 0
+					statementStart = true
+					continue
+				}
+			}
+		}
+		b.WriteString(text)
+	}
+
+	if !statementStart {
+		b.WriteString(";\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// isFormatKeyword reports whether the word at i is a keyword, rather than a field, a function or
+// the table of a record id.
+func isFormatKeyword(tokens []formatToken, i int) bool {
+	if !formatKeywords[strings.ToUpper(tokens[i].text)] {
+		return false
+	}
+	if i > 0 {
+		switch tokens[i-1].text {
+		case ".", ":":
+			return false
+		}
+	}
+	if i+1 < len(tokens) {
+		switch tokens[i+1].text {
+		case ":", "(":
+			return false
+		}
+	}
+	return true
+}
+
+// noSpaceBefore reports whether a space before text is removed.
+func noSpaceBefore(text string) bool {
+	switch text {
+	case ",", ";", ")", "]":
+		return true
+	default:
+		return false
+	}
+}
+
+// noSpaceAfter reports whether the space before the token at i is removed because of the token
+// before it.
+func noSpaceAfter(tokens []formatToken, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		if tokens[j].kind == formatSpace {
+			continue
+		}
+		return tokens[j].text == "(" || tokens[j].text == "["
+	}
+	return false
+}
+
+// lexSurrealQL splits sql into tokens. Comments become spaces, strings are single quoted and
+// identifiers escaped with backticks are escaped with ⟨⟩ instead.
+func lexSurrealQL(sql string) []formatToken {
+	var tokens []formatToken
+	runes := []rune(sql)
+
+	space := func() {
+		if len(tokens) == 0 || tokens[len(tokens)-1].kind != formatSpace {
+			tokens = append(tokens, formatToken{kind: formatSpace, text: " "})
+		}
+	}
+	quoted := func(from int, closing rune) int {
+		i := from + 1
+		for i < len(runes) && runes[i] != closing {
+			if runes[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(runes) {
+			return len(runes)
+		}
+		return i + 1
+	}
+	word := func(from int) int {
+		to := from
+		for to < len(runes) && (unicode.IsLetter(runes[to]) || unicode.IsDigit(runes[to]) || runes[to] == '_') {
+			to++
+		}
+		return to
+	}
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			space()
+			i++
+		case c == '-' && i+1 < len(runes) && runes[i+1] == '-',
+			c == '/' && i+1 < len(runes) && runes[i+1] == '/',
+			c == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			space()
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+			if i > len(runes) {
+				i = len(runes)
+			}
+			space()
+		case c == '\'':
+			end := quoted(i, '\'')
+			tokens = append(tokens, formatToken{kind: formatString, text: string(runes[i:end])})
+			i = end
+		case c == '"':
+			end := quoted(i, '"')
+			tokens = append(tokens, formatToken{kind: formatString, text: singleQuoted(runes[i:end])})
+			i = end
+		case c == '⟨':
+			end := quoted(i, '⟩')
+			tokens = append(tokens, formatToken{kind: formatIdent, text: string(runes[i:end])})
+			i = end
+		case c == '`':
+			end := quoted(i, '`')
+			tokens = append(tokens, formatToken{kind: formatIdent, text: angleQuoted(runes[i:end])})
+			i = end
+		case c == '$':
+			end := word(i + 1)
+			tokens = append(tokens, formatToken{kind: formatParam, text: string(runes[i:end])})
+			i = end
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_':
+			end := word(i)
+			tokens = append(tokens, formatToken{kind: formatWord, text: string(runes[i:end])})
+			i = end
+		default:
+			tokens = append(tokens, formatToken{kind: formatPunct, text: string(c)})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// singleQuoted rewrites a double quoted string, quotes included, as a single quoted string.
+func singleQuoted(s []rune) string {
+	return requote(s, '"', '\'', "'", "'")
+}
+
+// angleQuoted rewrites an identifier escaped with backticks as an identifier escaped with ⟨⟩.
+func angleQuoted(s []rune) string {
+	return requote(s, '`', '⟩', "⟨", "⟩")
+}
+
+// requote replaces the quotes around s, unescaping the old closing quote and escaping the new one.
+func requote(s []rune, oldClosing, newClosing rune, open, close string) string {
+	if len(s) < 2 || s[len(s)-1] != oldClosing {
+		// unterminated, left as written
+		return string(s)
+	}
+
+	var b strings.Builder
+	b.WriteString(open)
+	content := s[1 : len(s)-1]
+	for i := 0; i < len(content); i++ {
+		switch {
+		case content[i] == '\\' && i+1 < len(content) && content[i+1] == oldClosing:
+			b.WriteRune(oldClosing)
+			i++
+		case content[i] == '\\' && i+1 < len(content):
+			b.WriteRune(content[i])
+			b.WriteRune(content[i+1])
+			i++
+		case content[i] == newClosing:
+			b.WriteRune('\\')
+			b.WriteRune(newClosing)
+		default:
+			b.WriteRune(content[i])
+		}
+	}
+	b.WriteString(close)
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package surrealdb_test
+
+import (
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSurrealQL(t *testing.T) {
+	sql := `select name, count() as total   -- per city
+		from person where age > 18 and city = "it's \"here\"" and tags contains 'a;b'
+		group   by name order by total desc limit 10;
+	let $x = (select * from ` + "`my table`" + ` where string::lowercase(name) = $name) ;
+	CREATE person:select CONTENT { from: 1, value: 2 }`
+
+	require.Equal(t, "SELECT name, count() AS total FROM person WHERE age > 18 AND city = 'it\\'s \"here\"' "+
+		"AND tags CONTAINS 'a;b' GROUP BY name ORDER BY total DESC LIMIT 10;\n"+
+		"LET $x = (SELECT * FROM ⟨my table⟩ WHERE string::lowercase(name) = $name);\n"+
+		"CREATE person:select CONTENT { from: 1, value: 2 };",
+		surrealdb.NormalizeSurrealQL(sql))
+
+	require.Equal(t, "SELECT name, count() AS total\n"+
+		"  FROM person\n"+
+		"  WHERE age > 18 AND city = 'it\\'s \"here\"' AND tags CONTAINS 'a;b'\n"+
+		"  GROUP BY name\n"+
+		"  ORDER BY total DESC\n"+
+		"  LIMIT 10;\n"+
+		"LET $x = (SELECT * FROM ⟨my table⟩ WHERE string::lowercase(name) = $name);\n"+
+		"CREATE person:select\n"+
+		"  CONTENT { from: 1, value: 2 };",
+		surrealdb.FormatSurrealQL(sql))
+
+	require.Equal(t, surrealdb.NormalizeSurrealQL("SELECT * FROM a WHERE b = 1"),
+		surrealdb.NormalizeSurrealQL("select *\n  from a\n  where b = 1;\n"))
+	require.Equal(t, "", surrealdb.NormalizeSurrealQL("  -- nothing\n"))
+}
@@ -0,0 +1,47 @@
+package surrealdb
+
+import (
+	"fmt"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// GeoWithinDistance returns the condition that the point held by field lies within meters of point,
+// as computed by geo::distance. Field may be a path such as address.location.
+//
+//	near, err := surrealdb.GeoWithinDistance("location", models.NewGeometryPoint(51.5, -0.12), 1000)
+//	res, err := surrealdb.Query[[]Shop](db, "SELECT * FROM shop WHERE "+near.SQL, near.Vars)
+func GeoWithinDistance(field string, point models.GeometryPoint, meters float64) (Condition, error) {
+	return geoCondition(field, "geo::distance(%s, $%s_point) <= $%[2]s_distance", map[string]interface{}{
+		"point":    &point,
+		"distance": meters,
+	})
+}
+
+// GeoInside returns the condition that the geometry held by field lies inside area.
+func GeoInside(field string, area models.Area) (Condition, error) {
+	return geoCondition(field, "%s INSIDE $%s_area", map[string]interface{}{"area": area})
+}
+
+// GeoIntersects returns the condition that the geometry held by field intersects geometry, which
+// may be any geometry type of the models package.
+func GeoIntersects(field string, geometry interface{}) (Condition, error) {
+	return geoCondition(field, "%s INTERSECTS $%s_geometry", map[string]interface{}{"geometry": geometry})
+}
+
+// geoCondition formats sql with the escaped field and the prefix of the variables, which are named
+// after the field.
+func geoCondition(field, sql string, vars map[string]interface{}) (Condition, error) {
+	escaped, err := models.EscapeFieldPath(field)
+	if err != nil {
+		return Condition{}, err
+	}
+
+	prefix := "geo_" + fieldVarName(field)
+
+	c := Condition{SQL: fmt.Sprintf(sql, escaped, prefix), Vars: make(map[string]interface{}, len(vars))}
+	for name, value := range vars {
+		c.Vars[prefix+"_"+name] = value
+	}
+	return c, nil
+}
@@ -0,0 +1,32 @@
+package surrealdb_test
+
+import (
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestGeoConditions(t *testing.T) {
+	near, err := surrealdb.GeoWithinDistance("address.location", models.NewGeometryPoint(51.5, -0.12), 1000)
+	require.NoError(t, err)
+	require.Equal(t, "geo::distance(address.location, $geo_address_location_point) <= $geo_address_location_distance", near.SQL)
+	require.Equal(t, 1000.0, near.Vars["geo_address_location_distance"])
+
+	inside, err := surrealdb.GeoInside("location", models.NewBoundingBox(models.NewGeometryPoint(51.5, -0.12), 5000))
+	require.NoError(t, err)
+	require.Equal(t, "location INSIDE $geo_location_area", inside.SQL)
+
+	both, err := surrealdb.And(near, inside)
+	require.NoError(t, err)
+	require.Equal(t, "("+near.SQL+") AND ("+inside.SQL+")", both.SQL)
+	require.Len(t, both.Vars, 3)
+
+	_, err = surrealdb.And(inside, inside)
+	require.Error(t, err)
+
+	_, err = surrealdb.GeoIntersects("", models.GeometryPolygon{})
+	require.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package surrealdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// RelateEdge creates an edge of the relation table from in to out with the relate method, and
+// returns it decoded into TEdge. data, which may be nil, holds the fields of the edge besides in
+// and out.
+//
+//	edge, err := surrealdb.RelateEdge[Wrote](db, user, "wrote", post, map[string]any{"at": time.Now()})
+func RelateEdge[TEdge any](db *DB, in models.RecordID, relation models.Table, out models.RecordID, data interface{}) (*TEdge, error) {
+	var res connection.RPCResponse[TEdge]
+	if err := db.send(&res, "relate", in, relation, out, data); err != nil {
+		return nil, err
+	}
+
+	return res.Result, nil
+}
+
+// Traverse follows path from the record start and returns the records it leads to, decoded into
+// TResult. path is a sequence of steps such as ->wrote->post or <-owns<-user, where each step is
+// ->, <- or <-> followed by a table name made of letters, digits and underscores, or ? for any
+// table.
+//
+//	posts, err := surrealdb.Traverse[Post](db, models.NewRecordID("user", "john"), "->wrote->post")
+func Traverse[TResult any](db *DB, start models.RecordID, path string) ([]TResult, error) {
+	escaped, err := escapeGraphPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := querySingle[[]TResult](db, "SELECT * FROM $start"+escaped, map[string]interface{}{"start": start})
+	if err != nil {
+		return nil, err
+	}
+	return *records, nil
+}
+
+// escapeGraphPath checks that path is made of graph steps and escapes their table names.
+func escapeGraphPath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("empty graph path")
+	}
+
+	var b strings.Builder
+	for rest := path; rest != ""; {
+		var arrow string
+		for _, a := range []string{"<->", "->", "<-"} {
+			if strings.HasPrefix(rest, a) {
+				arrow = a
+				break
+			}
+		}
+		if arrow == "" {
+			return "", fmt.Errorf("invalid graph path %q: expected ->, <- or <-> before %q", path, rest)
+		}
+		rest = rest[len(arrow):]
+
+		end := strings.IndexAny(rest, "<-")
+		if end < 0 {
+			end = len(rest)
+		}
+		table := rest[:end]
+		rest = rest[end:]
+
+		b.WriteString(arrow)
+		if table == "?" {
+			b.WriteString(table)
+			continue
+		}
+		if !isPlaceholderName(table) {
+			return "", fmt.Errorf("invalid graph path %q: invalid table name %q", path, table)
+		}
+		b.WriteString("⟨" + table + "⟩")
+	}
+
+	return b.String(), nil
+}
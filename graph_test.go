@@ -0,0 +1,123 @@
+package surrealdb_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestRelateEdgeAndTraverse(t *testing.T) {
+	var lock sync.Mutex
+	var requests []connection.RPCRequest
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		requests = append(requests, req.RPCRequest)
+		lock.Unlock()
+
+		switch req.Method {
+		case "relate":
+			return map[string]interface{}{
+				"id":  models.NewRecordID("wrote", "w1"),
+				"in":  req.Params[0],
+				"out": req.Params[2],
+				"at":  "today",
+			}, nil
+		case "query":
+			return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": []interface{}{
+				map[string]interface{}{"id": models.NewRecordID("post", "p1"), "title": "Hello"},
+			}}}, nil
+		}
+		return nil, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	type wrote struct {
+		ID  *models.RecordID `json:"id"`
+		In  models.RecordID  `json:"in"`
+		Out models.RecordID  `json:"out"`
+		At  string           `json:"at"`
+	}
+	user, post := models.NewRecordID("user", "john"), models.NewRecordID("post", "p1")
+	edge, err := surrealdb.RelateEdge[wrote](db, user, "wrote", post, map[string]interface{}{"at": "today"})
+	require.NoError(t, err)
+	require.Equal(t, "w1", edge.ID.ID)
+	require.Equal(t, user, edge.In)
+	require.Equal(t, post, edge.Out)
+	require.Equal(t, "today", edge.At)
+
+	type article struct {
+		ID    *models.RecordID `json:"id"`
+		Title string           `json:"title"`
+	}
+	posts, err := surrealdb.Traverse[article](db, user, "->wrote->post")
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	require.Equal(t, "Hello", posts[0].Title)
+
+	_, err = surrealdb.Traverse[article](db, user, "<-owns<-?<->tagged")
+	require.NoError(t, err)
+
+	for _, path := range []string{"", "wrote", "->wrote->", "->wrote=>post"} {
+		_, err = surrealdb.Traverse[article](db, user, path)
+		require.Error(t, err, path)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, requests, 3)
+	require.Equal(t, "SELECT * FROM $start->⟨wrote⟩->⟨post⟩", requests[1].Params[0])
+	require.Equal(t, "SELECT * FROM $start<-⟨owns⟩<-?<->⟨tagged⟩", requests[2].Params[0])
+}
+
+func TestInsertRelations(t *testing.T) {
+	var lock sync.Mutex
+	var requests []connection.RPCRequest
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		requests = append(requests, req.RPCRequest)
+		lock.Unlock()
+
+		var edges []interface{}
+		for i, edge := range req.Params[1].([]interface{}) {
+			edge := edge.(map[interface{}]interface{})
+			edges = append(edges, map[string]interface{}{
+				"id":  models.NewRecordID("follows", fmt.Sprint(i)),
+				"in":  edge["in"],
+				"out": edge["out"],
+			})
+		}
+		return edges, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	type follows struct {
+		ID  *models.RecordID `json:"id,omitempty"`
+		In  models.RecordID  `json:"in"`
+		Out models.RecordID  `json:"out"`
+	}
+	alice, bob := models.NewRecordID("user", "alice"), models.NewRecordID("user", "bob")
+	edges, err := surrealdb.InsertRelations[follows](db, "follows", []follows{{In: alice, Out: bob}, {In: bob, Out: alice}})
+	require.NoError(t, err)
+	require.Equal(t, []follows{
+		{ID: &models.RecordID{Table: "follows", ID: "0"}, In: alice, Out: bob},
+		{ID: &models.RecordID{Table: "follows", ID: "1"}, In: bob, Out: alice},
+	}, *edges)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, requests, 1)
+	require.Equal(t, "insert_relation", requests[0].Method)
+	require.Equal(t, models.Table("follows"), requests[0].Params[0])
+}
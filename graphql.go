@@ -0,0 +1,77 @@
+package surrealdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+)
+
+// GraphQLResponse is the response to a GraphQL request.
+type GraphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors GraphQLErrors          `json:"errors,omitempty"`
+}
+
+// GraphQLError is an error reported in a GraphQL response.
+type GraphQLError struct {
+	Message   string                 `json:"message"`
+	Path      []interface{}          `json:"path,omitempty"`
+	Locations []GraphQLErrorLocation `json:"locations,omitempty"`
+}
+
+// GraphQLErrorLocation locates a GraphQL error in the request.
+type GraphQLErrorLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLErrors are the errors of a GraphQL response, returned as an error by DB.GraphQL.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return fmt.Sprintf("graphql: %s", strings.Join(messages, "; "))
+}
+
+// GraphQL runs a GraphQL request against the selected namespace and database. GraphQL must be
+// enabled on the server, with DEFINE CONFIG GRAPHQL. When the response holds errors, they are
+// returned as GraphQLErrors along with the response, which may hold partial data.
+func (db *DB) GraphQL(query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+	request := map[string]interface{}{"query": query}
+	if len(variables) > 0 {
+		request["variables"] = variables
+	}
+
+	var res connection.RPCResponse[cbor.RawMessage]
+	if err := db.send(&res, "graphql", request, map[string]interface{}{"format": "json"}); err != nil {
+		return nil, err
+	}
+	if res.Result == nil {
+		return nil, fmt.Errorf("graphql: empty response")
+	}
+
+	var response GraphQLResponse
+	unmarshaler := db.con.GetUnmarshaler()
+
+	// the response is a JSON document, unless the server encoded it with the RPC format
+	var document string
+	if err := unmarshaler.Unmarshal(*res.Result, &document); err == nil {
+		if err := json.Unmarshal([]byte(document), &response); err != nil {
+			return nil, fmt.Errorf("graphql: invalid response: %w", err)
+		}
+	} else if err := connection.DecodeResult(unmarshaler, "graphql", *res.Result, &response); err != nil {
+		db.stats.record(err)
+		return nil, err
+	}
+
+	if len(response.Errors) > 0 {
+		return &response, response.Errors
+	}
+	return &response, nil
+}
@@ -0,0 +1,36 @@
+package surrealdb_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestGraphQL(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		require.Equal(t, "graphql", req.Method)
+
+		if !strings.Contains(fmt.Sprint(req.Params[0]), "age") {
+			return `{"data":{"person":[{"name":"john"}]}}`, nil
+		}
+		return `{"data":null,"errors":[{"message":"Unknown field \"age\"","locations":[{"line":1,"column":12}]}]}`, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	res, err := db.GraphQL("{ person { name } }", nil)
+	require.NoError(t, err)
+	require.Equal(t, "john", res.Data["person"].([]interface{})[0].(map[string]interface{})["name"])
+
+	_, err = db.GraphQL("{ person { age } }", nil)
+	var gqlErrs surrealdb.GraphQLErrors
+	require.ErrorAs(t, err, &gqlErrs)
+	require.Equal(t, 12, gqlErrs[0].Locations[0].Column)
+}
@@ -0,0 +1,70 @@
+package surrealdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Assignment is a field = value assignment, see Set and SetExpr.
+type Assignment struct {
+	Field string
+	// SQL is the value assigned, a SurrealQL expression which may use the Vars.
+	SQL  string
+	Vars map[string]interface{}
+}
+
+// Set returns the assignment of value to field, bound as a variable.
+func Set(field string, value interface{}) Assignment {
+	name := "set_" + fieldVarName(field)
+	return Assignment{Field: field, SQL: "$" + name, Vars: map[string]interface{}{name: value}}
+}
+
+// SetExpr returns the assignment of the SurrealQL expression expr to field, such as visits + 1.
+func SetExpr(field, expr string) Assignment {
+	return Assignment{Field: field, SQL: expr}
+}
+
+// assignments returns the assignments joined by commas, and adds their variables to vars. It fails
+// when two assignments bind the same variable, such as two Set of the same field.
+func assignments(list []Assignment, vars map[string]interface{}) (string, error) {
+	parts := make([]string, len(list))
+	for i, a := range list {
+		field, err := models.EscapeFieldPath(a.Field)
+		if err != nil {
+			return "", err
+		}
+		if a.SQL == "" {
+			return "", fmt.Errorf("no value assigned to %s", a.Field)
+		}
+		for name, value := range a.Vars {
+			if _, bound := vars[name]; bound {
+				return "", fmt.Errorf("variable $%s is bound by several assignments", name)
+			}
+			vars[name] = value
+		}
+		parts[i] = field + " = " + a.SQL
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// InsertOrUpdate inserts the records of data, a record or a slice of records, into a table with a
+// single INSERT statement, like Insert. The records whose id, or the fields of a unique index, are
+// already taken are updated with the assignments instead, where $input is the record that was
+// being inserted.
+//
+//	users, err := surrealdb.InsertOrUpdate[User](db, "user", users,
+//		surrealdb.SetExpr("name", "$input.name"), surrealdb.SetExpr("logins", "logins + 1"))
+func InsertOrUpdate[TResult any](db *DB, what models.Table, data interface{}, onDuplicate ...Assignment) (*[]TResult, error) {
+	if len(onDuplicate) == 0 {
+		return nil, fmt.Errorf("no assignments to update the existing records with")
+	}
+
+	vars := map[string]interface{}{"table": what, "data": data}
+	update, err := assignments(onDuplicate, vars)
+	if err != nil {
+		return nil, err
+	}
+	return querySingle[[]TResult](db, "INSERT INTO $table $data ON DUPLICATE KEY UPDATE "+update, vars)
+}
@@ -0,0 +1,42 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestInsertOrUpdate(t *testing.T) {
+	type user struct {
+		Name   string `json:"name"`
+		Logins int    `json:"logins"`
+	}
+
+	var sql string
+	var vars map[interface{}]interface{}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		sql = req.Params[0].(string)
+		vars = req.Params[1].(map[interface{}]interface{})
+		return []surrealdb.QueryResult[interface{}]{{Status: "OK", Result: []user{{Name: "Ann", Logins: 2}, {Name: "Bob"}}}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	users, err := surrealdb.InsertOrUpdate[user](db, "user", []user{{Name: "Ann"}, {Name: "Bob"}},
+		surrealdb.SetExpr("logins", "logins + 1"), surrealdb.Set("meta.source", "import"))
+	require.NoError(t, err)
+	require.Equal(t, []user{{Name: "Ann", Logins: 2}, {Name: "Bob"}}, *users)
+	require.Equal(t, "INSERT INTO $table $data ON DUPLICATE KEY UPDATE logins = logins + 1, meta.source = $set_meta_source", sql)
+	require.Equal(t, "import", vars["set_meta_source"])
+	require.Len(t, vars["data"], 2)
+
+	_, err = surrealdb.InsertOrUpdate[user](db, "user", user{Name: "Ann"})
+	require.Error(t, err, "an insert without assignments is a plain Insert")
+	_, err = surrealdb.InsertOrUpdate[user](db, "user", user{Name: "Ann"}, surrealdb.Set("name", "a"), surrealdb.Set("name", "b"))
+	require.Error(t, err)
+}
@@ -1,9 +1,13 @@
 package benchmark_test
 
 import (
+	"context"
 	"fmt"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
 	"github.com/surrealdb/surrealdb.go/pkg/models"
 
 	surrealdb "github.com/surrealdb/surrealdb.go"
@@ -53,3 +57,65 @@ func BenchmarkSelect(b *testing.B) {
 		surrealdb.Select[testUser](db, models.NewRecordID("users", "bob")) //nolint:errcheck
 	}
 }
+
+// largeSelectServer returns a server answering every request with rows records
+func largeSelectServer(b *testing.B, rows int) *httptest.Server {
+	records := make([]interface{}, rows)
+	for i := range records {
+		records[i] = map[string]interface{}{
+			"id":       models.NewRecordID("users", i),
+			"username": fmt.Sprintf("user%d", i),
+			"password": "1234",
+		}
+	}
+
+	encoded, err := models.CborMarshaler{}.Marshal(records)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return mock.NewRPCServer(b, func(req mock.RPCRequest) (interface{}, error) {
+		return cbor.RawMessage(encoded), nil
+	})
+}
+
+type largeUser struct {
+	ID       *models.RecordID `json:"id,omitempty"`
+	Username string           `json:"username"`
+	Password string           `json:"password"`
+}
+
+// BenchmarkSelectLarge benchmarks the selection of 1000 records into a new slice
+func BenchmarkSelectLarge(b *testing.B) {
+	server := largeSelectServer(b, 1000)
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := surrealdb.Select[[]largeUser](db, models.Table("users")); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSelectIntoLarge benchmarks the selection of 1000 records into a reused slice
+func BenchmarkSelectIntoLarge(b *testing.B) {
+	server := largeSelectServer(b, 1000)
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var users []largeUser
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := surrealdb.SelectInto(db, &users, models.Table("users")); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Package fixture generates random documents holding every SurrealDB data type, to check that
+// they survive a round trip through the codec, or through the server, unchanged.
+//
+// Values are generated in the form the CBOR unmarshaler decodes them into an interface{}, such as
+// uint64 for positive integers or models.DecimalString for decimals, so that a document decoded
+// back compares equal to the one generated.
+package fixture
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Kind is a SurrealDB data type generated by a Generator.
+type Kind string
+
+const (
+	KindNone     Kind = "none"
+	KindNull     Kind = "null"
+	KindBool     Kind = "bool"
+	KindInt      Kind = "int"
+	KindNegative Kind = "negative"
+	KindFloat    Kind = "float"
+	KindDecimal  Kind = "decimal"
+	KindString   Kind = "string"
+	KindBytes    Kind = "bytes"
+	KindDatetime Kind = "datetime"
+	KindDuration Kind = "duration"
+	KindUUID     Kind = "uuid"
+	KindTable    Kind = "table"
+	KindRecordID Kind = "record"
+	KindPoint    Kind = "point"
+	KindLine     Kind = "line"
+	KindPolygon  Kind = "polygon"
+	KindMulti    Kind = "multipoint"
+	KindArray    Kind = "array"
+	KindObject   Kind = "object"
+)
+
+// Kinds are the kinds of values generated.
+var Kinds = []Kind{
+	KindNone, KindNull, KindBool, KindInt, KindNegative, KindFloat, KindDecimal, KindString, KindBytes,
+	KindDatetime, KindDuration, KindUUID, KindTable, KindRecordID, KindPoint, KindLine, KindPolygon,
+	KindMulti, KindArray, KindObject,
+}
+
+// DefaultMaxDepth is the nesting of arrays and objects when Generator.MaxDepth is 0.
+const DefaultMaxDepth = 3
+
+// Generator generates random documents from a seed, so that a failing document can be generated
+// again from the seed reported by the test.
+type Generator struct {
+	// MaxDepth bounds the nesting of arrays and objects, DefaultMaxDepth when 0.
+	MaxDepth int
+	// Skip lists the kinds not to generate, such as KindNone for documents stored by the server,
+	// which leaves out the fields holding NONE.
+	Skip map[Kind]bool
+
+	rand *rand.Rand
+}
+
+// New returns a generator seeded with seed.
+func New(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))} //nolint:gosec
+}
+
+// Document returns a document holding a field of every kind, named after it, along with
+// random fields of random kinds.
+func (g *Generator) Document() map[string]interface{} {
+	doc := map[string]interface{}{}
+	for _, kind := range g.kinds() {
+		doc[string(kind)] = g.Value(kind, 1)
+	}
+	for i := g.rand.Intn(4); i > 0; i-- {
+		doc[fmt.Sprintf("field_%d", i)] = g.Random(1)
+	}
+	return doc
+}
+
+// Random returns a value of a random kind, at depth in a document.
+func (g *Generator) Random(depth int) interface{} {
+	kinds := g.kinds()
+	if depth >= g.maxDepth() {
+		scalars := kinds[:0]
+		for _, kind := range kinds {
+			if kind != KindArray && kind != KindObject {
+				scalars = append(scalars, kind)
+			}
+		}
+		kinds = scalars
+	}
+	return g.Value(kinds[g.rand.Intn(len(kinds))], depth)
+}
+
+// Value returns a random value of kind, at depth in a document.
+func (g *Generator) Value(kind Kind, depth int) interface{} {
+	switch kind {
+	case KindNone:
+		return models.None
+	case KindNull:
+		return nil
+	case KindBool:
+		return g.rand.Intn(2) == 1
+	case KindInt:
+		// SurrealDB integers are signed 64-bit integers
+		return uint64(g.rand.Int63()) >> g.rand.Intn(63)
+	case KindNegative:
+		return -1 - g.rand.Int63n(math.MaxInt64)
+	case KindFloat:
+		return g.rand.NormFloat64() * math.Pow(10, float64(g.rand.Intn(20)-10))
+	case KindDecimal:
+		return models.DecimalString(models.NewDecimal(g.rand.Int63()-math.MaxInt64/2, -int32(g.rand.Intn(10))).String())
+	case KindString:
+		return g.string()
+	case KindBytes:
+		b := make([]byte, g.rand.Intn(32))
+		g.rand.Read(b)
+		return b
+	case KindDatetime:
+		// from 1970 to 2100, in UTC like the datetimes decoded
+		return models.CustomDateTime{Time: time.Unix(g.rand.Int63n(4102444800), g.rand.Int63n(1e9)).UTC()}
+	case KindDuration:
+		return models.CustomDuration{Duration: time.Duration(g.rand.Int63())}
+	case KindUUID:
+		var id uuid.UUID
+		g.rand.Read(id[:])
+		id.SetVersion(uuid.V4)
+		id.SetVariant(uuid.VariantRFC4122)
+		return models.UUID{UUID: id}
+	case KindTable:
+		return models.Table(g.identifier())
+	case KindRecordID:
+		if g.rand.Intn(2) == 0 {
+			return models.NewRecordID(g.identifier(), g.string())
+		}
+		return models.NewRecordID(g.identifier(), uint64(g.rand.Int63()))
+	case KindPoint:
+		return g.point()
+	case KindLine:
+		return models.GeometryLine{g.point(), g.point(), g.point()}
+	case KindPolygon:
+		return models.GeometryPolygon{g.ring(), g.ring()}
+	case KindMulti:
+		return models.GeometryMultiPoint{g.point(), g.point()}
+	case KindArray:
+		items := make([]interface{}, g.rand.Intn(5))
+		for i := range items {
+			items[i] = g.Random(depth + 1)
+		}
+		return items
+	case KindObject:
+		object := map[string]interface{}{}
+		for i := g.rand.Intn(5); i > 0; i-- {
+			object[g.identifier()] = g.Random(depth + 1)
+		}
+		return object
+	default:
+		panic(fmt.Sprintf("fixture: unknown kind %q", kind))
+	}
+}
+
+func (g *Generator) kinds() []Kind {
+	kinds := make([]Kind, 0, len(Kinds))
+	for _, kind := range Kinds {
+		if !g.Skip[kind] {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+func (g *Generator) maxDepth() int {
+	if g.MaxDepth == 0 {
+		return DefaultMaxDepth
+	}
+	return g.MaxDepth
+}
+
+// string returns a string mixing ASCII, accents, emojis, quotes and escapes.
+func (g *Generator) string() string {
+	alphabet := []rune("abcXYZ019 _-'\"\\/⟨⟩:éß日本🦀\n\t")
+	s := make([]rune, g.rand.Intn(16))
+	for i := range s {
+		s[i] = alphabet[g.rand.Intn(len(alphabet))]
+	}
+	return string(s)
+}
+
+// identifier returns a table or field name.
+func (g *Generator) identifier() string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz_"
+	b := make([]byte, 1+g.rand.Intn(8))
+	for i := range b {
+		b[i] = alphabet[g.rand.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+func (g *Generator) point() models.GeometryPoint {
+	return models.NewGeometryPoint(g.rand.Float64()*360-180, g.rand.Float64()*180-90)
+}
+
+// ring returns a closed line, as the rings of a polygon are.
+func (g *Generator) ring() models.GeometryLine {
+	first := g.point()
+	return models.GeometryLine{first, g.point(), g.point(), first}
+}
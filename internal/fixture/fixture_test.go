@@ -0,0 +1,75 @@
+package fixture
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	canonical := models.CborMarshaler{Deterministic: true}
+	marshalers := map[string]models.CborMarshaler{
+		"default":       {},
+		"deterministic": canonical,
+	}
+
+	for seed := int64(0); seed < 500; seed++ {
+		doc := New(seed).Document()
+		expected, err := canonical.Marshal(doc)
+		require.NoError(t, err, "seed %d", seed)
+
+		for name, marshaler := range marshalers {
+			data, err := marshaler.Marshal(doc)
+			require.NoError(t, err, "%s codec, seed %d", name, seed)
+
+			var decoded interface{}
+			require.NoError(t, models.CborUnmarshaler{}.Unmarshal(data, &decoded), "%s codec, seed %d", name, seed)
+			actual, err := canonical.Marshal(decoded)
+			require.NoError(t, err, "%s codec, seed %d", name, seed)
+			if !bytes.Equal(expected, actual) {
+				t.Fatalf("%s codec, seed %d: document changed by a round trip\nbefore: %x\nafter:  %x\ndocument: %#v",
+					name, seed, expected, actual, doc)
+			}
+		}
+	}
+}
+
+func TestGeneratorDeterministic(t *testing.T) {
+	a, err := models.CborMarshaler{Deterministic: true}.Marshal(New(42).Document())
+	require.NoError(t, err)
+	b, err := models.CborMarshaler{Deterministic: true}.Marshal(New(42).Document())
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+}
+
+func TestGeneratorSkip(t *testing.T) {
+	g := New(1)
+	g.Skip = map[Kind]bool{KindNone: true}
+	for i := 0; i < 100; i++ {
+		for _, value := range g.Document() {
+			require.NotEqual(t, models.None, value)
+		}
+	}
+}
+
+func TestKindsDecodeAsGenerated(t *testing.T) {
+	g := New(7)
+	for _, kind := range Kinds {
+		if kind == KindArray || kind == KindObject {
+			// nested objects decode as map[interface{}]interface{}, compared by TestCodecRoundTrip
+			continue
+		}
+		for i := 0; i < 20; i++ {
+			value := g.Value(kind, 1)
+			data, err := models.CborMarshaler{}.Marshal(value)
+			require.NoError(t, err, kind)
+
+			var decoded interface{}
+			require.NoError(t, models.CborUnmarshaler{}.Unmarshal(data, &decoded), kind)
+			require.Equal(t, value, decoded, kind)
+		}
+	}
+}
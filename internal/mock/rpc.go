@@ -0,0 +1,77 @@
+package mock
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// RPCRequest is a request received by the server of NewRPCServer.
+type RPCRequest struct {
+	connection.RPCRequest
+	// Body is the CBOR encoded request, for handlers decoding its params into their own types.
+	Body []byte
+	// HTTP is the http request carrying it, and Writer answers it for handlers returning
+	// ErrResponseWritten.
+	HTTP   *http.Request
+	Writer http.ResponseWriter
+}
+
+// ErrResponseWritten is returned by a handler that answered the request itself, for example with an
+// http error or by dropping the connection.
+var ErrResponseWritten = errors.New("response written by the handler")
+
+// NewRPCServer starts a fake SurrealDB http endpoint, decoding the CBOR RPC requests it receives
+// and answering each with the result returned by handle. An error returned by handle is sent as the
+// error of the response: as is when it is a *connection.RPCError, with code -32000 otherwise.
+// The server is closed once the test completes.
+func NewRPCServer(t testing.TB, handle func(req RPCRequest) (interface{}, error)) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			return
+		}
+
+		var req connection.RPCRequest
+		body, err := io.ReadAll(r.Body)
+		if err == nil {
+			err = models.CborUnmarshaler{}.Unmarshal(body, &req)
+		}
+		if err != nil {
+			t.Errorf("invalid rpc request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		result, err := handle(RPCRequest{RPCRequest: req, Body: body, HTTP: r, Writer: w})
+		if errors.Is(err, ErrResponseWritten) {
+			return
+		}
+		res := connection.RPCResponse[interface{}]{ID: req.ID}
+		if err != nil {
+			var rpcErr *connection.RPCError
+			if !errors.As(err, &rpcErr) {
+				rpcErr = &connection.RPCError{Code: -32000, Message: err.Error()}
+			}
+			res.Error = rpcErr
+		} else {
+			res.Result = &result
+		}
+
+		data, err := models.CborMarshaler{}.Marshal(res)
+		if err != nil {
+			t.Errorf("encoding the rpc response: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,132 @@
+package surrealdb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// KNN returns the condition that the vector held by field is one of the k nearest neighbours of
+// vector, written field <|k|> vector. It uses the vector index of field, or compares every record
+// when there is none.
+func KNN[T models.VectorElement](field string, vector models.Vector[T], k int) (Condition, error) {
+	if k < 1 {
+		return Condition{}, fmt.Errorf("k must be at least 1, got %d", k)
+	}
+	escaped, err := models.EscapeFieldPath(field)
+	if err != nil {
+		return Condition{}, err
+	}
+	name := "knn_" + fieldVarName(field) + "_vector"
+	return Condition{
+		SQL:  fmt.Sprintf("%s <|%d|> $%s", escaped, k, name),
+		Vars: map[string]interface{}{name: vector},
+	}, nil
+}
+
+// KNNResult is a record found by SearchKNN.
+type KNNResult[T any] struct {
+	Record T
+	// Distance is the distance between the vector of the record and the searched vector, as
+	// computed by vector::distance::knn.
+	Distance float64
+}
+
+// knnFields are the fields added to the records by SearchKNN
+type knnFields struct {
+	Distance float64 `json:"_knn_distance"`
+}
+
+// SearchKNN returns the k records of table whose vector held by field is the nearest to vector,
+// the nearest first. filter, which may be nil, further restricts the records returned. Use
+// DB.WithContext to bound the search.
+func SearchKNN[TResult any, T models.VectorElement](db *DB, table models.Table, field string,
+	vector models.Vector[T], k int, filter *Condition) ([]KNNResult[TResult], error) {
+	knn, err := KNN(field, vector, k)
+	if err != nil {
+		return nil, err
+	}
+	where := knn
+	if filter != nil {
+		if where, err = And(knn, *filter); err != nil {
+			return nil, err
+		}
+	}
+
+	vars := map[string]interface{}{"knn_table": table}
+	for name, value := range where.Vars {
+		vars[name] = value
+	}
+
+	sql := "SELECT *, vector::distance::knn() AS _knn_distance FROM $knn_table WHERE " + where.SQL +
+		" ORDER BY _knn_distance"
+	rows, err := queryStream[cbor.RawMessage](db, sql, vars)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unmarshaler := db.con.GetUnmarshaler()
+	var results []KNNResult[TResult]
+	for rows.Next() {
+		raw := rows.Value()
+		var result KNNResult[TResult]
+		if err := connection.DecodeResult(unmarshaler, "query", raw, &result.Record); err != nil {
+			return nil, err
+		}
+		var fields knnFields
+		if err := unmarshaler.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+		result.Distance = fields.Distance
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// VectorIndex is an HNSW index on a field holding vectors, used by the knn operator, see
+// VectorIndexStatement.
+type VectorIndex struct {
+	Name  string
+	Table models.Table
+	Field string
+	// Dimension is the number of elements of the vectors.
+	Dimension int
+	// Distance is the distance the nearest neighbours are found by, such as cosine or manhattan.
+	// It defaults to euclidean.
+	Distance string
+	// Type is the type of the elements, such as f32 or f64. It defaults to f64.
+	Type string
+}
+
+// vectorIndexOption matches the value of the DIST and TYPE options of a vector index
+var vectorIndexOption = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// VectorIndexStatement returns the DEFINE INDEX statement of idx, replacing an index of the same
+// name like DefineIndexStatement.
+func VectorIndexStatement(idx VectorIndex) (string, error) {
+	if idx.Name == "" || idx.Table == "" || idx.Dimension < 1 {
+		return "", fmt.Errorf("vector index needs a name, a table and a dimension")
+	}
+	field, err := models.EscapeFieldPath(idx.Field)
+	if err != nil {
+		return "", err
+	}
+
+	sql := fmt.Sprintf("DEFINE INDEX OVERWRITE %s ON TABLE %s FIELDS %s HNSW DIMENSION %d",
+		models.EscapeIdent(idx.Name), idx.Table.SurrealString(), field, idx.Dimension)
+	for _, option := range []struct{ keyword, value string }{{"DIST", idx.Distance}, {"TYPE", idx.Type}} {
+		if option.value == "" {
+			continue
+		}
+		if !vectorIndexOption.MatchString(option.value) {
+			return "", fmt.Errorf("invalid vector index %s %q", strings.ToLower(option.keyword), option.value)
+		}
+		sql += " " + option.keyword + " " + strings.ToUpper(option.value)
+	}
+	return sql, nil
+}
@@ -0,0 +1,50 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestSearchKNN(t *testing.T) {
+	var sql string
+	var vars map[interface{}]interface{}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		sql = req.Params[0].(string)
+		vars = req.Params[1].(map[interface{}]interface{})
+
+		return []interface{}{map[string]interface{}{
+			"status": "OK",
+			"time":   "1ms",
+			"result": []interface{}{
+				map[string]interface{}{"title": "a", "_knn_distance": 0.5},
+				map[string]interface{}{"title": "b", "_knn_distance": 1.25},
+			},
+		}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	type document struct {
+		Title string `json:"title"`
+	}
+	results, err := surrealdb.SearchKNN[document](db, "document", "embedding",
+		models.Vector[float32]{0.5, 1}, 2, &surrealdb.Condition{SQL: "published = true"})
+	require.NoError(t, err)
+	require.Contains(t, sql, "WHERE (embedding <|2|> $knn_embedding_vector) AND (published = true)")
+	require.Contains(t, sql, "ORDER BY _knn_distance")
+	require.Equal(t, []interface{}{0.5, 1.0}, vars["knn_embedding_vector"])
+	require.Equal(t, []surrealdb.KNNResult[document]{
+		{Record: document{Title: "a"}, Distance: 0.5},
+		{Record: document{Title: "b"}, Distance: 1.25},
+	}, results)
+
+	_, err = surrealdb.KNN("embedding", models.Vector[float64]{1}, 0)
+	require.Error(t, err)
+}
@@ -0,0 +1,234 @@
+package surrealdb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// LiveNotification is a change notified to a LiveQuery, with the record decoded into T.
+type LiveNotification[T any] struct {
+	Action connection.Action
+	// Before is the record as it was before an UPDATE or a DELETE. SurrealDB does not send the
+	// previous version of updated records, so for an UPDATE it is the version last notified by the
+	// live query, and nil when the record was not notified since the live query started.
+	// It is nil for CREATE.
+	Before *T
+	// After is the record as it is after a CREATE or UPDATE. It is nil for DELETE.
+	After *T
+	// Err is set when the record could not be decoded into T.
+	Err error
+}
+
+// LiveQuery is a running live query, see StartLiveQuery.
+type LiveQuery[T any] struct {
+	ID *models.UUID
+
+	db  *DB
+	ctx context.Context
+	// polled is set when the engine cannot receive notifications, see WithLivePollInterval
+	polled bool
+	// records holds the last notified version of the records, by id, to tell the Before of updates
+	records       map[string]cbor.RawMessage
+	notifications chan LiveNotification[T]
+	cancelRaw     func()
+	done          chan struct{}
+	killOnce      sync.Once
+	killErr       error
+}
+
+// StartLiveQuery runs a LIVE SELECT statement and decodes its notifications into T.
+// Notifications must be consumed, as once more than constants.DefaultNotificationBuffer are pending
+// the following ones are dropped, see WithNotificationBuffer.
+// The live query runs until Kill is called or ctx is done. It keeps the last notified version of
+// every record it notifies, to report the Before of updates.
+//
+// The http engine cannot receive notifications, so the live query is polled instead: its SELECT
+// statement runs every poll interval, see WithLivePollInterval, and the records created, updated
+// and deleted in between are notified. Polled live queries require the records to have an id, and
+// do not support LIVE SELECT DIFF.
+//
+//	lq, err := surrealdb.StartLiveQuery[User](ctx, db, "LIVE SELECT * FROM users WHERE age > $age", map[string]interface{}{"age": 18})
+//	defer lq.Kill()
+//	for n := range lq.Notifications() {
+//		...
+//	}
+func StartLiveQuery[T any](ctx context.Context, db *DB, query string, vars map[string]interface{}) (*LiveQuery[T], error) {
+	if !db.supportsLiveQueries() {
+		return startPolledLiveQuery[T](ctx, db, query, vars)
+	}
+
+	// subscribe before the query runs, so that no notification is missed
+	raw, cancelRaw := db.con.RawNotifications()
+
+	lq := &LiveQuery[T]{
+		db:            db,
+		ctx:           ctx,
+		records:       map[string]cbor.RawMessage{},
+		notifications: make(chan LiveNotification[T]),
+		cancelRaw:     cancelRaw,
+		done:          make(chan struct{}),
+	}
+
+	ids := make(chan models.UUID, 1)
+	go lq.forward(raw, ids)
+
+	id, err := querySingle[models.UUID](db.WithContext(ctx), query, vars)
+	if err != nil {
+		lq.stop()
+		return nil, err
+	}
+	lq.ID = id
+	ids <- *id
+	lq.killWithContext()
+
+	return lq, nil
+}
+
+// killWithContext kills the live query once its context is done.
+func (lq *LiveQuery[T]) killWithContext() {
+	if lq.ctx.Done() == nil {
+		return
+	}
+	go func() {
+		select {
+		case <-lq.ctx.Done():
+			_ = lq.Kill()
+		case <-lq.done:
+		}
+	}()
+}
+
+// Notifications returns the channel of notifications, closed once the live query is killed.
+func (lq *LiveQuery[T]) Notifications() <-chan LiveNotification[T] {
+	return lq.notifications
+}
+
+// Kill stops the live query on the server and closes the notification channel.
+func (lq *LiveQuery[T]) Kill() error {
+	lq.killOnce.Do(func() {
+		if !lq.polled {
+			lq.killErr = Kill(lq.db, lq.ID.String())
+		}
+		lq.stop()
+	})
+	return lq.killErr
+}
+
+func (lq *LiveQuery[T]) stop() {
+	close(lq.done)
+	lq.cancelRaw()
+}
+
+// forward decodes the notifications of the live query and passes them on. Notifications received
+// before the id of the live query is known are kept until it is.
+func (lq *LiveQuery[T]) forward(raw chan connection.RawNotification, ids chan models.UUID) {
+	defer close(lq.notifications)
+
+	var id *models.UUID
+	var pending []connection.RawNotification
+	for {
+		select {
+		case <-lq.done:
+			return
+		case known := <-ids:
+			id = &known
+			for _, n := range pending {
+				if !lq.deliver(id, n) {
+					return
+				}
+			}
+			pending = nil
+		case n, ok := <-raw:
+			if !ok {
+				return
+			}
+			if id == nil {
+				pending = append(pending, n)
+			} else if !lq.deliver(id, n) {
+				return
+			}
+		}
+	}
+}
+
+// deliver passes n on when it belongs to the live query, and returns false once the live query stopped.
+func (lq *LiveQuery[T]) deliver(id *models.UUID, n connection.RawNotification) bool {
+	if n.ID == nil || *n.ID != *id {
+		return true
+	}
+	if n.Action == connection.DeleteAction {
+		lq.forget(n.Result)
+		return lq.send(lq.notification(n.Action, n.Result, nil))
+	}
+	return lq.send(lq.notification(n.Action, lq.remember(n.Result), n.Result))
+}
+
+// remember records result as the last version of its record, and returns the previous one.
+// Results without an id, such as those of LIVE SELECT DIFF, are not recorded.
+func (lq *LiveQuery[T]) remember(result cbor.RawMessage) (before cbor.RawMessage) {
+	key, ok := lq.recordKey(result)
+	if !ok {
+		return nil
+	}
+	before = lq.records[key]
+	lq.records[key] = result
+	return before
+}
+
+// forget drops the last version of the record of result.
+func (lq *LiveQuery[T]) forget(result cbor.RawMessage) {
+	if key, ok := lq.recordKey(result); ok {
+		delete(lq.records, key)
+	}
+}
+
+func (lq *LiveQuery[T]) recordKey(result cbor.RawMessage) (string, bool) {
+	var record struct {
+		ID *models.RecordID `json:"id"`
+	}
+	if err := lq.db.con.GetUnmarshaler().Unmarshal(result, &record); err != nil || record.ID == nil {
+		return "", false
+	}
+	return record.ID.String(), true
+}
+
+// notification decodes the versions of the record of a notification, either of which may be nil.
+func (lq *LiveQuery[T]) notification(action connection.Action, before, after cbor.RawMessage) LiveNotification[T] {
+	notification := LiveNotification[T]{Action: action}
+	var err error
+	if notification.Before, err = lq.decode(before); err != nil {
+		notification.Err = err
+		return notification
+	}
+	if notification.After, err = lq.decode(after); err != nil {
+		notification.Before = nil
+		notification.Err = err
+	}
+	return notification
+}
+
+func (lq *LiveQuery[T]) decode(raw cbor.RawMessage) (*T, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var record T
+	if err := connection.DecodeResult(lq.db.con.GetUnmarshaler(), "live", raw, &record); err != nil {
+		lq.db.stats.record(err)
+		return nil, err
+	}
+	return &record, nil
+}
+
+// send passes notification on, and returns false once the live query stopped.
+func (lq *LiveQuery[T]) send(notification LiveNotification[T]) bool {
+	select {
+	case lq.notifications <- notification:
+		return true
+	case <-lq.done:
+		return false
+	}
+}
@@ -0,0 +1,51 @@
+package surrealdb_test
+
+import (
+	"context"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func (s *SurrealDBTestSuite) TestStartLiveQuery() {
+	lq, err := surrealdb.StartLiveQuery[testUser](context.Background(), s.db, "LIVE SELECT * FROM users WHERE username != $ignored",
+		map[string]interface{}{"ignored": "ignored"})
+	s.Require().NoError(err)
+
+	_, err = surrealdb.Create[testUser](s.db, models.NewRecordID("users", "ignored"), testUser{Username: "ignored"})
+	s.Require().NoError(err)
+	_, err = surrealdb.Create[testUser](s.db, models.NewRecordID("users", "johnny"), testUser{Username: "johnny"})
+	s.Require().NoError(err)
+	_, err = surrealdb.Update[testUser](s.db, models.NewRecordID("users", "johnny"), testUser{Username: "john"})
+	s.Require().NoError(err)
+	_, err = surrealdb.Delete[testUser](s.db, models.NewRecordID("users", "johnny"))
+	s.Require().NoError(err)
+
+	created := <-lq.Notifications()
+	s.Require().NoError(created.Err)
+	s.Equal(connection.CreateAction, created.Action)
+	s.Nil(created.Before)
+	s.Require().NotNil(created.After)
+	s.Equal("johnny", created.After.Username)
+
+	updated := <-lq.Notifications()
+	s.Require().NoError(updated.Err)
+	s.Equal(connection.UpdateAction, updated.Action)
+	s.Require().NotNil(updated.Before)
+	s.Equal("johnny", updated.Before.Username)
+	s.Require().NotNil(updated.After)
+	s.Equal("john", updated.After.Username)
+
+	deleted := <-lq.Notifications()
+	s.Require().NoError(deleted.Err)
+	s.Equal(connection.DeleteAction, deleted.Action)
+	s.Require().NotNil(deleted.Before)
+	s.Equal("john", deleted.Before.Username)
+	s.Nil(deleted.After)
+
+	s.Require().NoError(lq.Kill())
+	_, open := <-lq.Notifications()
+	s.False(open, "notifications are closed once killed")
+}
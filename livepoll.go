@@ -0,0 +1,145 @@
+package surrealdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gofrs/uuid"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// defaultLivePollInterval is how often the live queries of the engines without live query
+// notifications poll their records, when WithLivePollInterval is not set.
+const defaultLivePollInterval = time.Second
+
+// supportsLiveQueries reports whether the connection of db receives the notifications of live
+// queries. The http engine cannot, as the server has no way to push them.
+func (db *DB) supportsLiveQueries() bool {
+	_, isHTTP := db.con.(*connection.HTTPConnection)
+	return !isHTTP
+}
+
+// polledRecord is a record returned by the SELECT statement of a polled live query.
+type polledRecord struct {
+	id  string
+	raw cbor.RawMessage
+}
+
+// startPolledLiveQuery emulates a live query by running its SELECT statement every poll interval
+// and notifying the differences between consecutive results, for the engines without live query
+// notifications. The first result is read before it returns, so that an invalid query fails
+// like it does with the other engines.
+func startPolledLiveQuery[T any](ctx context.Context, db *DB, query string, vars map[string]interface{}) (*LiveQuery[T], error) {
+	sql, err := livePollStatement(query)
+	if err != nil {
+		return nil, err
+	}
+	records, err := db.WithContext(ctx).pollLiveQuery(sql, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	id := models.UUID{UUID: uuid.Must(uuid.NewV4())}
+	lq := &LiveQuery[T]{
+		ID:            &id,
+		db:            db,
+		ctx:           ctx,
+		polled:        true,
+		notifications: make(chan LiveNotification[T]),
+		cancelRaw:     func() {},
+		done:          make(chan struct{}),
+	}
+	go lq.poll(sql, vars, records)
+	lq.killWithContext()
+
+	return lq, nil
+}
+
+// livePollStatement returns the SELECT statement polled in place of a LIVE SELECT statement.
+func livePollStatement(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	words := strings.Fields(query)
+	if len(words) < 2 || !strings.EqualFold(words[0], "LIVE") || !strings.EqualFold(words[1], "SELECT") {
+		return "", fmt.Errorf("live query %q does not start with LIVE SELECT", query)
+	}
+	if len(words) > 2 && strings.EqualFold(words[2], "DIFF") {
+		return "", fmt.Errorf("LIVE SELECT DIFF cannot be polled by the http engine")
+	}
+	return strings.TrimSpace(query[len(words[0]):]), nil
+}
+
+// poll notifies the changes made to records, the last result of the live query, until the live
+// query is killed.
+func (lq *LiveQuery[T]) poll(sql string, vars map[string]interface{}, records []polledRecord) {
+	defer close(lq.notifications)
+
+	ticker := time.NewTicker(lq.db.livePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-lq.done:
+			return
+		case <-ticker.C:
+		}
+
+		polled, err := lq.db.pollLiveQuery(sql, vars)
+		if err != nil {
+			// polled again at the next tick, like a live query survives a reconnection
+			lq.db.logger.Warn("polling live query failed", "id", lq.ID.String(), "error", err.Error())
+			continue
+		}
+
+		previous := make(map[string]cbor.RawMessage, len(records))
+		for _, r := range records {
+			previous[r.id] = r.raw
+		}
+		for _, r := range polled {
+			before, ok := previous[r.id]
+			delete(previous, r.id)
+			switch {
+			case !ok:
+				if !lq.send(lq.notification(connection.CreateAction, nil, r.raw)) {
+					return
+				}
+			case !bytes.Equal(before, r.raw):
+				if !lq.send(lq.notification(connection.UpdateAction, before, r.raw)) {
+					return
+				}
+			}
+		}
+		for _, r := range records {
+			if raw, deleted := previous[r.id]; deleted {
+				if !lq.send(lq.notification(connection.DeleteAction, raw, nil)) {
+					return
+				}
+			}
+		}
+		records = polled
+	}
+}
+
+// pollLiveQuery runs the SELECT statement of a polled live query, and returns its records in order.
+func (db *DB) pollLiveQuery(sql string, vars map[string]interface{}) ([]polledRecord, error) {
+	rows, err := queryStream[cbor.RawMessage](db, sql, vars)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []polledRecord
+	for rows.Next() {
+		var record struct {
+			ID *models.RecordID `json:"id"`
+		}
+		if err := db.con.GetUnmarshaler().Unmarshal(rows.Value(), &record); err != nil || record.ID == nil {
+			return nil, fmt.Errorf("the records of a polled live query must have an id")
+		}
+		records = append(records, polledRecord{id: record.ID.String(), raw: rows.Value()})
+	}
+	return records, rows.Err()
+}
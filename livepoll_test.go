@@ -0,0 +1,79 @@
+package surrealdb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestPolledLiveQuery(t *testing.T) {
+	var lock sync.Mutex
+	var queries []string
+	snapshots := [][]interface{}{
+		{
+			map[string]interface{}{"id": models.NewRecordID("user", "a"), "name": "Ann"},
+			map[string]interface{}{"id": models.NewRecordID("user", "b"), "name": "Bob"},
+		},
+		{
+			map[string]interface{}{"id": models.NewRecordID("user", "a"), "name": "Anna"},
+			map[string]interface{}{"id": models.NewRecordID("user", "c"), "name": "Cid"},
+		},
+	}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		var snapshot interface{} = []interface{}{}
+		if req.Method == "query" {
+			queries = append(queries, req.Params[0].(string))
+			snapshot = snapshots[len(snapshots)-1]
+			if len(queries) < len(snapshots) {
+				snapshot = snapshots[len(queries)-1]
+			}
+		}
+		lock.Unlock()
+		return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": snapshot}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithLivePollInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	type user struct {
+		ID   *models.RecordID `json:"id"`
+		Name string           `json:"name"`
+	}
+	lq, err := surrealdb.StartLiveQuery[user](context.Background(), db, "LIVE SELECT * FROM user WHERE active", nil)
+	require.NoError(t, err)
+
+	var changes []string
+	for n := range lq.Notifications() {
+		require.NoError(t, n.Err)
+		change := string(n.Action)
+		if n.Before != nil {
+			change += " " + n.Before.Name
+		}
+		if n.After != nil {
+			change += " -> " + n.After.Name
+		}
+		changes = append(changes, change)
+		if len(changes) == 3 {
+			require.NoError(t, lq.Kill())
+		}
+	}
+	require.Equal(t, []string{"UPDATE Ann -> Anna", "CREATE -> Cid", "DELETE Bob"}, changes)
+
+	lock.Lock()
+	require.Equal(t, "SELECT * FROM user WHERE active", queries[0])
+	lock.Unlock()
+
+	_, err = surrealdb.StartLiveQuery[user](context.Background(), db, "LIVE SELECT DIFF FROM user", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package surrealdb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// Redacted replaces the values of the credentials and sensitive variables in the request logs.
+const Redacted = "[REDACTED]"
+
+// credentialKeys are the keys of the credentials given to signin and signup that are redacted
+var credentialKeys = map[string]bool{
+	"pass": true, "password": true, "secret": true, "key": true, "token": true, "refresh": true,
+}
+
+// WithRedactedVars redacts the values of the given query and connection variables in the request
+// logs, in addition to the credentials given to signin, signup and authenticate, which are always
+// redacted.
+func WithRedactedVars(names ...string) Option {
+	return func(c *config) error {
+		if c.redactedVars == nil {
+			c.redactedVars = map[string]bool{}
+		}
+		for _, name := range names {
+			c.redactedVars[strings.TrimPrefix(name, "$")] = true
+		}
+		return nil
+	}
+}
+
+// levelEnabler is implemented by the loggers able to tell whether a level is logged, such as the
+// slog based logger.Logger, letting the client skip building the request logs when not needed.
+type levelEnabler interface {
+	Enabled(ctx context.Context, level slog.Level) bool
+}
+
+// logRequest logs a request at debug level, with its method, redacted parameters, duration and
+// status.
+func (db *DB) logRequest(method string, params []interface{}, duration time.Duration, err error) {
+	if db.logger == nil {
+		return
+	}
+	if l, ok := db.logger.(levelEnabler); ok && !l.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
+	args := []interface{}{
+		"method", method,
+		"params", fmt.Sprintf("%v", db.redactParams(method, params)),
+		"duration", duration,
+	}
+	if err != nil {
+		args = append(args, "status", "error", "error", err.Error())
+	} else {
+		args = append(args, "status", "ok")
+	}
+	db.logger.Debug("rpc request", args...)
+}
+
+// redactParams returns a copy of the parameters of a request, safe to log.
+func (db *DB) redactParams(method string, params []interface{}) []interface{} {
+	redacted := make([]interface{}, len(params))
+	copy(redacted, params)
+
+	switch method {
+	case "signin", "signup":
+		if len(redacted) > 0 {
+			redacted[0] = redactCredentials(redacted[0])
+		}
+	case "authenticate":
+		if len(redacted) > 0 {
+			redacted[0] = Redacted
+		}
+	case "let":
+		if len(redacted) > 1 {
+			if key, ok := redacted[0].(string); ok && (key == constants.AuthTokenKey || db.redactedVars[key]) {
+				redacted[1] = Redacted
+			}
+		}
+	case "query":
+		if len(redacted) > 1 {
+			if vars, ok := redacted[1].(map[string]interface{}); ok {
+				redacted[1] = db.redactVars(vars)
+			}
+		}
+	}
+	return redacted
+}
+
+func (db *DB) redactVars(vars map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(vars))
+	for key, value := range vars {
+		if db.redactedVars[key] || key == constants.AuthTokenKey {
+			value = Redacted
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// redactCredentials returns the credentials given to signin or signup, without their secrets.
+func redactCredentials(credentials interface{}) interface{} {
+	switch c := credentials.(type) {
+	case *Auth:
+		if c == nil {
+			return c
+		}
+		redacted := *c
+		if redacted.Password != "" {
+			redacted.Password = Redacted
+		}
+		return redacted
+	case Auth:
+		return redactCredentials(&c)
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(c))
+		for key, value := range c {
+			if credentialKeys[strings.ToLower(key)] {
+				value = Redacted
+			}
+			redacted[key] = value
+		}
+		return redacted
+	default:
+		return Redacted
+	}
+}
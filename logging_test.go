@@ -0,0 +1,45 @@
+package surrealdb_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/logger"
+)
+
+func TestRequestLogging(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		if req.Method == "query" {
+			return []surrealdb.QueryResult[interface{}]{{Status: "OK", Result: []interface{}{}}}, nil
+		}
+		return "token", nil
+	})
+
+	var logs strings.Builder
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithLogger(logger.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		surrealdb.WithRedactedVars("$card"),
+	)
+	require.NoError(t, err)
+
+	_, err = db.SignIn(&surrealdb.Auth{Username: "root", Password: "hunter2"})
+	require.NoError(t, err)
+	_, err = surrealdb.Query[[]testUser](db, "SELECT * FROM users WHERE card = $card AND name = $name",
+		map[string]interface{}{"card": "4242-4242", "name": "john"})
+	require.NoError(t, err)
+
+	require.Contains(t, logs.String(), "method=signin")
+	require.Contains(t, logs.String(), "root")
+	require.Contains(t, logs.String(), "john")
+	require.Contains(t, logs.String(), "status=ok")
+	require.NotContains(t, logs.String(), "hunter2")
+	require.NotContains(t, logs.String(), "4242-4242")
+	require.Equal(t, 2, strings.Count(logs.String(), surrealdb.Redacted))
+}
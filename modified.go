@@ -0,0 +1,92 @@
+package surrealdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// DefaultTimestampField is the field ListModifiedIDs filters on when no field is given.
+const DefaultTimestampField = "updated_at"
+
+// ListModifiedIDs returns the ids of the records of table modified in [since, until), according to
+// the given timestamp fields, decoded into T, usually models.RecordID. A record matches when any
+// of the fields falls within the window. The window is shifted to the server clock when the DB
+// was created with WithClockSkewCompensation. A warning is logged, once per table and fields, when
+// no index of the table starts with one of the fields, as the query then scans the whole table.
+func ListModifiedIDs[T any](ctx context.Context, db *DB, table models.Table, since, until time.Time, timestampFields ...string) ([]T, error) {
+	db = db.WithContext(ctx)
+	if !until.After(since) {
+		return nil, fmt.Errorf("until must be after since")
+	}
+	if len(timestampFields) == 0 {
+		timestampFields = []string{DefaultTimestampField}
+	}
+
+	conditions := make([]string, len(timestampFields))
+	for i, field := range timestampFields {
+		escaped, err := models.EscapeFieldPath(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp field: %w", err)
+		}
+		conditions[i] = fmt.Sprintf("(%s >= $since AND %s < $until)", escaped, escaped)
+	}
+
+	db.adviseIndex(table, timestampFields)
+
+	sql := fmt.Sprintf("SELECT VALUE id FROM $table WHERE %s", strings.Join(conditions, " OR "))
+	ids, err := querySingle[[]T](db, sql, map[string]interface{}{
+		"table": table,
+		"since": &models.CustomDateTime{Time: db.toServerTime(since)},
+		"until": &models.CustomDateTime{Time: db.toServerTime(until)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return *ids, nil
+}
+
+// adviseIndex logs a warning when none of fields leads an index of table.
+func (db *DB) adviseIndex(table models.Table, fields []string) {
+	key := table.String() + "\x00" + strings.Join(fields, "\x00")
+	if _, advised := db.indexAdvice.LoadOrStore(key, true); advised {
+		return
+	}
+
+	info, err := querySingle[struct {
+		Indexes map[string]string `json:"indexes"`
+	}](db, fmt.Sprintf("INFO FOR TABLE %s", table.SurrealString()), nil)
+	if err != nil {
+		// the advice is best effort, the user may not be allowed to read the table definition
+		return
+	}
+
+	for _, definition := range info.Indexes {
+		for _, field := range fields {
+			if firstIndexedField(definition) == field {
+				return
+			}
+		}
+	}
+
+	db.logger.Warn("no index starts with the timestamp fields, listing modified records scans the whole table",
+		"table", table.String(), "fields", fields,
+		"advice", fmt.Sprintf("DEFINE INDEX %s_%s ON %s FIELDS %s",
+			table, strings.ReplaceAll(fields[0], ".", "_"), table.SurrealString(), fields[0]))
+}
+
+// firstIndexedField returns the first field of a DEFINE INDEX statement.
+func firstIndexedField(definition string) string {
+	for _, keyword := range []string{" FIELDS ", " COLUMNS "} {
+		if _, columns, found := strings.Cut(definition, keyword); found {
+			field, _, _ := strings.Cut(columns, ",")
+			field, _, _ = strings.Cut(strings.TrimSpace(field), " ")
+			return field
+		}
+	}
+	return ""
+}
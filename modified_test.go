@@ -0,0 +1,32 @@
+package surrealdb_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func (s *SurrealDBTestSuite) TestListModifiedIDs() {
+	now := time.Now()
+	for i, updated := range []time.Time{now.Add(-2 * time.Hour), now.Add(-30 * time.Minute), now} {
+		_, err := surrealdb.Create[testUser](s.db, models.NewRecordID("users", i), map[string]interface{}{
+			"updated_at": &models.CustomDateTime{Time: updated},
+		})
+		s.Require().NoError(err)
+	}
+
+	ctx := context.Background()
+	ids, err := surrealdb.ListModifiedIDs[models.RecordID](ctx, s.db, "users", now.Add(-time.Hour), now)
+	s.Require().NoError(err)
+	s.Require().Len(ids, 1)
+	s.Equal(models.NewRecordID("users", uint64(1)), ids[0])
+
+	_, err = surrealdb.ListModifiedIDs[models.RecordID](ctx, s.db, "users", now, now.Add(-time.Hour))
+	s.Error(err, "the window must not be empty")
+
+	_, err = surrealdb.ListModifiedIDs[models.RecordID](ctx, s.db, "users", now.Add(-time.Hour), now, "updated_at; DELETE users")
+	s.Error(err, "fields must be plain field paths")
+}
@@ -0,0 +1,215 @@
+package surrealdb
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/internal/codec"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/logger"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Option configures the client created by Connect.
+type Option func(c *config) error
+
+type config struct {
+	auth        *Auth
+	token       string
+	namespace   string
+	database    string
+	marshaler   codec.Marshaler
+	unmarshaler codec.Unmarshaler
+	logger      logger.Logger
+	auditHook   AuditHook
+	hooks       *connection.Hooks
+	protocol    protocol
+
+	redactedVars   map[string]bool
+	defaultVars    map[string]interface{}
+	methodPolicies map[string]MethodPolicy
+
+	compensateClockSkew bool
+	propagateDeadline   bool
+
+	maxConcurrentStreams int
+	warmConnections      int
+	maxBatchSize         int
+	livePollInterval     time.Duration
+
+	notificationBuffer *int
+
+	connectAttempts int
+	connectDelay    time.Duration
+}
+
+func newConfig() *config {
+	return &config{
+		marshaler:        models.CborMarshaler{},
+		unmarshaler:      models.CborUnmarshaler{},
+		logger:           logger.New(slog.NewTextHandler(os.Stdout, nil)),
+		connectAttempts:  1,
+		livePollInterval: defaultLivePollInterval,
+	}
+}
+
+// WithAuth signs in with the given credentials once connected. It replaces a token given with
+// WithToken before it.
+func WithAuth(auth *Auth) Option {
+	return func(c *config) error {
+		if auth == nil {
+			return fmt.Errorf("auth must not be nil")
+		}
+		c.auth = auth
+		c.token = ""
+		return nil
+	}
+}
+
+// WithToken authenticates with a token once connected, such as an access token of SurrealDB
+// Cloud or a token returned by SignIn, instead of signing in with credentials like WithAuth. It
+// replaces credentials given with WithAuth before it.
+func WithToken(token string) Option {
+	return func(c *config) error {
+		if token == "" {
+			return fmt.Errorf("token must not be empty")
+		}
+		c.token = token
+		c.auth = nil
+		return nil
+	}
+}
+
+// WithNamespace selects the namespace and database to use once connected. It takes precedence
+// over the ns and db parameters of the connection url.
+func WithNamespace(namespace, database string) Option {
+	return func(c *config) error {
+		if namespace == "" || database == "" {
+			return fmt.Errorf("namespace and database must both be set")
+		}
+		c.namespace = namespace
+		c.database = database
+		return nil
+	}
+}
+
+// WithCodec replaces the CBOR codec used to talk to the server.
+func WithCodec(marshaler codec.Marshaler, unmarshaler codec.Unmarshaler) Option {
+	return func(c *config) error {
+		if marshaler == nil || unmarshaler == nil {
+			return fmt.Errorf("marshaler and unmarshaler must both be set")
+		}
+		c.marshaler = marshaler
+		c.unmarshaler = unmarshaler
+		return nil
+	}
+}
+
+// WithLogger sets the logger used by the client and its connection.
+func WithLogger(l logger.Logger) Option {
+	return func(c *config) error {
+		if l == nil {
+			return fmt.Errorf("logger must not be nil")
+		}
+		c.logger = l
+		return nil
+	}
+}
+
+// WithHooks instruments the requests and live query notifications of the connection with hooks,
+// for tracing and metrics.
+func WithHooks(hooks connection.Hooks) Option {
+	return func(c *config) error {
+		c.hooks = &hooks
+		return nil
+	}
+}
+
+// WithRetry makes Connect try up to attempts times to connect, sign in and select the namespace,
+// waiting delay between two attempts.
+func WithRetry(attempts int, delay time.Duration) Option {
+	return func(c *config) error {
+		if attempts < 1 {
+			return fmt.Errorf("connect attempts must be at least 1, got %d", attempts)
+		}
+		if delay < 0 {
+			return fmt.Errorf("connect retry delay must not be negative")
+		}
+		c.connectAttempts = attempts
+		c.connectDelay = delay
+		return nil
+	}
+}
+
+// WithClockSkewCompensation measures the clock skew between the client and the server once
+// connected, and shifts the time windows given to ListModifiedIDs to the server clock.
+// Call DB.MeasureClockSkew to measure it again on long-lived connections.
+func WithClockSkewCompensation() Option {
+	return func(c *config) error {
+		c.compensateClockSkew = true
+		return nil
+	}
+}
+
+// WithMaxConcurrentStreams bounds the number of requests in flight at once over the http engine,
+// which are the concurrent streams of the connection over HTTP/2.
+func WithMaxConcurrentStreams(n int) Option {
+	return func(c *config) error {
+		if n < 1 {
+			return fmt.Errorf("max concurrent streams must be at least 1")
+		}
+		c.maxConcurrentStreams = n
+		return nil
+	}
+}
+
+// WithWarmPool opens n connections on connect with the http engine and keeps them idle, so that
+// a burst of requests does not pay for opening connections.
+func WithWarmPool(n int) Option {
+	return func(c *config) error {
+		if n < 1 {
+			return fmt.Errorf("warm pool must hold at least 1 connection")
+		}
+		c.warmConnections = n
+		return nil
+	}
+}
+
+// WithMaxBatchSize sets the number of record ids SelectByIDs and DeleteByIDs send in one statement,
+// 1000 by default.
+func WithMaxBatchSize(n int) Option {
+	return func(c *config) error {
+		if n < 1 {
+			return fmt.Errorf("max batch size must be at least 1")
+		}
+		c.maxBatchSize = n
+		return nil
+	}
+}
+
+// WithLivePollInterval sets how often the live queries started with StartLiveQuery poll their
+// records over the http engine, which cannot receive notifications, 1s by default.
+func WithLivePollInterval(interval time.Duration) Option {
+	return func(c *config) error {
+		if interval <= 0 {
+			return fmt.Errorf("live poll interval must be positive")
+		}
+		c.livePollInterval = interval
+		return nil
+	}
+}
+
+// WithNotificationBuffer sets how many notifications the ws engine holds for each live query whose
+// consumer has not read them yet, 128 by default. The notifications of a consumer further behind
+// are dropped, and counted in Stats.DroppedNotifications.
+func WithNotificationBuffer(n int) Option {
+	return func(c *config) error {
+		if n < 1 {
+			return fmt.Errorf("notification buffer must be at least 1")
+		}
+		c.notificationBuffer = &n
+		return nil
+	}
+}
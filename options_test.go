@@ -0,0 +1,113 @@
+package surrealdb_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/logger"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestConnect_Options(t *testing.T) {
+	quiet := surrealdb.WithLogger(logger.New(slog.NewTextHandler(io.Discard, nil)))
+	unreachable := "ws://127.0.0.1:1"
+
+	t.Run("invalid options are rejected", func(t *testing.T) {
+		invalid := []surrealdb.Option{
+			surrealdb.WithAuth(nil),
+			surrealdb.WithNamespace("test", ""),
+			surrealdb.WithCodec(nil, nil),
+			surrealdb.WithLogger(nil),
+			surrealdb.WithRetry(0, time.Second),
+		}
+		for _, opt := range invalid {
+			_, err := surrealdb.Connect(context.Background(), unreachable, opt)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("connection is retried", func(t *testing.T) {
+		start := time.Now()
+		_, err := surrealdb.Connect(context.Background(), unreachable, quiet, surrealdb.WithRetry(3, 20*time.Millisecond))
+		require.Error(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("retries stop when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := surrealdb.Connect(ctx, unreachable, quiet, surrealdb.WithRetry(3, time.Hour))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestWithToken(t *testing.T) {
+	var lock sync.Mutex
+	var methods, authorizations []string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		methods = append(methods, req.Method)
+		authorizations = append(authorizations, req.HTTP.Header.Get("Authorization"))
+		lock.Unlock()
+		return []interface{}{}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithToken("cloud-token"),
+	)
+	require.NoError(t, err)
+	_, err = surrealdb.Select[[]map[string]interface{}](db, models.Table("users"))
+	require.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, []string{"authenticate", "select"}, methods)
+	require.Equal(t, "Bearer cloud-token", authorizations[1])
+}
+
+func TestNotificationOptions(t *testing.T) {
+	upgrader := gorilla.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	db, err := surrealdb.Connect(ctx, "ws"+strings.TrimPrefix(server.URL, "http"),
+		surrealdb.WithNotificationBuffer(1),
+	)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// the http engine polls live queries instead of receiving notifications
+	_, err = surrealdb.Connect(ctx, server.URL, surrealdb.WithNotificationBuffer(16))
+	require.Error(t, err)
+
+	_, err = surrealdb.Connect(ctx, server.URL, surrealdb.WithNotificationBuffer(0))
+	require.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package surrealdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// DefaultPageSize is the number of records of a page when Pagination.PageSize is not set
+const DefaultPageSize = 100
+
+// Pagination describes the records paged through by a Paginator.
+type Pagination struct {
+	Table models.Table
+	// PageSize is the number of records of a page, DefaultPageSize when zero.
+	PageSize int
+	// OrderBy is the field path the records are sorted by, such as created_at or address.city.
+	// Records with the same value are sorted by id. The records are sorted by id when empty.
+	OrderBy    string
+	Descending bool
+	// Where optionally filters the records with a condition, which may use the Vars.
+	Where string
+	Vars  map[string]interface{}
+}
+
+// Paginator pages through the records of a table, see NewPaginator.
+type Paginator[T any] struct {
+	db        *DB
+	firstPage string
+	nextPage  string
+	vars      map[string]interface{}
+	// orderBy is the path of the field the records are sorted by, nil when sorted by id.
+	orderBy  []string
+	pageSize int
+
+	// cursor holds the sort key and id of the last record returned, nil before the first page.
+	cursor  map[string]interface{}
+	hasMore bool
+}
+
+// NewPaginator returns a paginator over the records described by p.
+//
+// Pages are delimited by the last record of the previous page, rather than by an offset: each
+// page selects the records sorted after that record. Records created or deleted while paging
+// therefore never shift a page, so that no record is skipped or returned twice, and fetching a
+// page stays as fast deep into the table as at its start, given an index on the OrderBy field.
+//
+//	pages, err := surrealdb.NewPaginator[User](db, surrealdb.Pagination{Table: "user", OrderBy: "created_at"})
+//	for pages.HasMore() {
+//		users, err := pages.NextPage(ctx)
+//	}
+func NewPaginator[T any](db *DB, p Pagination) (*Paginator[T], error) {
+	if p.Table == "" {
+		return nil, fmt.Errorf("no table to paginate")
+	}
+	if p.PageSize < 0 {
+		return nil, fmt.Errorf("invalid page size %d", p.PageSize)
+	}
+	if p.PageSize == 0 {
+		p.PageSize = DefaultPageSize
+	}
+
+	var orderBy []string
+	order := "id"
+	if p.OrderBy != "" && p.OrderBy != "id" {
+		orderBy = strings.Split(p.OrderBy, ".")
+		field, err := models.EscapeFieldPath(p.OrderBy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid order field: %w", err)
+		}
+		order = field
+	}
+
+	vars := make(map[string]interface{}, len(p.Vars)+2)
+	for key, value := range p.Vars {
+		if strings.HasPrefix(key, "page_") {
+			return nil, fmt.Errorf("variable %s uses the prefix reserved by the paginator", key)
+		}
+		vars[key] = value
+	}
+	vars["page_table"] = p.Table
+	vars["page_limit"] = p.PageSize + 1
+
+	op, direction := ">", "ASC"
+	if p.Descending {
+		op, direction = "<", "DESC"
+	}
+
+	after := fmt.Sprintf("id %s $page_id", op)
+	sort := "id " + direction
+	if orderBy != nil {
+		after = fmt.Sprintf("(%[1]s %[2]s $page_key OR (%[1]s = $page_key AND id %[2]s $page_id))", order, op)
+		sort = fmt.Sprintf("%s %s, id %s", order, direction, direction)
+	}
+
+	first := "SELECT * FROM $page_table"
+	next := first + " WHERE " + after
+	if p.Where != "" {
+		first += " WHERE (" + p.Where + ")"
+		next += " AND (" + p.Where + ")"
+	}
+	tail := fmt.Sprintf(" ORDER BY %s LIMIT $page_limit", sort)
+
+	return &Paginator[T]{
+		db:        db,
+		firstPage: first + tail,
+		nextPage:  next + tail,
+		vars:      vars,
+		orderBy:   orderBy,
+		pageSize:  p.PageSize,
+		hasMore:   true,
+	}, nil
+}
+
+// HasMore reports whether there may be records left, that is until a page smaller than the page
+// size was returned.
+func (p *Paginator[T]) HasMore() bool {
+	return p.hasMore
+}
+
+// NextPage returns the next page of records, queried within ctx, and an empty page once there are
+// none left.
+func (p *Paginator[T]) NextPage(ctx context.Context) ([]T, error) {
+	if !p.hasMore {
+		return nil, nil
+	}
+
+	vars := make(map[string]interface{}, len(p.vars)+2)
+	for key, value := range p.vars {
+		vars[key] = value
+	}
+	sql := p.firstPage
+	if p.cursor != nil {
+		sql = p.nextPage
+		for key, value := range p.cursor {
+			vars[key] = value
+		}
+	}
+
+	rows, err := querySingle[[]cbor.RawMessage](p.db.WithContext(ctx), sql, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(*rows) > p.pageSize
+	if more {
+		*rows = (*rows)[:p.pageSize]
+	}
+
+	unmarshaler := p.db.con.GetUnmarshaler()
+	page := make([]T, len(*rows))
+	for i, row := range *rows {
+		if err := connection.DecodeResult(unmarshaler, "query", row, &page[i]); err != nil {
+			p.db.stats.record(err)
+			return nil, err
+		}
+	}
+
+	if len(*rows) > 0 {
+		cursor, err := p.cursorOf((*rows)[len(*rows)-1])
+		if err != nil {
+			return nil, err
+		}
+		p.cursor = cursor
+	}
+	p.hasMore = more
+
+	return page, nil
+}
+
+// cursorOf extracts the sort key and id of a record, which the next page starts after.
+func (p *Paginator[T]) cursorOf(row cbor.RawMessage) (map[string]interface{}, error) {
+	unmarshaler := p.db.con.GetUnmarshaler()
+
+	var fields map[string]cbor.RawMessage
+	if err := unmarshaler.Unmarshal(row, &fields); err != nil {
+		return nil, fmt.Errorf("reading the cursor of the page: %w", err)
+	}
+	var id interface{}
+	if err := unmarshaler.Unmarshal(fields["id"], &id); err != nil {
+		return nil, fmt.Errorf("reading the id of the last record of the page: %w", err)
+	}
+	if p.orderBy == nil {
+		return map[string]interface{}{"page_id": id}, nil
+	}
+
+	value := row
+	for _, part := range p.orderBy {
+		fields = nil
+		if err := unmarshaler.Unmarshal(value, &fields); err != nil {
+			return nil, fmt.Errorf("reading the sort key of the last record of the page: %w", err)
+		}
+		var ok bool
+		if value, ok = fields[part]; !ok {
+			return nil, fmt.Errorf("the records have no %s field to sort by", strings.Join(p.orderBy, "."))
+		}
+	}
+
+	var key interface{}
+	if err := unmarshaler.Unmarshal(value, &key); err != nil {
+		return nil, fmt.Errorf("reading the sort key of the last record of the page: %w", err)
+	}
+	return map[string]interface{}{"page_key": key, "page_id": id}, nil
+}
@@ -0,0 +1,69 @@
+package surrealdb_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestPaginator(t *testing.T) {
+	record := func(id string, rank int) map[string]interface{} {
+		return map[string]interface{}{"id": models.NewRecordID("users", id), "username": id, "meta": map[string]interface{}{"rank": rank}}
+	}
+
+	var queries []string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		sql := req.Params[0].(string)
+		queries = append(queries, sql)
+
+		rows := []interface{}{record("a", 1), record("b", 2), record("c", 2)}
+		if strings.Contains(sql, "$page_id") {
+			vars := fmt.Sprint(req.Params[1])
+			require.Contains(t, vars, "page_key:2")
+			require.Contains(t, vars, "users b")
+			rows = []interface{}{record("c", 2)}
+		}
+		return []surrealdb.QueryResult[interface{}]{{Status: "OK", Result: rows}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	pages, err := surrealdb.NewPaginator[testUser](db, surrealdb.Pagination{
+		Table: "users", PageSize: 2, OrderBy: "meta.rank", Where: "active = $active",
+		Vars: map[string]interface{}{"active": true},
+	})
+	require.NoError(t, err)
+
+	var names []string
+	for pages.HasMore() {
+		page, err := pages.NextPage(context.Background())
+		require.NoError(t, err)
+		for _, user := range page {
+			names = append(names, user.Username)
+		}
+	}
+	require.Equal(t, []string{"a", "b", "c"}, names)
+	require.Equal(t, []string{
+		"SELECT * FROM $page_table WHERE (active = $active) ORDER BY meta.rank ASC, id ASC LIMIT $page_limit",
+		"SELECT * FROM $page_table WHERE (meta.rank > $page_key OR (meta.rank = $page_key AND id > $page_id)) AND (active = $active) ORDER BY meta.rank ASC, id ASC LIMIT $page_limit",
+	}, queries)
+
+	_, err = surrealdb.NewPaginator[testUser](db, surrealdb.Pagination{Table: "users", Vars: map[string]interface{}{"page_id": 1}})
+	require.Error(t, err)
+
+	pages, err = surrealdb.NewPaginator[testUser](db, surrealdb.Pagination{Table: "users"})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = pages.NextPage(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.True(t, pages.HasMore())
+}
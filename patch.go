@@ -0,0 +1,113 @@
+package surrealdb
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// ApplyPatch applies the JSON Patch operations ops to the records of what with the patch method,
+// and returns them as they are after the patch, decoded into TResult. Use DiffPatch to compute
+// ops from two versions of a record.
+//
+//	user, err := surrealdb.ApplyPatch[User](db, models.NewRecordID("user", "john"), []surrealdb.PatchData{
+//		{Op: surrealdb.PatchReplace, Path: "/name", Value: "Johnny"},
+//	})
+func ApplyPatch[TResult any, TWhat TableOrRecord](db *DB, what TWhat, ops []PatchData) (*TResult, error) {
+	var res connection.RPCResponse[TResult]
+	if err := db.send(&res, "patch", what, ops, false); err != nil {
+		return nil, err
+	}
+
+	return res.Result, nil
+}
+
+// DiffPatch returns the JSON Patch operations that turn before into after, two values encoded
+// like records, such as two versions of a struct. Fields are compared one by one, so that only
+// the changed ones are replaced, whereas arrays of different lengths are replaced as a whole.
+// Fields that become nil are set to null, and fields left out of after are removed.
+func DiffPatch(before, after interface{}) ([]PatchData, error) {
+	from, err := patchValue(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := patchValue(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffPatch(nil, "", from, to), nil
+}
+
+// patchValue returns v as decoded from its CBOR encoding, so that structs become maps keyed by
+// the names of their fields in the database.
+func patchValue(v interface{}) (interface{}, error) {
+	data, err := models.CborMarshaler{}.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %T: %w", v, err)
+	}
+
+	var decoded interface{}
+	if err := (models.CborUnmarshaler{}).Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+func diffPatch(ops []PatchData, path string, from, to interface{}) []PatchData {
+	switch to := to.(type) {
+	case map[interface{}]interface{}:
+		if from, ok := from.(map[interface{}]interface{}); ok {
+			return diffPatchMap(ops, path, from, to)
+		}
+	case []interface{}:
+		if from, ok := from.([]interface{}); ok && len(from) == len(to) {
+			for i := range to {
+				ops = diffPatch(ops, fmt.Sprintf("%s/%d", path, i), from[i], to[i])
+			}
+			return ops
+		}
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return ops
+	}
+	return append(ops, PatchData{Op: PatchReplace, Path: path, Value: to})
+}
+
+func diffPatchMap(ops []PatchData, path string, from, to map[interface{}]interface{}) []PatchData {
+	for _, key := range sortedPatchKeys(from) {
+		if _, ok := to[key]; !ok {
+			ops = append(ops, PatchData{Op: PatchRemove, Path: path + "/" + escapePatchKey(key)})
+		}
+	}
+	for _, key := range sortedPatchKeys(to) {
+		fromValue, ok := from[key]
+		if !ok {
+			ops = append(ops, PatchData{Op: PatchAdd, Path: path + "/" + escapePatchKey(key), Value: to[key]})
+			continue
+		}
+		ops = diffPatch(ops, path+"/"+escapePatchKey(key), fromValue, to[key])
+	}
+	return ops
+}
+
+func sortedPatchKeys(m map[interface{}]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+// escapePatchKey escapes a key as a JSON Pointer (RFC 6901) reference token.
+func escapePatchKey(key interface{}) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(fmt.Sprint(key))
+}
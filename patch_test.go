@@ -0,0 +1,100 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestDiffPatch(t *testing.T) {
+	type address struct {
+		City   string `json:"city"`
+		Street string `json:"street"`
+	}
+	type user struct {
+		ID      *models.RecordID `json:"id,omitempty"`
+		Name    string           `json:"name"`
+		Nick    *string          `json:"nick"`
+		Tags    []string         `json:"tags"`
+		Address address          `json:"address"`
+		Extra   map[string]int   `json:"extra,omitempty"`
+	}
+
+	nick := "jo"
+	before := user{
+		ID:      &models.RecordID{Table: "user", ID: "john"},
+		Name:    "John",
+		Nick:    &nick,
+		Tags:    []string{"a", "b"},
+		Address: address{City: "Paris", Street: "Main"},
+	}
+	after := before
+	after.Name = "Johnny"
+	after.Nick = nil
+	after.Tags = []string{"a", "c"}
+	after.Address.City = "Lyon"
+	after.Extra = map[string]int{"a/b": 1}
+
+	ops, err := surrealdb.DiffPatch(before, after)
+	require.NoError(t, err)
+	require.Equal(t, []surrealdb.PatchData{
+		{Op: surrealdb.PatchReplace, Path: "/address/city", Value: "Lyon"},
+		{Op: surrealdb.PatchAdd, Path: "/extra", Value: map[interface{}]interface{}{"a/b": uint64(1)}},
+		{Op: surrealdb.PatchReplace, Path: "/name", Value: "Johnny"},
+		{Op: surrealdb.PatchReplace, Path: "/nick", Value: nil},
+		{Op: surrealdb.PatchReplace, Path: "/tags/1", Value: "c"},
+	}, ops)
+
+	ops, err = surrealdb.DiffPatch(before, before)
+	require.NoError(t, err)
+	require.Empty(t, ops)
+
+	after.Tags = []string{"a"}
+	after.Extra = nil
+	ops, err = surrealdb.DiffPatch(before, after)
+	require.NoError(t, err)
+	require.Contains(t, ops, surrealdb.PatchData{Op: surrealdb.PatchReplace, Path: "/tags", Value: []interface{}{"a"}})
+
+	ops, err = surrealdb.DiffPatch(map[string]interface{}{"a": 1, "b": 2}, map[string]interface{}{"a": 1})
+	require.NoError(t, err)
+	require.Equal(t, []surrealdb.PatchData{{Op: surrealdb.PatchRemove, Path: "/b"}}, ops)
+}
+
+func TestApplyPatch(t *testing.T) {
+	var params []interface{}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		params = req.Params
+		return map[string]interface{}{"id": models.NewRecordID("user", "john"), "name": "Johnny"}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	type user struct {
+		ID   *models.RecordID `json:"id"`
+		Name string           `json:"name"`
+	}
+	john, err := surrealdb.ApplyPatch[user](db, models.NewRecordID("user", "john"), []surrealdb.PatchData{
+		{Op: surrealdb.PatchReplace, Path: "/name", Value: "Johnny"},
+		{Op: surrealdb.PatchAdd, Path: "/nick", Value: nil},
+		{Op: surrealdb.PatchMove, Path: "/alias", From: "/nickname"},
+		{Op: surrealdb.PatchRemove, Path: "/age"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Johnny", john.Name)
+
+	require.Len(t, params, 3)
+	require.Equal(t, models.NewRecordID("user", "john"), params[0])
+	require.Equal(t, []interface{}{
+		map[interface{}]interface{}{"op": "replace", "path": "/name", "value": "Johnny"},
+		map[interface{}]interface{}{"op": "add", "path": "/nick", "value": nil},
+		map[interface{}]interface{}{"op": "move", "path": "/alias", "from": "/nickname"},
+		map[interface{}]interface{}{"op": "remove", "path": "/age"},
+	}, params[1])
+	require.Equal(t, false, params[2])
+}
@@ -1,8 +1,11 @@
 package connection
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/surrealdb/surrealdb.go/internal/codec"
 	"github.com/surrealdb/surrealdb.go/pkg/constants"
@@ -23,14 +26,23 @@ type Connection interface {
 	Let(key string, value interface{}) error
 	Unset(key string) error
 	LiveNotifications(id string) (chan Notification, error)
+	RawNotifications() (chan RawNotification, func())
 	GetUnmarshaler() codec.Unmarshaler
 }
 
+// ContextSender is implemented by the connections able to stop waiting for a response once a
+// context is done.
+type ContextSender interface {
+	SendContext(ctx context.Context, res interface{}, method string, params ...interface{}) error
+}
+
 type NewConnectionParams struct {
 	Marshaler   codec.Marshaler
 	Unmarshaler codec.Unmarshaler
 	BaseURL     string
 	Logger      logger.Logger
+	// Hooks instrument the requests and notifications of the connection, see Hooks.
+	Hooks *Hooks
 }
 
 type BaseConnection struct {
@@ -38,6 +50,7 @@ type BaseConnection struct {
 	marshaler   codec.Marshaler
 	unmarshaler codec.Unmarshaler
 	logger      logger.Logger
+	hooks       *Hooks
 
 	responseChannels     map[string]chan []byte
 	responseChannelsLock sync.RWMutex
@@ -45,8 +58,27 @@ type BaseConnection struct {
 	errorChannels     map[string]chan error
 	errorChannelsLock sync.RWMutex
 
-	notificationChannels     map[string]chan Notification
+	notificationChannels     map[string]*liveSubscription
 	notificationChannelsLock sync.RWMutex
+	// notificationBuffer is the number of notifications held for each live query and raw
+	// subscriber that has not read them yet, after which their notifications are dropped
+	notificationBuffer   int
+	droppedNotifications atomic.Uint64
+
+	rawSubscriptions     []*rawSubscription
+	rawSubscriptionsLock sync.RWMutex
+}
+
+// liveSubscription is the channel returned by LiveNotifications, fed from its own queue.
+type liveSubscription struct {
+	ch    chan Notification
+	queue *notificationQueue
+}
+
+type rawSubscription struct {
+	ch    chan RawNotification
+	queue *notificationQueue
+	once  sync.Once
 }
 
 func (bc *BaseConnection) createResponseChannel(id string) (chan []byte, error) {
@@ -85,18 +117,94 @@ func (bc *BaseConnection) createNotificationChannel(liveQueryID string) (chan No
 		return nil, fmt.Errorf("%w: %v", constants.ErrIDInUse, liveQueryID)
 	}
 
-	ch := make(chan Notification)
-	bc.notificationChannels[liveQueryID] = ch
+	sub := &liveSubscription{
+		ch:    make(chan Notification),
+		queue: newNotificationQueue(bc.notificationBuffer),
+	}
+	bc.notificationChannels[liveQueryID] = sub
+	go sub.queue.drain(func(msg queuedNotification) bool {
+		return bc.deliverNotification(sub, msg)
+	})
 
-	return ch, nil
+	return sub.ch, nil
 }
 
-func (bc *BaseConnection) getNotificationChannel(id string) (chan Notification, bool) {
+func (bc *BaseConnection) getNotificationChannel(id string) (*liveSubscription, bool) {
 	bc.notificationChannelsLock.RLock()
 	defer bc.notificationChannelsLock.RUnlock()
-	ch, ok := bc.notificationChannels[id]
+	sub, ok := bc.notificationChannels[id]
 
-	return ch, ok
+	return sub, ok
+}
+
+// deliverNotification decodes a queued notification and waits for the live query consumer to read
+// it. It returns false once the queue of the live query is closed.
+func (bc *BaseConnection) deliverNotification(sub *liveSubscription, msg queuedNotification) bool {
+	raw := msg.notification
+	notification := Notification{ID: raw.ID, Action: raw.Action}
+	if err := bc.unmarshaler.Unmarshal(raw.Result, &notification.Result); err != nil {
+		err := fmt.Errorf("error unmarshalling notification %+v: %w", raw.ID.String(), err)
+		bc.logger.Error(err.Error())
+		return true
+	}
+
+	select {
+	case sub.ch <- notification:
+		bc.hooks.notification(NotificationDelivery{
+			LiveQueryID: raw.ID.String(),
+			Action:      raw.Action,
+			Lag:         time.Since(msg.received),
+		})
+		return true
+	case <-sub.queue.done:
+		return false
+	}
+}
+
+// queueNotification queues n for the consumer of its live query, reporting false when no channel
+// was created for the live query with LiveNotifications.
+func (bc *BaseConnection) queueNotification(n RawNotification, received time.Time) bool {
+	sub, ok := bc.getNotificationChannel(n.ID.String())
+	if !ok {
+		return false
+	}
+	if ok, overflowed := sub.queue.push(n, received); !ok {
+		bc.notificationDropped(n, overflowed)
+	}
+	return true
+}
+
+// notificationDropped counts a notification dropped because its subscriber fell too far behind,
+// logging a warning when the subscriber starts dropping notifications.
+func (bc *BaseConnection) notificationDropped(n RawNotification, overflowed bool) {
+	bc.droppedNotifications.Add(1)
+	if overflowed {
+		bc.logger.Warn("dropping live query notifications, the subscriber is too far behind",
+			"live_query_id", n.ID.String(), "buffer", bc.notificationBuffer)
+	}
+	bc.hooks.notificationDropped(NotificationDelivery{LiveQueryID: n.ID.String(), Action: n.Action})
+}
+
+// DroppedNotifications returns the number of live query notifications dropped because their
+// subscriber, a live query channel or a raw subscription, had not read the previous ones yet.
+func (bc *BaseConnection) DroppedNotifications() uint64 {
+	return bc.droppedNotifications.Load()
+}
+
+// closeNotifications stops delivering notifications to every subscriber, closing the channels of
+// the raw subscriptions.
+func (bc *BaseConnection) closeNotifications() {
+	bc.notificationChannelsLock.RLock()
+	for _, sub := range bc.notificationChannels {
+		sub.queue.close()
+	}
+	bc.notificationChannelsLock.RUnlock()
+
+	bc.rawSubscriptionsLock.RLock()
+	for _, sub := range bc.rawSubscriptions {
+		sub.queue.close()
+	}
+	bc.rawSubscriptionsLock.RUnlock()
 }
 
 func (bc *BaseConnection) removeResponseChannel(id string) {
@@ -148,3 +256,66 @@ func (bc *BaseConnection) LiveNotifications(liveQueryID string) (chan Notificati
 	}
 	return c, err
 }
+
+// RawNotifications subscribes to every live query notification received on the connection,
+// whatever live query it belongs to. The returned function cancels the subscription and closes
+// the channel, as does closing the connection.
+//
+// Notifications are delivered in the order they were received from the server. Each subscriber
+// has its own queue, holding as many notifications as the notification buffer of the connection,
+// constants.DefaultNotificationBuffer by default: once a subscriber falls further behind, its
+// notifications are dropped and counted, see DroppedNotifications, rather than delaying the
+// other subscribers or the responses to requests.
+func (bc *BaseConnection) RawNotifications() (chan RawNotification, func()) {
+	sub := &rawSubscription{
+		ch:    make(chan RawNotification),
+		queue: newNotificationQueue(bc.notificationBuffer),
+	}
+
+	bc.rawSubscriptionsLock.Lock()
+	bc.rawSubscriptions = append(bc.rawSubscriptions, sub)
+	bc.rawSubscriptionsLock.Unlock()
+
+	go func() {
+		defer close(sub.ch)
+		sub.queue.drain(func(msg queuedNotification) bool {
+			select {
+			case sub.ch <- msg.notification:
+				return true
+			case <-sub.queue.done:
+				return false
+			}
+		})
+	}()
+
+	cancel := func() {
+		sub.once.Do(func() {
+			bc.rawSubscriptionsLock.Lock()
+			for i, s := range bc.rawSubscriptions {
+				if s == sub {
+					bc.rawSubscriptions = append(bc.rawSubscriptions[:i], bc.rawSubscriptions[i+1:]...)
+					break
+				}
+			}
+			bc.rawSubscriptionsLock.Unlock()
+
+			sub.queue.close()
+		})
+	}
+
+	return sub.ch, cancel
+}
+
+// publishRawNotification queues n for every raw subscriber, without waiting for any of them. It
+// reports whether there was a subscriber.
+func (bc *BaseConnection) publishRawNotification(n RawNotification, received time.Time) bool {
+	bc.rawSubscriptionsLock.RLock()
+	defer bc.rawSubscriptionsLock.RUnlock()
+
+	for _, sub := range bc.rawSubscriptions {
+		if ok, overflowed := sub.queue.push(n, received); !ok {
+			bc.notificationDropped(n, overflowed)
+		}
+	}
+	return len(bc.rawSubscriptions) > 0
+}
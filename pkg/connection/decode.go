@@ -0,0 +1,166 @@
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/internal/codec"
+)
+
+// DecodeError is returned when the result of a request cannot be decoded into the requested
+// Go type, typically because the schema of the data changed.
+type DecodeError struct {
+	// Method is the RPC method of the request
+	Method string
+	// Target is the table or record the request applies to, when known
+	Target string
+	// Path locates the value that could not be decoded, such as result[0].address.city
+	Path string
+	// CBORType is the type of the value received, such as "UTF-8 text string"
+	CBORType string
+	// GoType is the Go type the value could not be decoded into
+	GoType string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	msg := fmt.Sprintf("cannot decode the result of %s", e.Method)
+	if e.Target != "" {
+		msg += fmt.Sprintf(" on %s", e.Target)
+	}
+	if e.Path != "" {
+		msg += fmt.Sprintf(" at %s", e.Path)
+	}
+	if e.CBORType != "" {
+		return fmt.Sprintf("%s: found %s, expected %s", msg, e.CBORType, e.GoType)
+	}
+	return fmt.Sprintf("%s: %s", msg, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeResult decodes data into dest like unmarshaler does, reporting failures as a *DecodeError
+// locating the value that could not be decoded.
+func DecodeResult(unmarshaler codec.Unmarshaler, method string, data []byte, dest interface{}) error {
+	err := unmarshaler.Unmarshal(data, dest)
+	if err == nil {
+		return nil
+	}
+
+	decodeErr := &DecodeError{Method: method, Err: err}
+	var typeErr *cbor.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		decodeErr.CBORType = typeErr.CBORType
+		decodeErr.GoType = typeErr.GoType
+	}
+	if t := reflect.TypeOf(dest); t != nil {
+		decodeErr.Path = strings.TrimPrefix(locateDecodeError(unmarshaler, data, t), ".")
+	}
+
+	return decodeErr
+}
+
+var cborUnmarshalerType = reflect.TypeOf((*cbor.Unmarshaler)(nil)).Elem()
+
+// locateDecodeError returns the path of the first value of data that cannot be decoded into t,
+// by decoding the fields and items of data one by one. It returns an empty path when the failure
+// is at the top level.
+func locateDecodeError(unmarshaler codec.Unmarshaler, data []byte, t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(cborUnmarshalerType) {
+		return ""
+	}
+
+	fails := func(raw []byte, t reflect.Type) bool {
+		return unmarshaler.Unmarshal(raw, reflect.New(t).Interface()) != nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var fields map[string]cbor.RawMessage
+		if unmarshaler.Unmarshal(data, &fields) != nil {
+			return ""
+		}
+		for _, key := range sortedKeys(fields) {
+			field, ok := fieldByKey(t, key)
+			if ok && fails(fields[key], field.Type) {
+				return "." + key + locateDecodeError(unmarshaler, fields[key], field.Type)
+			}
+		}
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return ""
+		}
+		var entries map[string]cbor.RawMessage
+		if unmarshaler.Unmarshal(data, &entries) != nil {
+			return ""
+		}
+		for _, key := range sortedKeys(entries) {
+			if fails(entries[key], t.Elem()) {
+				return "." + key + locateDecodeError(unmarshaler, entries[key], t.Elem())
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return ""
+		}
+		var items []cbor.RawMessage
+		if unmarshaler.Unmarshal(data, &items) != nil {
+			return ""
+		}
+		for i, item := range items {
+			if fails(item, t.Elem()) {
+				return fmt.Sprintf("[%d]", i) + locateDecodeError(unmarshaler, item, t.Elem())
+			}
+		}
+	}
+
+	return ""
+}
+
+// fieldByKey returns the struct field decoded from key, named by its cbor or json tag.
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	var fallback *reflect.StructField
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name := field.Name
+		for _, tag := range []string{"cbor", "json"} {
+			if value, ok := field.Tag.Lookup(tag); ok {
+				if tagName, _, _ := strings.Cut(value, ","); tagName != "" {
+					name = tagName
+				}
+				break
+			}
+		}
+		if name == key {
+			return field, true
+		}
+		if fallback == nil && strings.EqualFold(name, key) {
+			f := field
+			fallback = &f
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return reflect.StructField{}, false
+}
+
+func sortedKeys(m map[string]cbor.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
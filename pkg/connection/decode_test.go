@@ -0,0 +1,73 @@
+package connection
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestDecodeResult(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type person struct {
+		ID      *models.RecordID `json:"id,omitempty"`
+		Name    string           `json:"name"`
+		Age     int              `json:"age"`
+		Address address          `json:"address"`
+	}
+
+	marshaler := models.CborMarshaler{}
+	unmarshaler := models.CborUnmarshaler{}
+
+	encode := func(result interface{}) []byte {
+		data, err := marshaler.Marshal(RPCResponse[interface{}]{ID: "1", Result: &result})
+		assert.NoError(t, err)
+		return data
+	}
+
+	t.Run("valid result", func(t *testing.T) {
+		data := encode([]interface{}{map[string]interface{}{
+			"id":      models.NewRecordID("person", "john"),
+			"name":    "John",
+			"age":     42,
+			"address": map[string]interface{}{"city": "London"},
+		}})
+
+		var res RPCResponse[[]person]
+		assert.NoError(t, DecodeResult(unmarshaler, "select", data, &res))
+		assert.Equal(t, "London", (*res.Result)[0].Address.City)
+	})
+
+	t.Run("field path of the invalid value", func(t *testing.T) {
+		data := encode([]interface{}{
+			map[string]interface{}{"name": "John", "age": 42, "address": map[string]interface{}{"city": "London"}},
+			map[string]interface{}{"name": "Jane", "age": 41, "address": map[string]interface{}{"city": 12}},
+		})
+
+		var res RPCResponse[[]person]
+		err := DecodeResult(unmarshaler, "select", data, &res)
+
+		var decodeErr *DecodeError
+		assert.True(t, errors.As(err, &decodeErr))
+		assert.Equal(t, "select", decodeErr.Method)
+		assert.Equal(t, "result[1].address.city", decodeErr.Path)
+		assert.Equal(t, "positive integer", decodeErr.CBORType)
+		assert.Equal(t, "string", decodeErr.GoType)
+		assert.Contains(t, err.Error(), "result[1].address.city")
+	})
+
+	t.Run("invalid value in a map", func(t *testing.T) {
+		data := encode(map[string]interface{}{"a": 1, "b": "two"})
+
+		var res RPCResponse[map[string]int]
+		err := DecodeResult(unmarshaler, "query", data, &res)
+
+		var decodeErr *DecodeError
+		assert.True(t, errors.As(err, &decodeErr))
+		assert.Equal(t, "result.b", decodeErr.Path)
+	})
+}
@@ -46,7 +46,8 @@ func NewEmbeddedConnection(p NewConnectionParams) *EmbeddedConnection {
 			unmarshaler: p.Unmarshaler,
 
 			responseChannels:     make(map[string]chan []byte),
-			notificationChannels: make(map[string]chan Notification),
+			notificationChannels: make(map[string]*liveSubscription),
+			notificationBuffer:   constants.DefaultNotificationBuffer,
 		},
 
 		closeChan: make(chan int),
@@ -129,7 +130,7 @@ func (h *EmbeddedConnection) Send(res interface{}, method string, params ...inte
 	resultBytes := cbor.RawMessage(C.GoBytes(unsafe.Pointer(cRes), resSize))
 
 	rpcRes, _ := h.marshaler.Marshal(RPCResponse[cbor.RawMessage]{ID: request.ID, Result: &resultBytes})
-	return h.unmarshaler.Unmarshal(rpcRes, res)
+	return DecodeResult(h.unmarshaler, method, rpcRes, res)
 }
 
 func (h *EmbeddedConnection) Use(namespace, database string) error {
@@ -0,0 +1,123 @@
+package connection
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// JSON-RPC error codes used by SurrealDB
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+type knownServerError struct {
+	code     int      // matches any code when 0
+	contains []string // matches when the message contains any of them, or always when empty
+	err      error
+	hint     string
+}
+
+// knownServerErrors maps server errors to stable error values. The first match wins.
+var knownServerErrors = []knownServerError{
+	{code: CodeMethodNotFound, err: constants.ErrMethodNotFound,
+		hint: "the server does not know this RPC method, check that it is supported by the server version"},
+	{code: CodeInvalidParams, err: constants.ErrInvalidParams,
+		hint: "check the number and types of the parameters sent with the request"},
+	{contains: []string{"already exists", "already contains"}, err: constants.ErrAlreadyExists,
+		hint: "records already exist — use UPSERT or INSERT IGNORE, see https://surrealdb.com/docs/surrealql/statements/upsert"},
+	{contains: []string{"token has expired", "token expired"}, err: constants.ErrTokenExpired,
+		hint: "sign in again or authenticate with a fresh token"},
+	{contains: []string{"access method"}, err: constants.ErrAccessNotFound,
+		hint: "check the name of the access method, and the namespace and database it is defined on with DEFINE ACCESS"},
+	{contains: []string{"problem with authentication", "invalid authentication"}, err: constants.ErrAuthentication,
+		hint: "check the credentials, and the namespace, database and access method they belong to"},
+	// the IAM error, and the errors of the PERMISSIONS clauses of tables, functions and parameters
+	{contains: []string{"not enough permissions to perform this action", "you don't have permission to"}, err: constants.ErrPermissionDenied,
+		hint: "the authenticated user lacks the permissions for this operation"},
+	{contains: []string{"read or write conflict"}, err: constants.ErrTransactionConflict,
+		hint: "another transaction changed the same data, the transaction can be retried"},
+	{contains: []string{"too many requests", "rate limit", "throttl", "server is busy"}, err: constants.ErrThrottled,
+		hint: "the server is rate limiting requests, retry later with a backoff, see surrealdb.RetryPolicy"},
+	{code: CodeParseError, err: constants.ErrParse,
+		hint: "the request could not be parsed, see https://surrealdb.com/docs/surrealql"},
+	{contains: []string{"parse error", "failed to parse"}, err: constants.ErrParse,
+		hint: "the query is not valid SurrealQL, see https://surrealdb.com/docs/surrealql"},
+}
+
+// ClassifyError returns the stable error value matching a server error code and message, along
+// with a short hint on how to solve it. Both are empty when the error is not known.
+func ClassifyError(code int, message string) (kind error, hint string) {
+	lower := strings.ToLower(message)
+	for _, known := range knownServerErrors {
+		if known.code != 0 && known.code != code {
+			continue
+		}
+		if len(known.contains) == 0 {
+			return known.err, known.hint
+		}
+		for _, s := range known.contains {
+			if strings.Contains(lower, s) {
+				return known.err, known.hint
+			}
+		}
+	}
+	return nil, ""
+}
+
+// ThrottledError is returned when the server, or a gateway in front of it such as SurrealDB
+// Cloud, rejects a request because it is busy or rate limiting requests. It matches
+// constants.ErrThrottled with errors.Is.
+type ThrottledError struct {
+	// StatusCode is the HTTP status of the response, 429 or 503.
+	StatusCode int
+	// RetryAfter is the wait the server asked for before the next request, or 0 when it did not
+	// send a Retry-After header.
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (HTTP %d, retry after %s)", constants.ErrThrottled, e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s (HTTP %d)", constants.ErrThrottled, e.StatusCode)
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return constants.ErrThrottled
+}
+
+// throttledError returns a *ThrottledError for a response rejected with 429 Too Many Requests or
+// 503 Service Unavailable, and nil for any other response.
+func throttledError(resp *http.Response, now time.Time) error {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil
+	}
+	return &ThrottledError{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), now)}
+}
+
+// parseRetryAfter parses a Retry-After header, a number of seconds or an HTTP date, into the
+// wait it asks for. It returns 0 when the header is missing or invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
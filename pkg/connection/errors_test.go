@@ -0,0 +1,89 @@
+package connection
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+func TestRPCErrorClassification(t *testing.T) {
+	cases := []struct {
+		err      RPCError
+		expected error
+	}{
+		{RPCError{Code: -32000, Message: "There was a problem with the database: Database record `users:1` already exists"}, constants.ErrAlreadyExists},
+		{RPCError{Code: -32000, Message: "Database index `email` already contains 'a@b.c'"}, constants.ErrAlreadyExists},
+		{RPCError{Code: -32000, Message: "There was a problem with authentication"}, constants.ErrAuthentication},
+		{RPCError{Code: -32000, Message: "There was a problem with the database: The token has expired"}, constants.ErrTokenExpired},
+		{RPCError{Code: -32000, Message: "Not enough permissions to perform this action"}, constants.ErrPermissionDenied},
+		{RPCError{Code: -32000, Message: "IAM error: Not enough permissions to perform this action"}, constants.ErrPermissionDenied},
+		{RPCError{Code: -32000, Message: "You don't have permission to run the fn::score function"}, constants.ErrPermissionDenied},
+		{RPCError{Code: -32000, Message: "You don't have permission to view the $secret parameter"}, constants.ErrPermissionDenied},
+		{RPCError{Code: CodeMethodNotFound, Message: "Method not found"}, constants.ErrMethodNotFound},
+		{RPCError{Code: CodeInvalidParams, Message: "Invalid params"}, constants.ErrInvalidParams},
+		{RPCError{Code: CodeParseError, Message: "Parse error"}, constants.ErrParse},
+		{RPCError{Code: -32000, Description: "Failed to commit transaction due to a read or write conflict"}, constants.ErrTransactionConflict},
+		{RPCError{Code: -32000, Message: "Too many requests, rate limit exceeded"}, constants.ErrThrottled},
+		{RPCError{Code: -32000, Message: "The database access method 'account' does not exist in database 'main'"}, constants.ErrAccessNotFound},
+		{RPCError{Code: -32000, Message: "There was a problem with the database: The refresh token has expired"}, constants.ErrTokenExpired},
+	}
+
+	for _, c := range cases {
+		var err error = &c.err
+		assert.ErrorIs(t, err, c.expected, c.err.Message)
+		assert.NotEmpty(t, c.err.Hint())
+		assert.Contains(t, err.Error(), "hint: ")
+	}
+}
+
+func TestRPCErrorUnknown(t *testing.T) {
+	rpcErr := RPCError{Code: -32000, Message: "Something unexpected"}
+
+	assert.Nil(t, errors.Unwrap(rpcErr))
+	assert.Empty(t, rpcErr.Hint())
+	assert.Equal(t, "Something unexpected", rpcErr.Error())
+
+	// capabilities and other errors mentioning what is not allowed are not permission errors
+	for _, message := range []string{
+		"Function 'http::get' is not allowed to be executed",
+		"Access to network target 'example.com:443' is not allowed",
+		"Scripting functions are not allowed",
+	} {
+		var err error = &RPCError{Code: -32000, Message: message}
+		assert.False(t, errors.Is(err, constants.ErrPermissionDenied), message)
+	}
+}
+
+func TestThrottledError(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		status     int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{http.StatusTooManyRequests, "", 0},
+		{http.StatusTooManyRequests, "3", 3 * time.Second},
+		{http.StatusServiceUnavailable, now.Add(time.Minute).Format(http.TimeFormat), time.Minute},
+		{http.StatusServiceUnavailable, now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{http.StatusTooManyRequests, "soon", 0},
+	}
+
+	for _, c := range cases {
+		resp := &http.Response{StatusCode: c.status, Header: http.Header{}}
+		resp.Header.Set("Retry-After", c.retryAfter)
+		err := throttledError(resp, now)
+		assert.ErrorIs(t, err, constants.ErrThrottled, c.retryAfter)
+
+		var throttled *ThrottledError
+		assert.ErrorAs(t, err, &throttled)
+		assert.Equal(t, c.status, throttled.StatusCode)
+		assert.Equal(t, c.expected, throttled.RetryAfter, c.retryAfter)
+	}
+
+	assert.Nil(t, throttledError(&http.Response{StatusCode: http.StatusBadRequest}, now))
+}
@@ -0,0 +1,154 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// RequestStart describes a request about to be sent, see Hooks.
+type RequestStart struct {
+	ID     string
+	Method string
+	// Params are the parameters of the request, which must not be modified.
+	Params []interface{}
+	// RequestSize is the size in bytes of the encoded request.
+	RequestSize int
+}
+
+// Target returns the table or record the request applies to, or the function called by run
+// requests. It is empty for queries.
+func (r RequestStart) Target() string {
+	switch v := r.what().(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case models.RecordID:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Table returns the table the request applies to, empty for queries and functions.
+func (r RequestStart) Table() string {
+	if r.Method == "run" {
+		return ""
+	}
+	switch v := r.what().(type) {
+	case models.Table:
+		return string(v)
+	case string:
+		return v
+	case models.RecordID:
+		return v.Table
+	case *models.RecordID:
+		return v.Table
+	case models.RecordIDRange:
+		return string(v.Table)
+	default:
+		return ""
+	}
+}
+
+// Statement returns the SurrealQL of query requests, empty for the other methods.
+func (r RequestStart) Statement() string {
+	if r.Method != "query" || len(r.Params) == 0 {
+		return ""
+	}
+	switch statement := r.Params[0].(type) {
+	case string:
+		return statement
+	case *models.EncodedString:
+		return statement.String()
+	default:
+		return ""
+	}
+}
+
+// what returns the parameter holding the table or record of the request.
+func (r RequestStart) what() interface{} {
+	switch {
+	case r.Method == "query" || len(r.Params) == 0:
+		return nil
+	case r.Method == "relate":
+		// relate takes the in record, the relation table and the out record
+		if len(r.Params) > 1 {
+			return r.Params[1]
+		}
+		return nil
+	default:
+		return r.Params[0]
+	}
+}
+
+// RequestEnd describes a request once its response was received, or it failed, see Hooks.
+type RequestEnd struct {
+	ID       string
+	Method   string
+	Duration time.Duration
+	// RequestSize and ResponseSize are the sizes in bytes of the encoded request and response.
+	// ResponseSize is zero when no response was received.
+	RequestSize  int
+	ResponseSize int
+	Err          error
+}
+
+// NotificationDelivery describes a live query notification handed to its consumer, see Hooks.
+type NotificationDelivery struct {
+	LiveQueryID string
+	Action      Action
+	// Lag is the time between the reception of the notification and its delivery, spent waiting
+	// in the queue of the live query for the consumer to read it. It is zero for a notification
+	// that was dropped.
+	Lag time.Duration
+}
+
+// Hooks are called by the connections around requests and notifications, to instrument them
+// with tracing or metrics. Every hook is optional, and hooks must be safe for concurrent use.
+type Hooks struct {
+	// OnRequestStart is called before a request is sent. The context it returns, which may for
+	// example carry a span, is the one given to OnRequestEnd.
+	OnRequestStart func(ctx context.Context, req RequestStart) context.Context
+	// OnRequestEnd is called once the request completed.
+	OnRequestEnd func(ctx context.Context, req RequestEnd)
+	// OnNotification is called once a live query notification was delivered. It is only called
+	// by the engines supporting live queries.
+	OnNotification func(n NotificationDelivery)
+	// OnNotificationDropped is called when a notification is dropped because its subscriber, a
+	// live query channel or a raw subscription, fell too far behind to hold it.
+	OnNotificationDropped func(n NotificationDelivery)
+}
+
+func (h *Hooks) requestStart(ctx context.Context, req RequestStart) context.Context {
+	if h == nil || h.OnRequestStart == nil {
+		return ctx
+	}
+	return h.OnRequestStart(ctx, req)
+}
+
+func (h *Hooks) requestEnd(ctx context.Context, req RequestEnd) {
+	if h == nil || h.OnRequestEnd == nil {
+		return
+	}
+	h.OnRequestEnd(ctx, req)
+}
+
+func (h *Hooks) notification(n NotificationDelivery) {
+	if h == nil || h.OnNotification == nil {
+		return
+	}
+	h.OnNotification(n)
+}
+
+func (h *Hooks) notificationDropped(n NotificationDelivery) {
+	if h == nil || h.OnNotificationDropped == nil {
+		return
+	}
+	h.OnNotificationDropped(n)
+}
@@ -0,0 +1,34 @@
+package connection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestRequestStartDescription(t *testing.T) {
+	encoded, err := models.NewEncodedString("SELECT * FROM user")
+	assert.NoError(t, err)
+
+	cases := []struct {
+		req                      RequestStart
+		target, table, statement string
+	}{
+		{RequestStart{Method: "select", Params: []interface{}{models.NewRecordID("user", "john")}}, "user:john", "user", ""},
+		{RequestStart{Method: "create", Params: []interface{}{models.Table("user"), nil}}, "user", "user", ""},
+		{RequestStart{Method: "delete", Params: []interface{}{models.NewRecordIDRange("user", 1, 10)}}, "user:1..10", "user", ""},
+		{RequestStart{Method: "relate", Params: []interface{}{models.NewRecordID("user", "a"), models.Table("follows"), models.NewRecordID("user", "b")}}, "follows", "follows", ""},
+		{RequestStart{Method: "query", Params: []interface{}{"SELECT * FROM user", nil}}, "", "", "SELECT * FROM user"},
+		{RequestStart{Method: "query", Params: []interface{}{encoded, nil}}, "", "", "SELECT * FROM user"},
+		{RequestStart{Method: "run", Params: []interface{}{"fn::score", nil, []interface{}{}}}, "fn::score", "", ""},
+		{RequestStart{Method: "ping"}, "", "", ""},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.target, c.req.Target(), c.req.Method)
+		assert.Equal(t, c.table, c.req.Table(), c.req.Method)
+		assert.Equal(t, c.statement, c.req.Statement(), c.req.Method)
+	}
+}
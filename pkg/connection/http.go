@@ -5,14 +5,20 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/http/httptrace"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/surrealdb/surrealdb.go/internal/codec"
 
 	"github.com/surrealdb/surrealdb.go/internal/rand"
 	"github.com/surrealdb/surrealdb.go/pkg/constants"
+	"github.com/surrealdb/surrealdb.go/pkg/logger"
 )
 
 type HTTPConnection struct {
@@ -20,6 +26,48 @@ type HTTPConnection struct {
 
 	httpClient *http.Client
 	variables  sync.Map
+
+	// sessionVariables holds the values defined with Let. HTTP requests are stateless, so they
+	// are sent along with every query instead of being kept by the server.
+	sessionVariables sync.Map
+
+	// streams bounds the number of requests in flight, when set
+	streams         chan struct{}
+	warmConnections int
+	stats           httpStats
+
+	// timeout, poolSize and http2 are the settings of the HTTP client, kept to apply them to the
+	// client given to SetHTTPClient too
+	timeout  time.Duration
+	poolSize int
+	http2    *bool
+}
+
+// HTTPStats holds counters about the requests made by an HTTPConnection and the reuse of the
+// underlying connections.
+type HTTPStats struct {
+	Requests uint64
+	// HTTP2Requests counts the requests answered over HTTP/2
+	HTTP2Requests uint64
+	// NewConnections counts the requests that had to open a connection
+	NewConnections uint64
+	// ReusedConnections counts the requests sent on a connection opened before
+	ReusedConnections uint64
+	// IdleConnections counts the requests sent on a connection taken from the idle pool
+	IdleConnections uint64
+}
+
+type httpStats struct {
+	requests, http2Requests, newConnections, reusedConnections, idleConnections atomic.Uint64
+}
+
+// sessionlessMethods can be called before a namespace and database are selected
+var sessionlessMethods = map[string]bool{
+	"signin":       true,
+	"signup":       true,
+	"authenticate": true,
+	"invalidate":   true,
+	"version":      true,
 }
 
 func NewHTTPConnection(p NewConnectionParams) *HTTPConnection {
@@ -28,9 +76,15 @@ func NewHTTPConnection(p NewConnectionParams) *HTTPConnection {
 			marshaler:   p.Marshaler,
 			unmarshaler: p.Unmarshaler,
 			baseURL:     p.BaseURL,
+			logger:      p.Logger,
+			hooks:       p.Hooks,
 		},
 	}
 
+	if con.logger == nil {
+		con.logger = logger.New(slog.NewJSONHandler(os.Stdout, nil))
+	}
+
 	if con.httpClient == nil {
 		con.httpClient = &http.Client{
 			Timeout: constants.DefaultHTTPTimeout, // Set a default timeout to avoid hanging requests
@@ -55,7 +109,7 @@ func (h *HTTPConnection) Connect() error {
 		return err
 	}
 
-	return nil
+	return h.warm(ctx)
 }
 
 func (h *HTTPConnection) Close() error {
@@ -63,12 +117,125 @@ func (h *HTTPConnection) Close() error {
 }
 
 func (h *HTTPConnection) SetTimeout(timeout time.Duration) *HTTPConnection {
+	h.timeout = timeout
 	h.httpClient.Timeout = timeout
 	return h
 }
 
+// SetPoolSize limits the number of connections opened to the server and keeps up to size of them
+// idle for reuse.
+func (h *HTTPConnection) SetPoolSize(size int) *HTTPConnection {
+	h.poolSize = size
+	h.tuneTransport()
+	return h
+}
+
+// SetHTTP2 sets whether HTTP/2 is negotiated with https servers, multiplexing concurrent requests
+// over a single connection. It is enabled by default. Plain http connections use HTTP/1.1.
+func (h *HTTPConnection) SetHTTP2(enabled bool) *HTTPConnection {
+	h.http2 = &enabled
+	h.tuneTransport()
+	return h
+}
+
+// SetMaxConcurrentStreams bounds the number of requests in flight at once, which are the
+// concurrent streams of the connection over HTTP/2. Requests beyond it wait for a slot.
+func (h *HTTPConnection) SetMaxConcurrentStreams(n int) *HTTPConnection {
+	h.streams = make(chan struct{}, n)
+	return h
+}
+
+// SetWarmPool opens n connections to the server on Connect and keeps them idle, so that a burst
+// of requests does not pay for opening connections. Over HTTP/2 a single connection is enough.
+func (h *HTTPConnection) SetWarmPool(n int) *HTTPConnection {
+	h.warmConnections = n
+	h.tuneTransport()
+	return h
+}
+
+// Stats returns a snapshot of the request and connection reuse counters.
+func (h *HTTPConnection) Stats() HTTPStats {
+	return HTTPStats{
+		Requests:          h.stats.requests.Load(),
+		HTTP2Requests:     h.stats.http2Requests.Load(),
+		NewConnections:    h.stats.newConnections.Load(),
+		ReusedConnections: h.stats.reusedConnections.Load(),
+		IdleConnections:   h.stats.idleConnections.Load(),
+	}
+}
+
+// tuneTransport applies the pool size, HTTP/2 and warm pool settings to the transport of the HTTP
+// client.
+func (h *HTTPConnection) tuneTransport() {
+	if h.poolSize == 0 && h.http2 == nil && h.warmConnections == 0 {
+		return
+	}
+	transport := h.transport()
+	if transport == nil {
+		h.logger.Warn("the transport of the HTTP client is not an *http.Transport, pool size, HTTP/2 and warm pool settings are not applied")
+		return
+	}
+
+	if h.poolSize > 0 {
+		transport.MaxConnsPerHost = h.poolSize
+		transport.MaxIdleConnsPerHost = h.poolSize
+	}
+	if h.http2 != nil {
+		transport.ForceAttemptHTTP2 = *h.http2
+	}
+	if transport.MaxIdleConnsPerHost < h.warmConnections {
+		transport.MaxIdleConnsPerHost = h.warmConnections
+	}
+}
+
+// transport returns the transport of the HTTP client, replacing the shared default transport
+// with a copy before it is tuned. It returns nil when the client has another kind of
+// http.RoundTripper, which cannot be tuned.
+func (h *HTTPConnection) transport() *http.Transport {
+	switch transport := h.httpClient.Transport.(type) {
+	case nil:
+	case *http.Transport:
+		if transport != http.DefaultTransport {
+			return transport
+		}
+	default:
+		return nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	h.httpClient.Transport = transport
+	return transport
+}
+
+// warm opens the connections of the warm pool, which return to the idle pool once their
+// request is done.
+func (h *HTTPConnection) warm(ctx context.Context) error {
+	errs := make(chan error, h.warmConnections)
+	for i := 0; i < h.warmConnections; i++ {
+		go func() {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+"/health", http.NoBody)
+			if err == nil {
+				_, err = h.MakeRequest(req)
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < h.warmConnections; i++ {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetHTTPClient sets the client sending the requests. The settings given to SetTimeout,
+// SetPoolSize, SetHTTP2 and SetWarmPool, before or after, apply to client too: its timeout is
+// replaced, and its transport is tuned when it is an *http.Transport.
 func (h *HTTPConnection) SetHTTPClient(client *http.Client) *HTTPConnection {
 	h.httpClient = client
+	if h.timeout > 0 {
+		client.Timeout = h.timeout
+	}
+	h.tuneTransport()
 	return h
 }
 
@@ -77,12 +244,31 @@ func (h *HTTPConnection) GetUnmarshaler() codec.Unmarshaler {
 }
 
 func (h *HTTPConnection) Send(dest any, method string, params ...interface{}) error {
+	return h.SendContext(context.Background(), dest, method, params...)
+}
+
+// SendContext is Send, cancelling the HTTP request once ctx is done.
+func (h *HTTPConnection) SendContext(ctx context.Context, dest any, method string, params ...interface{}) error {
 	if h.baseURL == "" {
 		return constants.ErrNoBaseURL
 	}
 
+	switch method {
+	case "use", "let", "unset":
+		// kept on the client side, but not changed once the request was given up on
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return h.sendSessionMethod(method, params)
+	case "live", "kill":
+		return &UnsupportedByEngineError{Engine: "http", Method: method}
+	case "query":
+		params = h.withSessionVariables(params)
+	}
+
+	id := rand.String(constants.RequestIDLength)
 	request := &RPCRequest{
-		ID:     rand.String(constants.RequestIDLength),
+		ID:     id,
 		Method: method,
 		Params: params,
 	}
@@ -91,23 +277,37 @@ func (h *HTTPConnection) Send(dest any, method string, params ...interface{}) er
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, h.baseURL+"/rpc", bytes.NewBuffer(reqBody))
+	ctx = h.hooks.requestStart(ctx, RequestStart{ID: id, Method: method, Params: params, RequestSize: len(reqBody)})
+	start := time.Now()
+	responseSize, err := h.roundTrip(ctx, dest, method, reqBody)
+	h.hooks.requestEnd(ctx, RequestEnd{
+		ID:           id,
+		Method:       method,
+		Duration:     time.Since(start),
+		RequestSize:  len(reqBody),
+		ResponseSize: responseSize,
+		Err:          err,
+	})
+	return err
+}
+
+// roundTrip posts an encoded request, decodes its response into dest, and returns the size of the
+// response.
+func (h *HTTPConnection) roundTrip(ctx context.Context, dest any, method string, reqBody []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.baseURL+"/rpc", bytes.NewBuffer(reqBody))
 	if err != nil {
-		return err
+		return 0, err
 	}
 	req.Header.Set("Accept", "application/cbor")
 	req.Header.Set("Content-Type", "application/cbor")
 
-	if namespace, ok := h.variables.Load("namespace"); ok {
+	namespace, hasNamespace := h.variables.Load("namespace")
+	database, hasDatabase := h.variables.Load("database")
+	if hasNamespace && hasDatabase {
 		req.Header.Set("Surreal-NS", namespace.(string))
-	} else {
-		return constants.ErrNoNamespaceOrDB
-	}
-
-	if database, ok := h.variables.Load("database"); ok {
 		req.Header.Set("Surreal-DB", database.(string))
-	} else {
-		return constants.ErrNoNamespaceOrDB
+	} else if !sessionlessMethods[method] {
+		return 0, constants.ErrNoNamespaceOrDB
 	}
 
 	if token, ok := h.variables.Load(constants.AuthTokenKey); ok {
@@ -116,31 +316,59 @@ func (h *HTTPConnection) Send(dest any, method string, params ...interface{}) er
 
 	respData, err := h.MakeRequest(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	var rpcRes RPCResponse[interface{}]
+	// the result is only decoded once, into dest
+	var rpcRes RPCResponse[cbor.RawMessage]
 	if err := h.unmarshaler.Unmarshal(respData, &rpcRes); err != nil {
-		return err
+		return len(respData), err
 	}
 	if rpcRes.Error != nil {
-		return rpcRes.Error
+		return len(respData), rpcRes.Error
 	}
 
 	if dest != nil {
-		return h.unmarshaler.Unmarshal(respData, dest)
+		return len(respData), DecodeResult(h.unmarshaler, method, respData, dest)
 	}
 
-	return nil
+	return len(respData), nil
 }
 
 func (h *HTTPConnection) MakeRequest(req *http.Request) ([]byte, error) {
+	if h.streams != nil {
+		select {
+		case h.streams <- struct{}{}:
+			defer func() { <-h.streams }()
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			switch {
+			case info.WasIdle:
+				h.stats.idleConnections.Add(1)
+				h.stats.reusedConnections.Add(1)
+			case info.Reused:
+				h.stats.reusedConnections.Add(1)
+			default:
+				h.stats.newConnections.Add(1)
+			}
+		},
+	}))
+	h.stats.requests.Add(1)
+
 	resp, err := h.httpClient.Do(req)
 
 	if err != nil {
 		return nil, fmt.Errorf("error making HTTP request: %w", err)
 	}
 	defer resp.Body.Close()
+	if resp.ProtoMajor == 2 {
+		h.stats.http2Requests.Add(1)
+	}
 
 	respBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -150,6 +378,9 @@ func (h *HTTPConnection) MakeRequest(req *http.Request) ([]byte, error) {
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		return respBytes, nil
 	}
+	if err := throttledError(resp, time.Now()); err != nil {
+		return nil, err
+	}
 
 	var errorResponse RPCResponse[any]
 	err = h.unmarshaler.Unmarshal(respBytes, &errorResponse)
@@ -166,12 +397,89 @@ func (h *HTTPConnection) Use(namespace, database string) error {
 	return nil
 }
 
+// Let defines a session variable. It is kept by the connection and sent with every query.
 func (h *HTTPConnection) Let(key string, value interface{}) error {
-	h.variables.Store(key, value)
+	if key == constants.AuthTokenKey {
+		h.variables.Store(key, value)
+		return nil
+	}
+	h.sessionVariables.Store(key, value)
 	return nil
 }
 
 func (h *HTTPConnection) Unset(key string) error {
-	h.variables.Delete(key)
+	if key == constants.AuthTokenKey {
+		h.variables.Delete(key)
+		return nil
+	}
+	h.sessionVariables.Delete(key)
 	return nil
 }
+
+// LiveNotifications is not available over HTTP, which cannot receive server pushed messages.
+func (h *HTTPConnection) LiveNotifications(id string) (chan Notification, error) {
+	return nil, &UnsupportedByEngineError{Engine: "http", Method: "live"}
+}
+
+// sendSessionMethod handles the RPC methods changing the session state, which the HTTP engine
+// keeps on the client side.
+func (h *HTTPConnection) sendSessionMethod(method string, params []interface{}) error {
+	switch method {
+	case "use":
+		if len(params) != 2 {
+			return fmt.Errorf("use expects a namespace and a database")
+		}
+		namespace, nsOK := params[0].(string)
+		database, dbOK := params[1].(string)
+		if !nsOK || !dbOK {
+			return fmt.Errorf("use expects a namespace and a database")
+		}
+		return h.Use(namespace, database)
+	case "let":
+		if len(params) != 2 {
+			return fmt.Errorf("let expects a key and a value")
+		}
+		key, ok := params[0].(string)
+		if !ok {
+			return fmt.Errorf("let expects a key and a value")
+		}
+		return h.Let(key, params[1])
+	default:
+		if len(params) != 1 {
+			return fmt.Errorf("unset expects a key")
+		}
+		key, ok := params[0].(string)
+		if !ok {
+			return fmt.Errorf("unset expects a key")
+		}
+		return h.Unset(key)
+	}
+}
+
+// withSessionVariables merges the session variables into the variables of a query request.
+// Variables given with the query take precedence.
+func (h *HTTPConnection) withSessionVariables(params []interface{}) []interface{} {
+	vars := map[string]interface{}{}
+	h.sessionVariables.Range(func(key, value any) bool {
+		vars[key.(string)] = value
+		return true
+	})
+	if len(vars) == 0 {
+		return params
+	}
+
+	if len(params) > 1 {
+		if queryVars, ok := params[1].(map[string]interface{}); ok {
+			for k, v := range queryVars {
+				vars[k] = v
+			}
+		}
+	}
+
+	merged := append([]interface{}{}, params...)
+	for len(merged) < 2 {
+		merged = append(merged, nil)
+	}
+	merged[1] = vars
+	return merged
+}
@@ -7,10 +7,15 @@ import (
 	"encoding/base64"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
 	"github.com/surrealdb/surrealdb.go/pkg/models"
 )
 
@@ -77,3 +82,204 @@ func (s *HTTPTestSuite) TestMockClientEngine_MakeRequest() {
 	_, err := httpEngine.MakeRequest(req)
 	s.Require().Error(err, "should return error for status code 400")
 }
+
+func newMockHTTPConnection(fn RoundTripFunc) *HTTPConnection {
+	httpEngine := NewHTTPConnection(NewConnectionParams{
+		BaseURL:     "http://test.surreal",
+		Marshaler:   models.CborMarshaler{},
+		Unmarshaler: models.CborUnmarshaler{},
+	})
+	httpEngine.SetHTTPClient(NewTestClient(fn))
+	return httpEngine
+}
+
+func mockRPCResponse(result interface{}) *http.Response {
+	respBody, _ := models.CborMarshaler{}.Marshal(map[string]interface{}{
+		"id":     "mock",
+		"result": result,
+	})
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader(respBody)),
+		Header:     make(http.Header),
+	}
+}
+
+func (s *HTTPTestSuite) TestSessionVariablesSentWithQuery() {
+	var received RPCRequest
+	httpEngine := newMockHTTPConnection(func(req *http.Request) *http.Response {
+		s.Equal("app", req.Header.Get("Surreal-NS"))
+		s.Equal("main", req.Header.Get("Surreal-DB"))
+		s.Equal("Bearer token", req.Header.Get("Authorization"))
+
+		body, _ := io.ReadAll(req.Body)
+		s.Require().NoError(models.CborUnmarshaler{}.Unmarshal(body, &received))
+		return mockRPCResponse([]interface{}{})
+	})
+
+	s.Require().NoError(httpEngine.Send(nil, "use", "app", "main"))
+	s.Require().NoError(httpEngine.Let(constants.AuthTokenKey, "token"))
+	s.Require().NoError(httpEngine.Send(nil, "let", "tenant", "acme"))
+	s.Require().NoError(httpEngine.Let("region", "eu"))
+	s.Require().NoError(httpEngine.Unset("region"))
+
+	err := httpEngine.Send(nil, "query", "SELECT * FROM users WHERE tenant = $tenant", map[string]interface{}{
+		"limit": 10,
+	})
+	s.Require().NoError(err)
+
+	s.Require().Len(received.Params, 2)
+	vars, ok := received.Params[1].(map[interface{}]interface{})
+	s.Require().True(ok)
+	s.Equal("acme", vars["tenant"])
+	s.EqualValues(10, vars["limit"])
+	s.NotContains(vars, "region")
+	s.NotContains(vars, constants.AuthTokenKey)
+}
+
+func (s *HTTPTestSuite) TestSessionlessMethodsWithoutNamespace() {
+	called := false
+	httpEngine := newMockHTTPConnection(func(req *http.Request) *http.Response {
+		called = true
+		return mockRPCResponse("token")
+	})
+
+	err := httpEngine.Send(nil, "query", "INFO FOR DB")
+	s.Require().ErrorIs(err, constants.ErrNoNamespaceOrDB)
+	s.False(called)
+
+	err = httpEngine.Send(nil, "signin", map[string]interface{}{"user": "root", "pass": "root"})
+	s.Require().NoError(err)
+	s.True(called)
+}
+
+func (s *HTTPTestSuite) TestLiveQueriesUnsupported() {
+	httpEngine := newMockHTTPConnection(func(req *http.Request) *http.Response {
+		s.Fail("live queries should not reach the server")
+		return nil
+	})
+	s.Require().NoError(httpEngine.Use("app", "main"))
+
+	err := httpEngine.Send(nil, "live", models.Table("users"), false)
+	s.Require().ErrorIs(err, constants.ErrUnsupportedByEngine)
+
+	var unsupported *UnsupportedByEngineError
+	s.Require().ErrorAs(err, &unsupported)
+	s.Equal("live", unsupported.Method)
+
+	_, err = httpEngine.LiveNotifications("id")
+	s.Require().ErrorIs(err, constants.ErrUnsupportedByEngine)
+}
+
+func (s *HTTPTestSuite) TestWarmPoolAndStats() {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := maxInFlight.Load()
+			if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	con := NewHTTPConnection(NewConnectionParams{
+		BaseURL:     server.URL,
+		Marshaler:   models.CborMarshaler{},
+		Unmarshaler: models.CborUnmarshaler{},
+	})
+	con.SetWarmPool(4).SetMaxConcurrentStreams(2)
+	s.Require().NoError(con.Connect())
+
+	stats := con.Stats()
+	s.Equal(uint64(5), stats.Requests, "health check and warm pool")
+	s.LessOrEqual(maxInFlight.Load(), int32(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/health", http.NoBody)
+			s.NoError(err)
+			_, err = con.MakeRequest(req)
+			s.NoError(err)
+		}()
+	}
+	wg.Wait()
+
+	after := con.Stats()
+	s.Equal(uint64(9), after.Requests)
+	s.Equal(stats.NewConnections, after.NewConnections, "requests should reuse the warm connections")
+	s.Equal(uint64(4), after.IdleConnections-stats.IdleConnections)
+	s.LessOrEqual(maxInFlight.Load(), int32(2))
+}
+
+func (s *HTTPTestSuite) TestHTTPClientTuning() {
+	newConnection := func() *HTTPConnection {
+		return NewHTTPConnection(NewConnectionParams{
+			BaseURL:     "http://test.surreal",
+			Marshaler:   models.CborMarshaler{},
+			Unmarshaler: models.CborUnmarshaler{},
+		})
+	}
+
+	// the settings apply to a client set later
+	client := &http.Client{}
+	con := newConnection().SetTimeout(time.Second).SetPoolSize(8).SetHTTP2(false).SetMaxConcurrentStreams(2)
+	con.SetHTTPClient(client)
+	s.Equal(time.Second, client.Timeout)
+	transport, ok := client.Transport.(*http.Transport)
+	s.Require().True(ok)
+	s.Equal(8, transport.MaxConnsPerHost)
+	s.Equal(8, transport.MaxIdleConnsPerHost)
+	s.False(transport.ForceAttemptHTTP2)
+	s.Equal(2, cap(con.streams))
+
+	// and to a client set before
+	custom := &http.Transport{}
+	newConnection().SetHTTPClient(&http.Client{Transport: custom}).SetWarmPool(4)
+	s.Equal(4, custom.MaxIdleConnsPerHost)
+
+	// other round trippers are kept as they are
+	roundTripper := RoundTripFunc(func(req *http.Request) *http.Response { return nil })
+	client = &http.Client{Transport: roundTripper}
+	newConnection().SetPoolSize(8).SetHTTPClient(client)
+	_, ok = client.Transport.(RoundTripFunc)
+	s.True(ok)
+}
+
+func (s *HTTPTestSuite) TestHooks() {
+	type spanKey struct{}
+	var ends []RequestEnd
+	hooks := &Hooks{
+		OnRequestStart: func(ctx context.Context, req RequestStart) context.Context {
+			s.Equal("signin", req.Method)
+			s.Positive(req.RequestSize)
+			return context.WithValue(ctx, spanKey{}, req.ID)
+		},
+		OnRequestEnd: func(ctx context.Context, req RequestEnd) {
+			s.Equal(req.ID, ctx.Value(spanKey{}))
+			ends = append(ends, req)
+		},
+	}
+
+	httpEngine := newMockHTTPConnection(func(req *http.Request) *http.Response {
+		return mockRPCResponse("token")
+	})
+	httpEngine.hooks = hooks
+
+	var token RPCResponse[string]
+	s.Require().NoError(httpEngine.Send(&token, "signin", map[string]interface{}{"user": "root", "pass": "root"}))
+	var wrongType RPCResponse[int]
+	s.Require().Error(httpEngine.Send(&wrongType, "signin", map[string]interface{}{"user": "root", "pass": "root"}))
+
+	s.Require().Len(ends, 2)
+	s.NoError(ends[0].Err)
+	s.Positive(ends[0].ResponseSize)
+	s.Positive(ends[0].Duration)
+	s.Error(ends[1].Err, "the decoding error should be reported")
+}
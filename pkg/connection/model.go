@@ -1,5 +1,11 @@
 package connection
 
+import (
+	"fmt"
+
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
 // RPCError represents a JSON-RPC error
 type RPCError struct {
 	Code        int    `json:"code" msgpack:"code"`
@@ -8,10 +14,46 @@ type RPCError struct {
 }
 
 func (r RPCError) Error() string {
+	msg := r.Message
 	if r.Description != "" {
-		return r.Description
+		msg = r.Description
+	}
+	if hint := r.Hint(); hint != "" {
+		return fmt.Sprintf("%s (hint: %s)", msg, hint)
 	}
-	return r.Message
+	return msg
+}
+
+// Unwrap returns the stable error value matching the server error, such as
+// constants.ErrAlreadyExists, so that callers can test for it with errors.Is.
+func (r RPCError) Unwrap() error {
+	kind, _ := ClassifyError(r.Code, r.message())
+	return kind
+}
+
+// Hint returns a short suggestion on how to solve a known server error.
+func (r RPCError) Hint() string {
+	_, hint := ClassifyError(r.Code, r.message())
+	return hint
+}
+
+func (r RPCError) message() string {
+	return r.Message + " " + r.Description
+}
+
+// UnsupportedByEngineError is returned for an RPC method that the connection engine cannot
+// perform, such as live queries over HTTP. It matches constants.ErrUnsupportedByEngine with errors.Is.
+type UnsupportedByEngineError struct {
+	Engine string
+	Method string
+}
+
+func (e *UnsupportedByEngineError) Error() string {
+	return fmt.Sprintf("method %q is not supported by the %s engine", e.Method, e.Engine)
+}
+
+func (e *UnsupportedByEngineError) Unwrap() error {
+	return constants.ErrUnsupportedByEngine
 }
 
 // RPCRequest represents an incoming JSON-RPC request
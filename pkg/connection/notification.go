@@ -1,12 +1,24 @@
 package connection
 
-import "github.com/surrealdb/surrealdb.go/pkg/models"
+import (
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
 
 type Notification struct {
 	ID     *models.UUID `json:"id,omitempty"`
 	Action Action       `json:"action"`
 	Result interface{}  `json:"result"`
 }
+
+// RawNotification is the envelope of a live query notification with the result left undecoded,
+// for callers that route and decode notifications themselves.
+type RawNotification struct {
+	ID     *models.UUID    `json:"id,omitempty"`
+	Action Action          `json:"action"`
+	Result cbor.RawMessage `json:"result"`
+}
+
 type Action string
 
 const (
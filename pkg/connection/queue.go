@@ -0,0 +1,123 @@
+package connection
+
+import (
+	"sync"
+	"time"
+)
+
+// notificationQueue is the bounded FIFO of the notifications of a single subscriber, a live query
+// channel or a raw subscription, that it has not read yet.
+//
+// The read loop pushes onto it without ever blocking: once the subscriber has fallen size
+// notifications behind, further notifications are dropped and counted, so that a slow consumer
+// delays neither RPC responses nor the other subscribers sharing the socket. Each queue is drained
+// by its own goroutine, which hands the notifications to the subscriber in the order received.
+type notificationQueue struct {
+	mu       sync.Mutex
+	size     int
+	items    []queuedNotification
+	closed   bool
+	dropping bool
+	dropped  uint64
+
+	// ready wakes up the goroutine draining the queue, and done is closed with the queue
+	ready chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+type queuedNotification struct {
+	notification RawNotification
+	received     time.Time
+}
+
+func newNotificationQueue(size int) *notificationQueue {
+	return &notificationQueue{
+		size:  size,
+		ready: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+}
+
+// push appends n without waiting. When the queue is full, n is dropped instead and push returns
+// false, along with whether n is the first notification dropped since the last one accepted. A
+// closed queue discards n.
+func (q *notificationQueue) push(n RawNotification, received time.Time) (ok, overflowed bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return true, false
+	}
+	if len(q.items) >= q.size {
+		q.dropped++
+		overflowed = !q.dropping
+		q.dropping = true
+		return false, overflowed
+	}
+	q.dropping = false
+	q.items = append(q.items, queuedNotification{notification: n, received: received})
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+	return true, false
+}
+
+// next waits for the oldest notification of the queue. It returns false once the queue is closed.
+func (q *notificationQueue) next() (queuedNotification, bool) {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return queuedNotification{}, false
+		}
+		if len(q.items) > 0 {
+			msg := q.items[0]
+			q.items[0] = queuedNotification{}
+			q.items = q.items[1:]
+			q.mu.Unlock()
+			return msg, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.ready:
+		case <-q.done:
+		}
+	}
+}
+
+// drain hands every notification to deliver, until the queue is closed or deliver returns false.
+func (q *notificationQueue) drain(deliver func(msg queuedNotification) bool) {
+	for {
+		msg, ok := q.next()
+		if !ok || !deliver(msg) {
+			return
+		}
+	}
+}
+
+func (q *notificationQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// droppedCount returns the number of notifications dropped because the queue was full.
+func (q *notificationQueue) droppedCount() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// close discards the pending notifications, and stops the goroutine draining the queue.
+func (q *notificationQueue) close() {
+	q.once.Do(func() {
+		q.mu.Lock()
+		q.closed = true
+		q.items = nil
+		q.mu.Unlock()
+		close(q.done)
+	})
+}
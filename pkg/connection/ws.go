@@ -1,9 +1,11 @@
 package connection
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/surrealdb/surrealdb.go/internal/codec"
 
 	"io"
@@ -48,22 +50,30 @@ type WebSocketConnection struct {
 }
 
 func NewWebSocketConnection(p NewConnectionParams) *WebSocketConnection {
+	log := p.Logger
+	if log == nil {
+		log = logger.New(slog.NewJSONHandler(os.Stdout, nil))
+	}
+
 	return &WebSocketConnection{
 		BaseConnection: BaseConnection{
 			baseURL: p.BaseURL,
 
 			marshaler:   p.Marshaler,
 			unmarshaler: p.Unmarshaler,
+			logger:      log,
+			hooks:       p.Hooks,
 
 			responseChannels:     make(map[string]chan []byte),
 			errorChannels:        make(map[string]chan error),
-			notificationChannels: make(map[string]chan Notification),
+			notificationChannels: make(map[string]*liveSubscription),
+			notificationBuffer:   constants.DefaultNotificationBuffer,
 		},
 
 		Conn:      nil,
 		closeChan: make(chan int),
 		Timeout:   constants.DefaultWSTimeout,
-		logger:    logger.New(slog.NewJSONHandler(os.Stdout, nil)),
+		logger:    log,
 	}
 }
 
@@ -98,6 +108,21 @@ func (ws *WebSocketConnection) SetTimeOut(timeout time.Duration) *WebSocketConne
 	return ws
 }
 
+// SetNotificationBuffer sets how many notifications are held for each live query, and each
+// subscriber of RawNotifications, that has not read them yet. The notifications of a consumer
+// that falls further behind are dropped, so that it delays neither the other subscribers nor the
+// responses to requests, see DroppedNotifications.
+func (ws *WebSocketConnection) SetNotificationBuffer(n int) *WebSocketConnection {
+	ws.Option = append(ws.Option, func(ws *WebSocketConnection) error {
+		if n < 1 {
+			return fmt.Errorf("notification buffer must be at least 1, got %d", n)
+		}
+		ws.notificationBuffer = n
+		return nil
+	})
+	return ws
+}
+
 // If path is empty it will use os.stdout/os.stderr
 func (ws *WebSocketConnection) Logger(logData logger.Logger) *WebSocketConnection {
 	ws.logger = logData
@@ -121,6 +146,7 @@ func (ws *WebSocketConnection) Close() error {
 	ws.connLock.Lock()
 	defer ws.connLock.Unlock()
 	close(ws.closeChan)
+	ws.closeNotifications()
 	err := ws.Conn.WriteMessage(gorilla.CloseMessage, gorilla.FormatCloseMessage(constants.CloseMessageCode, ""))
 	if err != nil {
 		return err
@@ -146,6 +172,11 @@ func (ws *WebSocketConnection) GetUnmarshaler() codec.Unmarshaler {
 }
 
 func (ws *WebSocketConnection) Send(dest interface{}, method string, params ...interface{}) error {
+	return ws.SendContext(context.Background(), dest, method, params...)
+}
+
+// SendContext is Send, giving up on waiting for the response once ctx is done.
+func (ws *WebSocketConnection) SendContext(ctx context.Context, dest interface{}, method string, params ...interface{}) error {
 	select {
 	case <-ws.closeChan:
 		return ws.closeError
@@ -158,6 +189,10 @@ func (ws *WebSocketConnection) Send(dest interface{}, method string, params ...i
 		Method: method,
 		Params: params,
 	}
+	data, err := ws.marshaler.Marshal(request)
+	if err != nil {
+		return err
+	}
 
 	responseChan, err := ws.createResponseChannel(id)
 	if err != nil {
@@ -170,36 +205,44 @@ func (ws *WebSocketConnection) Send(dest interface{}, method string, params ...i
 	defer ws.removeResponseChannel(id)
 	defer ws.removeErrorChannel(id)
 
-	if err := ws.write(request); err != nil {
-		return err
+	ctx = ws.hooks.requestStart(ctx, RequestStart{ID: id, Method: method, Params: params, RequestSize: len(data)})
+	end := RequestEnd{ID: id, Method: method, RequestSize: len(data)}
+	start := time.Now()
+	defer func() {
+		end.Duration = time.Since(start)
+		ws.hooks.requestEnd(ctx, end)
+	}()
+
+	if end.Err = ws.write(data); end.Err != nil {
+		return end.Err
 	}
 	timeout := time.After(ws.Timeout)
 
 	select {
+	case <-ctx.Done():
+		end.Err = ctx.Err()
 	case <-timeout:
-		return constants.ErrTimeout
+		end.Err = constants.ErrTimeout
 	case resBytes, open := <-responseChan:
 		if !open {
-			return errors.New("channel closed")
+			end.Err = errors.New("channel closed")
+			break
 		}
+		end.ResponseSize = len(resBytes)
 		if dest != nil {
-			return ws.unmarshaler.Unmarshal(resBytes, dest)
+			end.Err = DecodeResult(ws.unmarshaler, method, resBytes, dest)
 		}
-		return nil
 	case resErr, open := <-errorChan:
 		if !open {
-			return errors.New("error channel closed")
+			end.Err = errors.New("error channel closed")
+			break
 		}
-		return resErr
+		end.Err = resErr
 	}
+	return end.Err
 }
 
-func (ws *WebSocketConnection) write(v interface{}) error {
-	data, err := ws.marshaler.Marshal(v)
-	if err != nil {
-		return err
-	}
-
+func (ws *WebSocketConnection) write(data []byte) error {
 	ws.connLock.Lock()
 	defer ws.connLock.Unlock()
 	return ws.Conn.WriteMessage(gorilla.BinaryMessage, data)
@@ -219,11 +262,29 @@ func (ws *WebSocketConnection) initialize() {
 				}
 				continue
 			}
-			go ws.handleResponse(data)
+			ws.dispatch(data)
 		}
 	}
 }
 
+// dispatch routes a message read from the socket, which it decodes once. Replies to requests are
+// handed to the request waiting for them, while live query notifications are queued for each of
+// their subscribers, so that the read loop never waits for a consumer.
+func (ws *WebSocketConnection) dispatch(data []byte) {
+	var rpcRes RPCResponse[cbor.RawMessage]
+	if err := ws.unmarshaler.Unmarshal(data, &rpcRes); err != nil {
+		ws.logger.Error(fmt.Errorf("error unmarshalling message: %w", err).Error())
+		return
+	}
+
+	if rpcRes.Error != nil || (rpcRes.ID != nil && rpcRes.ID != "") {
+		go ws.handleResponse(rpcRes, data)
+		return
+	}
+
+	ws.handleNotification(rpcRes, time.Now())
+}
+
 func (ws *WebSocketConnection) handleError(err error) bool {
 	if errors.Is(err, net.ErrClosed) {
 		ws.closeError = net.ErrClosed
@@ -239,12 +300,8 @@ func (ws *WebSocketConnection) handleError(err error) bool {
 	return false
 }
 
-func (ws *WebSocketConnection) handleResponse(res []byte) {
-	var rpcRes RPCResponse[interface{}]
-	if err := ws.unmarshaler.Unmarshal(res, &rpcRes); err != nil {
-		panic(err)
-	}
-
+// handleResponse passes a reply, decoded by dispatch from res, on to the request waiting for it.
+func (ws *WebSocketConnection) handleResponse(rpcRes RPCResponse[cbor.RawMessage], res []byte) {
 	if rpcRes.Error != nil {
 		err := fmt.Errorf("rpc request err %w", rpcRes.Error)
 		ws.logger.Error(err.Error())
@@ -262,46 +319,41 @@ func (ws *WebSocketConnection) handleResponse(res []byte) {
 		return
 	}
 
-	if rpcRes.ID != nil && rpcRes.ID != "" {
-		// Try to resolve message as response to query
-		responseChan, ok := ws.getResponseChannel(fmt.Sprintf("%v", rpcRes.ID))
-		if !ok {
-			err := fmt.Errorf("unavailable ResponseChannel %+v", rpcRes.ID)
-			ws.logger.Error(err.Error())
-			return
-		}
-		defer close(responseChan)
-		responseChan <- res
-	} else {
-		// todo: find a surefire way to confirm a notification
-
-		var notificationRes RPCResponse[Notification]
-		if err := ws.unmarshaler.Unmarshal(res, &notificationRes); err != nil {
-			panic(err)
-		}
-
-		if notificationRes.Result.ID == nil {
-			err := fmt.Errorf("response did not contain an 'id' field")
-			ws.logger.Error(err.Error(), "result", fmt.Sprint(rpcRes.Result))
-			return
-		}
-
-		channelID := notificationRes.Result.ID
+	// Try to resolve message as response to query
+	responseChan, ok := ws.getResponseChannel(fmt.Sprintf("%v", rpcRes.ID))
+	if !ok {
+		err := fmt.Errorf("unavailable ResponseChannel %+v", rpcRes.ID)
+		ws.logger.Error(err.Error())
+		return
+	}
+	defer close(responseChan)
+	responseChan <- res
+}
 
-		LiveNotificationChan, ok := ws.getNotificationChannel(channelID.String())
-		if !ok {
-			err := fmt.Errorf("unavailable ResponseChannel %+v", channelID.String())
-			ws.logger.Error(err.Error(), "result", fmt.Sprint(rpcRes.Result))
-			return
-		}
+// handleNotification queues a notification, decoded by dispatch, for the raw subscribers and for
+// the consumer of its live query.
+func (ws *WebSocketConnection) handleNotification(rpcRes RPCResponse[cbor.RawMessage], received time.Time) {
+	if rpcRes.Result == nil {
+		ws.logger.Error("notification did not contain a 'result' field")
+		return
+	}
 
-		var notification RPCResponse[Notification]
-		if err := ws.unmarshaler.Unmarshal(res, &notification); err != nil {
-			err := fmt.Errorf("error unmarshalling notification %+v", channelID.String())
-			ws.logger.Error(err.Error(), "result", fmt.Sprint(rpcRes.Result))
-			return
-		}
+	var raw RawNotification
+	if err := ws.unmarshaler.Unmarshal(*rpcRes.Result, &raw); err != nil {
+		err := fmt.Errorf("error unmarshalling notification: %w", err)
+		ws.logger.Error(err.Error())
+		return
+	}
+	if raw.ID == nil {
+		err := fmt.Errorf("response did not contain an 'id' field")
+		ws.logger.Error(err.Error())
+		return
+	}
 
-		LiveNotificationChan <- *notification.Result
+	published := ws.publishRawNotification(raw, received)
+	if !ws.queueNotification(raw, received) && !published {
+		// a live query read through RawNotifications only, such as by StartLiveQuery, has no channel
+		err := fmt.Errorf("unavailable ResponseChannel %+v", raw.ID.String())
+		ws.logger.Error(err.Error())
 	}
 }
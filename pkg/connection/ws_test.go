@@ -1,9 +1,24 @@
 package connection
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gofrs/uuid"
+	gorilla "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+	"github.com/surrealdb/surrealdb.go/pkg/logger"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
 )
 
 type WsTestSuite struct {
@@ -26,3 +41,316 @@ func (s *WsTestSuite) SetupSuite() {
 func (s *WsTestSuite) TearDownSuite() {
 
 }
+
+func newTestWebSocketConnection() *WebSocketConnection {
+	return NewWebSocketConnection(NewConnectionParams{
+		BaseURL:     "ws://test.surreal",
+		Marshaler:   models.CborMarshaler{},
+		Unmarshaler: models.CborUnmarshaler{},
+	})
+}
+
+func (s *WsTestSuite) TestNotificationQueue_Order() {
+	q := newNotificationQueue(3)
+	for _, action := range []Action{CreateAction, UpdateAction, DeleteAction} {
+		ok, _ := q.push(RawNotification{Action: action}, time.Now())
+		s.Require().True(ok)
+	}
+	s.Equal(3, q.len())
+
+	for _, expected := range []Action{CreateAction, UpdateAction, DeleteAction} {
+		msg, ok := q.next()
+		s.Require().True(ok)
+		s.Equal(expected, msg.notification.Action)
+	}
+
+	q.close()
+	_, ok := q.next()
+	s.False(ok, "next should return false once the queue is closed")
+}
+
+func (s *WsTestSuite) TestNotificationQueue_DropsWhenFull() {
+	q := newNotificationQueue(1)
+	ok, _ := q.push(RawNotification{Action: CreateAction}, time.Now())
+	s.Require().True(ok)
+
+	// pushing onto a full queue never waits, the notification is dropped instead
+	ok, overflowed := q.push(RawNotification{Action: UpdateAction}, time.Now())
+	s.False(ok)
+	s.True(overflowed, "the first dropped notification should be reported")
+	ok, overflowed = q.push(RawNotification{Action: UpdateAction}, time.Now())
+	s.False(ok)
+	s.False(overflowed, "only the first notification of a run of drops should be reported")
+	s.Equal(uint64(2), q.droppedCount())
+
+	msg, ok := q.next()
+	s.Require().True(ok)
+	s.Equal(CreateAction, msg.notification.Action)
+	ok, _ = q.push(RawNotification{Action: DeleteAction}, time.Now())
+	s.True(ok, "the queue should accept notifications again once read")
+
+	q.close()
+	ok, _ = q.push(RawNotification{Action: DeleteAction}, time.Now())
+	s.True(ok, "a closed queue discards notifications without counting them")
+	s.Equal(uint64(2), q.droppedCount())
+}
+
+func (s *WsTestSuite) TestDispatch_ResponsesNotBlockedByNotifications() {
+	ws := newTestWebSocketConnection()
+	defer func() {
+		close(ws.closeChan)
+		ws.closeNotifications()
+	}()
+
+	liveID := models.UUID{UUID: uuid.Must(uuid.NewV4())}
+	notifications, err := ws.LiveNotifications(liveID.String())
+	s.Require().NoError(err)
+
+	notification, err := ws.marshaler.Marshal(map[string]interface{}{
+		"result": map[string]interface{}{
+			"id":     liveID,
+			"action": "CREATE",
+			"result": map[string]interface{}{"name": "remi"},
+		},
+	})
+	s.Require().NoError(err)
+
+	// nobody reads the notification channel, so the notifications past its buffer are dropped
+	count := constants.DefaultNotificationBuffer + 10
+	for i := 0; i < count; i++ {
+		ws.dispatch(notification)
+	}
+
+	responses, err := ws.createResponseChannel("abc")
+	s.Require().NoError(err)
+	response, err := ws.marshaler.Marshal(map[string]interface{}{
+		"id":     "abc",
+		"result": "ok",
+	})
+	s.Require().NoError(err)
+	ws.dispatch(response)
+
+	select {
+	case res := <-responses:
+		var rpcRes RPCResponse[string]
+		s.Require().NoError(ws.unmarshaler.Unmarshal(res, &rpcRes))
+		s.Equal("ok", *rpcRes.Result)
+	case <-time.After(time.Second):
+		s.Fail("response was not delivered while notifications were pending")
+	}
+
+	first := <-notifications
+	s.Equal(CreateAction, first.Action)
+	s.Equal(liveID.String(), first.ID.String())
+	// one notification may be held by the goroutine waiting for the consumer, the others dropped
+	s.GreaterOrEqual(ws.DroppedNotifications(), uint64(count-constants.DefaultNotificationBuffer-1))
+}
+
+func (s *WsTestSuite) TestRawNotifications() {
+	ws := newTestWebSocketConnection()
+	defer func() {
+		close(ws.closeChan)
+		ws.closeNotifications()
+	}()
+
+	raw, cancel := ws.RawNotifications()
+
+	// the live query is not registered through LiveNotifications, raw subscribers still see it
+	liveID := models.UUID{UUID: uuid.Must(uuid.NewV4())}
+	for _, action := range []string{"CREATE", "UPDATE", "DELETE"} {
+		notification, err := ws.marshaler.Marshal(map[string]interface{}{
+			"result": map[string]interface{}{
+				"id":     liveID,
+				"action": action,
+				"result": map[string]interface{}{"name": "remi"},
+			},
+		})
+		s.Require().NoError(err)
+		ws.dispatch(notification)
+	}
+
+	for _, expected := range []Action{CreateAction, UpdateAction, DeleteAction} {
+		n := <-raw
+		s.Equal(expected, n.Action)
+		s.Equal(liveID.String(), n.ID.String())
+
+		var result map[string]string
+		s.Require().NoError(ws.unmarshaler.Unmarshal(n.Result, &result))
+		s.Equal("remi", result["name"])
+	}
+
+	cancel()
+	_, open := <-raw
+	s.False(open, "channel should be closed once the subscription is cancelled")
+}
+
+func (s *WsTestSuite) TestRawNotifications_StalledSubscriber() {
+	ws := newTestWebSocketConnection()
+	defer func() {
+		close(ws.closeChan)
+		ws.closeNotifications()
+	}()
+
+	_, cancelStalled := ws.RawNotifications()
+	defer cancelStalled()
+	raw, cancel := ws.RawNotifications()
+	defer cancel()
+
+	liveID := models.UUID{UUID: uuid.Must(uuid.NewV4())}
+	notification, err := ws.marshaler.Marshal(map[string]interface{}{
+		"result": map[string]interface{}{"id": liveID, "action": "CREATE", "result": "remi"},
+	})
+	s.Require().NoError(err)
+
+	// the stalled subscriber never reads, while the other one keeps up
+	count := 2 * constants.DefaultNotificationBuffer
+	received := make(chan int)
+	go func() {
+		n := 0
+		for n < count {
+			<-raw
+			n++
+		}
+		received <- n
+	}()
+	for i := 0; i < count; i++ {
+		ws.dispatch(notification)
+		if i%constants.DefaultNotificationBuffer == 0 {
+			// lets the reading subscriber catch up, as it would be dropping notifications too otherwise
+			s.Require().Eventually(func() bool { return ws.rawSubscriptions[1].queue.len() == 0 }, time.Second, time.Millisecond)
+		}
+	}
+
+	select {
+	case n := <-received:
+		s.Equal(count, n)
+	case <-time.After(time.Second):
+		s.FailNow("notifications were not delivered while another subscriber was stalled")
+	}
+	s.Equal(uint64(0), ws.rawSubscriptions[1].queue.droppedCount())
+	s.Greater(ws.rawSubscriptions[0].queue.droppedCount(), uint64(0))
+	s.Equal(ws.rawSubscriptions[0].queue.droppedCount(), ws.DroppedNotifications())
+}
+
+func (s *WsTestSuite) TestNotificationHook() {
+	delivered := make(chan NotificationDelivery, 1)
+	ws := NewWebSocketConnection(NewConnectionParams{
+		BaseURL:     "ws://test.surreal",
+		Marshaler:   models.CborMarshaler{},
+		Unmarshaler: models.CborUnmarshaler{},
+		Hooks: &Hooks{OnNotification: func(n NotificationDelivery) {
+			delivered <- n
+		}},
+	})
+	ws.notificationBuffer = 1
+	defer func() {
+		close(ws.closeChan)
+		ws.closeNotifications()
+	}()
+
+	liveID := models.UUID{UUID: uuid.Must(uuid.NewV4())}
+	notifications, err := ws.LiveNotifications(liveID.String())
+	s.Require().NoError(err)
+
+	notification, err := ws.marshaler.Marshal(map[string]interface{}{
+		"result": map[string]interface{}{
+			"id":     liveID,
+			"action": "UPDATE",
+			"result": map[string]interface{}{"name": "remi"},
+		},
+	})
+	s.Require().NoError(err)
+	ws.dispatch(notification)
+
+	time.Sleep(10 * time.Millisecond)
+	<-notifications
+
+	n := <-delivered
+	s.Equal(liveID.String(), n.LiveQueryID)
+	s.Equal(UpdateAction, n.Action)
+	s.GreaterOrEqual(n.Lag, 10*time.Millisecond, "the lag should include the wait for the consumer")
+}
+
+// TestSend_StalledNotificationConsumer checks that a live query consumer which never reads its
+// notifications holds back neither the read loop nor the responses to concurrent requests.
+func (s *WsTestSuite) TestSend_StalledNotificationConsumer() {
+	liveID := models.UUID{UUID: uuid.Must(uuid.NewV4())}
+	marshaler := models.CborMarshaler{}
+	notification, err := marshaler.Marshal(map[string]interface{}{
+		"result": map[string]interface{}{"id": liveID, "action": "CREATE", "result": "remi"},
+	})
+	s.Require().NoError(err)
+
+	upgrader := gorilla.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req RPCRequest
+			if err := (models.CborUnmarshaler{}).Unmarshal(data, &req); err != nil {
+				return
+			}
+
+			// floods the connection with notifications ahead of every response
+			for i := 0; i < 4*constants.DefaultNotificationBuffer; i++ {
+				if err := conn.WriteMessage(gorilla.BinaryMessage, notification); err != nil {
+					return
+				}
+			}
+			result := interface{}("pong")
+			res, err := marshaler.Marshal(RPCResponse[interface{}]{ID: req.ID, Result: &result})
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(gorilla.BinaryMessage, res); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	ws := NewWebSocketConnection(NewConnectionParams{
+		BaseURL:     "ws" + strings.TrimPrefix(server.URL, "http"),
+		Marshaler:   models.CborMarshaler{},
+		Unmarshaler: models.CborUnmarshaler{},
+		Logger:      logger.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	s.Require().NoError(ws.Connect())
+	defer ws.Close()
+
+	// the consumer never reads its notifications
+	_, err = ws.LiveNotifications(liveID.String())
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var res RPCResponse[string]
+			if err := ws.SendContext(ctx, &res, "ping"); err != nil {
+				errs <- err
+				return
+			}
+			if res.Result == nil || *res.Result != "pong" {
+				errs <- fmt.Errorf("unexpected result %v", res.Result)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		s.Require().NoError(err, "requests should complete while a notification consumer is stalled")
+	}
+	s.Greater(ws.DroppedNotifications(), uint64(0))
+}
@@ -16,5 +16,9 @@ const (
 
 	DefaultHTTPTimeout = 10 * time.Second
 
+	// DefaultNotificationBuffer number of live query notifications held for each subscriber
+	// that has not read them yet, after which its notifications are dropped
+	DefaultNotificationBuffer = 128
+
 	OneSecondToNanoSecond = 1_000_000_000
 )
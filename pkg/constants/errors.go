@@ -9,11 +9,26 @@ var (
 	ErrNoRow        = errors.New("error no row")
 )
 var (
-	ErrIDInUse            = errors.New("id already in use")
-	ErrTimeout            = errors.New("timeout")
-	ErrNoBaseURL          = errors.New("base url not set")
-	ErrNoMarshaler        = errors.New("marshaler is not set")
-	ErrNoUnmarshaler      = errors.New("unmarshaler is not set")
-	ErrNoNamespaceOrDB    = errors.New("namespace or database or both are not set")
-	ErrMethodNotAvailable = errors.New("method not available on this connection")
+	ErrIDInUse             = errors.New("id already in use")
+	ErrTimeout             = errors.New("timeout")
+	ErrNoBaseURL           = errors.New("base url not set")
+	ErrNoMarshaler         = errors.New("marshaler is not set")
+	ErrNoUnmarshaler       = errors.New("unmarshaler is not set")
+	ErrNoNamespaceOrDB     = errors.New("namespace or database or both are not set")
+	ErrMethodNotAvailable  = errors.New("method not available on this connection")
+	ErrUnsupportedByEngine = errors.New("operation not supported by the connection engine")
+)
+
+// Server errors, matched with errors.Is against the errors returned by SurrealDB
+var (
+	ErrAlreadyExists       = errors.New("record already exists")
+	ErrPermissionDenied    = errors.New("not enough permissions")
+	ErrAuthentication      = errors.New("authentication failed")
+	ErrTokenExpired        = errors.New("token has expired")
+	ErrAccessNotFound      = errors.New("access method not found")
+	ErrParse               = errors.New("query could not be parsed")
+	ErrMethodNotFound      = errors.New("rpc method not found")
+	ErrInvalidParams       = errors.New("invalid rpc parameters")
+	ErrTransactionConflict = errors.New("transaction conflict")
+	ErrThrottled           = errors.New("server is busy or rate limiting requests")
 )
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 )
 
@@ -28,3 +29,8 @@ func (handler *SlogHandler) Info(msg string, args ...any) {
 func (handler *SlogHandler) Debug(msg string, args ...any) {
 	handler.logger.Debug(msg, args...)
 }
+
+// Enabled reports whether the logger logs messages at level.
+func (handler *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return handler.logger.Enabled(ctx, level)
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"io"
 	"reflect"
+	"sync"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/surrealdb/surrealdb.go/internal/codec"
@@ -75,23 +76,84 @@ func registerCborTags() cbor.TagSet {
 }
 
 type CborMarshaler struct {
+	// Deterministic produces canonical CBOR following the core deterministic encoding of RFC 8949:
+	// map keys are sorted and numbers take their shortest form, so that equal values always
+	// encode to the same bytes. It is meant for hashing and diffing payloads.
+	Deterministic bool
 }
 
 func (c CborMarshaler) Marshal(v interface{}) ([]byte, error) {
 	v = replacerBeforeEncode(v)
+	v, err := encodeSurrealTags(v)
+	if err != nil {
+		return nil, err
+	}
 	em := getCborEncoder()
-	return em.Marshal(v)
+	data, err := em.Marshal(v)
+	if err != nil || !c.Deterministic {
+		return data, err
+	}
+	return canonicalize(data)
 }
 
 func (c CborMarshaler) NewEncoder(w io.Writer) codec.Encoder {
+	if c.Deterministic {
+		return &canonicalEncoder{w: w, marshaler: c}
+	}
 	em := getCborEncoder()
 	return em.NewEncoder(w)
 }
 
+type canonicalEncoder struct {
+	w         io.Writer
+	marshaler CborMarshaler
+}
+
+func (e *canonicalEncoder) Encode(v interface{}) error {
+	data, err := e.marshaler.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+// canonicalize re-encodes a CBOR payload deterministically. Custom types encode their content
+// through their own MarshalCBOR, so sorting has to happen on the encoded payload to also reach
+// maps nested in tags, like the object id of a record id.
+func canonicalize(data []byte) ([]byte, error) {
+	// tags are not registered, so that they decode to cbor.Tag and are written back unchanged
+	dm, err := cbor.DecOptions{}.DecMode()
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := dm.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	opts := cbor.CoreDetEncOptions()
+	opts.Time = cbor.TimeRFC3339Nano
+	opts.TimeTag = cbor.EncTagRequired
+	em, err := opts.EncMode()
+	if err != nil {
+		return nil, err
+	}
+
+	return em.Marshal(v)
+}
+
 type CborUnmarshaler struct {
+	// Options selects how NONE and NULL values are decoded.
+	Options DecodeOptions
 }
 
 func (c CborUnmarshaler) Unmarshal(data []byte, dst interface{}) error {
+	if decoded, err := decodeFieldByField(data, dst, c.Options); decoded {
+		return err
+	}
+
 	dm := getCborDecoder()
 	err := dm.Unmarshal(data, dst)
 	if err != nil {
@@ -107,21 +169,59 @@ func (c CborUnmarshaler) NewDecoder(r io.Reader) codec.Decoder {
 	return dm.NewDecoder(r)
 }
 
+// EncodedString is a string encoded once, for the strings sent many times such as the SurrealQL of
+// prepared queries. It encodes as the string with the CborMarshaler.
+type EncodedString struct {
+	value   string
+	encoded []byte
+}
+
+// NewEncodedString encodes s.
+func NewEncodedString(s string) (*EncodedString, error) {
+	encoded, err := getCborEncoder().Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return &EncodedString{value: s, encoded: encoded}, nil
+}
+
+// String returns the string.
+func (s *EncodedString) String() string {
+	return s.value
+}
+
+func (s *EncodedString) MarshalCBOR() ([]byte, error) {
+	return s.encoded, nil
+}
+
+var (
+	cborModesOnce sync.Once
+	cborEncMode   cbor.EncMode
+	cborDecMode   cbor.DecMode
+)
+
+// getCborEncoder returns the encoding mode of the SurrealDB tags. The modes are built once, as
+// building them allocates more than encoding most values.
 func getCborEncoder() cbor.EncMode {
+	cborModesOnce.Do(buildCborModes)
+	return cborEncMode
+}
+
+func getCborDecoder() cbor.DecMode {
+	cborModesOnce.Do(buildCborModes)
+	return cborDecMode
+}
+
+func buildCborModes() {
 	tags := registerCborTags()
 	em, err := cbor.EncOptions{
-		Time:    cbor.TimeRFC3339,
+		// time.Time values keep their nanoseconds, like CustomDateTime
+		Time:    cbor.TimeRFC3339Nano,
 		TimeTag: cbor.EncTagRequired,
 	}.EncModeWithTags(tags)
 	if err != nil {
 		panic(err)
 	}
-
-	return em
-}
-
-func getCborDecoder() cbor.DecMode {
-	tags := registerCborTags()
 	dm, err := cbor.DecOptions{
 		TimeTagToAny: cbor.TimeTagToTime,
 	}.DecModeWithTags(tags)
@@ -129,5 +229,5 @@ func getCborDecoder() cbor.DecMode {
 		panic(err)
 	}
 
-	return dm
+	cborEncMode, cborDecMode = em, dm
 }
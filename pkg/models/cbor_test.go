@@ -194,7 +194,7 @@ func TestCustomDuration_String(t *testing.T) {
 
 func TestRecordID_String(t *testing.T) {
 	rid := RecordID{Table: "mytesttable", ID: "121212121"}
-	assert.Equal(t, "mytesttable:121212121", rid.String())
+	assert.Equal(t, "mytesttable:⟨121212121⟩", rid.String())
 }
 
 func TestFormatDurationAndParseDuration(t *testing.T) {
@@ -209,3 +209,169 @@ func TestFormatDuration(t *testing.T) {
 	d := FormatDuration(33333333333000000)
 	assert.Equal(t, "1y2w6d19h15m33s333ms", d)
 }
+
+func TestForFuture(t *testing.T) {
+	em := getCborEncoder()
+	dm := getCborDecoder()
+
+	f := NewFuture("time::now()")
+	encoded, err := em.Marshal(&f)
+	assert.Nil(t, err, "Should not encounter an error while encoding")
+
+	var decoded interface{}
+	err = dm.Unmarshal(encoded, &decoded)
+	assert.Nil(t, err, "Should not encounter an error while decoding")
+	assert.Equal(t, f, decoded)
+}
+
+func TestForComputed(t *testing.T) {
+	em := getCborEncoder()
+	dm := getCborDecoder()
+
+	type person struct {
+		Name string           `json:"name"`
+		Age  Computed[uint64] `json:"age"`
+	}
+
+	t.Run("computed value", func(t *testing.T) {
+		encoded, err := em.Marshal(map[string]interface{}{"name": "john", "age": 42})
+		assert.Nil(t, err, "Should not encounter an error while encoding")
+
+		var decoded person
+		err = dm.Unmarshal(encoded, &decoded)
+		assert.Nil(t, err, "Should not encounter an error while decoding")
+
+		age, ok := decoded.Age.Value()
+		assert.True(t, ok)
+		assert.Equal(t, uint64(42), age)
+		assert.Nil(t, decoded.Age.Future())
+	})
+
+	t.Run("unevaluated future", func(t *testing.T) {
+		f := NewFuture("time::year(time::now()) - born")
+		encoded, err := em.Marshal(map[string]interface{}{"name": "john", "age": &f})
+		assert.Nil(t, err, "Should not encounter an error while encoding")
+
+		var decoded person
+		err = dm.Unmarshal(encoded, &decoded)
+		assert.Nil(t, err, "Should not encounter an error while decoding")
+
+		_, ok := decoded.Age.Value()
+		assert.False(t, ok)
+		assert.Equal(t, f.String(), decoded.Age.Future().String())
+	})
+}
+
+func TestDeterministicMarshal(t *testing.T) {
+	m := CborMarshaler{Deterministic: true}
+	dm := getCborDecoder()
+
+	value := map[string]interface{}{
+		"zeta":  1.5,
+		"alpha": "a",
+		"mid":   []interface{}{1, 2.0, "three"},
+		"record": NewRecordID("person", map[string]interface{}{
+			"b": 2, "a": 1, "c": 3, "d": 4, "e": 5,
+		}),
+	}
+	for i := 0; i < 20; i++ {
+		value[fmt.Sprintf("key%d", i)] = i
+	}
+
+	first, err := m.Marshal(value)
+	assert.Nil(t, err, "Should not encounter an error while encoding")
+	for i := 0; i < 20; i++ {
+		again, err := m.Marshal(value)
+		assert.Nil(t, err, "Should not encounter an error while encoding")
+		assert.Equal(t, first, again, "encoding should be stable")
+	}
+
+	var decoded map[string]interface{}
+	err = dm.Unmarshal(first, &decoded)
+	assert.Nil(t, err, "Should not encounter an error while decoding")
+	assert.Equal(t, "a", decoded["alpha"])
+	assert.Equal(t, 1.5, decoded["zeta"])
+	assert.Equal(t, "person", decoded["record"].(RecordID).Table)
+
+	// 1.5 takes the shortest (half precision) form
+	assert.Contains(t, cborDiagnose(t, first), "1.5_1")
+}
+
+func cborDiagnose(t *testing.T, data []byte) string {
+	dm, err := cbor.DiagOptions{FloatPrecisionIndicator: true}.DiagMode()
+	assert.Nil(t, err)
+	diag, err := dm.Diagnose(data)
+	assert.Nil(t, err)
+	return diag
+}
+
+func TestCustomDateTime_Precision(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		paris = time.FixedZone("CET", 3600)
+	}
+	local := time.Date(2024, 10, 30, 13, 5, 0, 123456789, paris)
+
+	data, err := CborMarshaler{}.Marshal(map[string]interface{}{
+		"custom": CustomDateTime{local},
+		"time":   local,
+	})
+	assert.NoError(t, err)
+
+	var decoded struct {
+		Custom CustomDateTime `json:"custom"`
+		Time   CustomDateTime `json:"time"`
+	}
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+	assert.True(t, local.Equal(decoded.Custom.Time))
+	assert.True(t, local.Equal(decoded.Time.Time), "time.Time values must keep their nanoseconds")
+	assert.Equal(t, time.UTC, decoded.Custom.Location())
+	assert.Equal(t, "2024-10-30T12:05:00.123456789Z", decoded.Custom.String())
+	assert.Equal(t, "<datetime> '2024-10-30T12:05:00.123456789Z'", decoded.Custom.SurrealString())
+
+	// trailing zeros may be left out
+	compact, err := cbor.Marshal(cbor.Tag{Number: TagCustomDatetime, Content: []int64{1730289900}})
+	assert.NoError(t, err)
+	var dt CustomDateTime
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(compact, &dt))
+	assert.Equal(t, "2024-10-30T12:05:00Z", dt.String())
+}
+
+func TestCustomDuration_Precision(t *testing.T) {
+	d := NewDuration(300*24*time.Hour + time.Nanosecond)
+	data, err := CborMarshaler{}.Marshal(map[string]interface{}{"d": d})
+	assert.NoError(t, err)
+
+	var decoded struct {
+		D Duration `json:"d"`
+	}
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+	assert.Equal(t, d, decoded.D)
+	assert.Equal(t, "42w6d1ns", decoded.D.SurrealString())
+
+	for _, content := range []interface{}{[]int64{}, "0ns"} {
+		tag := TagCustomDuration
+		if _, ok := content.(string); ok {
+			tag = TagStringDuration
+		}
+		data, err := cbor.Marshal(cbor.Tag{Number: tag, Content: content})
+		assert.NoError(t, err)
+		decoded.D = NewDuration(time.Hour)
+		assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded.D))
+		assert.Equal(t, NewDuration(0), decoded.D)
+		assert.Equal(t, "0ns", decoded.D.String())
+	}
+}
+
+func TestEncodedString(t *testing.T) {
+	sql := "SELECT * FROM user WHERE age > $min"
+	s, err := NewEncodedString(sql)
+	assert.Nil(t, err)
+	assert.Equal(t, sql, s.String())
+
+	encoded, err := CborMarshaler{}.Marshal([]interface{}{s, map[string]interface{}{"min": 18}})
+	assert.Nil(t, err)
+	expected, err := CborMarshaler{}.Marshal([]interface{}{sql, map[string]interface{}{"min": 18}})
+	assert.Nil(t, err)
+	assert.Equal(t, expected, encoded)
+}
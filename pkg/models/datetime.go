@@ -27,28 +27,59 @@ func (d *CustomDateTime) MarshalCBOR() ([]byte, error) {
 	})
 }
 
+// UnmarshalCBOR decodes a datetime, from its seconds and nanoseconds, either of which is left out
+// when zero, or from a standard CBOR time. The time is in UTC, like in the database.
 func (d *CustomDateTime) UnmarshalCBOR(data []byte) error {
 	dec := getCborDecoder()
 
-	var temp [2]int64
-	err := dec.Unmarshal(data, &temp)
-	if err != nil {
+	var tag cbor.RawTag
+	if err := dec.Unmarshal(data, &tag); err != nil {
 		return err
 	}
+	if tag.Number != TagCustomDatetime {
+		var t time.Time
+		if err := dec.Unmarshal(data, &t); err != nil {
+			return err
+		}
+		*d = CustomDateTime{t.UTC()}
+		return nil
+	}
 
-	s := temp[0]
-	ns := temp[1]
+	s, ns, err := decodeSecondsAndNanos(tag.Content)
+	if err != nil {
+		return err
+	}
 
-	*d = CustomDateTime{time.Unix(s, ns)}
+	*d = CustomDateTime{time.Unix(s, ns).UTC()}
 
 	return nil
 }
 
+// String returns the datetime in UTC in the RFC 3339 format, with the nanoseconds when not zero.
 func (d *CustomDateTime) String() string {
-	layout := "2006-01-02T15:04:05Z"
-	return d.Format(layout)
+	return d.UTC().Format(time.RFC3339Nano)
 }
 
+// SurrealString returns the datetime as a SurrealQL literal, in UTC and to the nanosecond.
 func (d *CustomDateTime) SurrealString() string {
 	return fmt.Sprintf("<datetime> '%s'", d.String())
 }
+
+// decodeSecondsAndNanos decodes the [seconds, nanoseconds] content of a datetime or a duration,
+// of which trailing zeros may be left out.
+func decodeSecondsAndNanos(content []byte) (s, ns int64, err error) {
+	var temp []int64
+	if err := getCborDecoder().Unmarshal(content, &temp); err != nil {
+		return 0, 0, err
+	}
+	if len(temp) > 2 {
+		return 0, 0, fmt.Errorf("expected at most 2 numbers, got %d", len(temp))
+	}
+	if len(temp) > 0 {
+		s = temp[0]
+	}
+	if len(temp) > 1 {
+		ns = temp[1]
+	}
+	return s, ns, nil
+}
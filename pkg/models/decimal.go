@@ -0,0 +1,252 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Decimal is an arbitrary precision decimal number, the decimal type of SurrealDB. Its value is
+// Coefficient * 10^Exponent. The zero value is 0.
+//
+// Decimals are immutable: the arithmetic methods return new values. A Decimal converts to
+// shopspring/decimal without loss with decimal.NewFromBigInt(d.Coefficient(), d.Exponent()).
+type Decimal struct {
+	coefficient *big.Int
+	exponent    int32
+}
+
+// NewDecimal returns the decimal coefficient * 10^exponent.
+func NewDecimal(coefficient int64, exponent int32) Decimal {
+	return Decimal{coefficient: big.NewInt(coefficient), exponent: exponent}
+}
+
+// NewDecimalFromBigInt returns the decimal coefficient * 10^exponent.
+func NewDecimalFromBigInt(coefficient *big.Int, exponent int32) Decimal {
+	return Decimal{coefficient: new(big.Int).Set(coefficient), exponent: exponent}
+}
+
+// NewDecimalFromRat returns r rounded half away from zero to the given number of digits after the
+// decimal point.
+func NewDecimalFromRat(r *big.Rat, digits int32) Decimal {
+	scaled := new(big.Int).Mul(r.Num(), pow10(digits))
+	quo, rem := new(big.Int).QuoRem(scaled, r.Denom(), new(big.Int))
+	if rem.Sign() != 0 && new(big.Int).Mul(rem.Abs(rem), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(r.Sign())))
+	}
+	return Decimal{coefficient: quo, exponent: -digits}
+}
+
+// ParseDecimal parses a decimal written like 12, -1.05 or 1.5e-3.
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		if exponent, err = strconv.ParseInt(s[i+1:], 10, 32); err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+		mantissa = s[:i]
+	}
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		exponent -= int64(len(mantissa) - i - 1)
+		mantissa = mantissa[:i] + mantissa[i+1:]
+	}
+	if exponent < math.MinInt32 || exponent > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("decimal %q is out of range", s)
+	}
+
+	digits := strings.TrimLeft(mantissa, "+-")
+	if digits == "" || strings.Trim(digits, "0123456789") != "" || len(mantissa)-len(digits) > 1 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	coefficient, _ := new(big.Int).SetString(mantissa, 10)
+	return Decimal{coefficient: coefficient, exponent: int32(exponent)}, nil
+}
+
+// Coefficient returns the coefficient of the decimal.
+func (d Decimal) Coefficient() *big.Int {
+	if d.coefficient == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(d.coefficient)
+}
+
+// Exponent returns the power of ten the coefficient is multiplied by.
+func (d Decimal) Exponent() int32 {
+	return d.exponent
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the decimal.
+func (d Decimal) Sign() int {
+	if d.coefficient == nil {
+		return 0
+	}
+	return d.coefficient.Sign()
+}
+
+// IsZero reports whether the decimal is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, exponent := align(d, other)
+	return Decimal{coefficient: a.Add(a, b), exponent: exponent}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, exponent := align(d, other)
+	return Decimal{coefficient: a.Sub(a, b), exponent: exponent}
+}
+
+// Mul returns d * other.
+func (d Decimal) Mul(other Decimal) Decimal {
+	coefficient := d.Coefficient()
+	return Decimal{coefficient: coefficient.Mul(coefficient, other.Coefficient()), exponent: d.exponent + other.exponent}
+}
+
+// Div returns d / other rounded half away from zero to the given number of digits after the
+// decimal point. It panics when other is 0.
+func (d Decimal) Div(other Decimal, digits int32) Decimal {
+	return NewDecimalFromRat(new(big.Rat).Quo(d.Rat(), other.Rat()), digits)
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	coefficient := d.Coefficient()
+	return Decimal{coefficient: coefficient.Neg(coefficient), exponent: d.exponent}
+}
+
+// Cmp compares two decimals and returns -1, 0 or 1, whatever their exponents.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Equal reports whether two decimals have the same value, so that 1.50 equals 1.5.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Rat returns the decimal as a rational number, which is exact.
+func (d Decimal) Rat() *big.Rat {
+	r := new(big.Rat).SetInt(d.Coefficient())
+	if d.exponent >= 0 {
+		return r.Mul(r, new(big.Rat).SetInt(pow10(d.exponent)))
+	}
+	return r.Quo(r, new(big.Rat).SetInt(pow10(-d.exponent)))
+}
+
+// Float64 returns the float64 nearest to the decimal, and whether it is exact.
+func (d Decimal) Float64() (float64, bool) {
+	return d.Rat().Float64()
+}
+
+// Int64 returns the integer part of the decimal, and whether it fits in an int64 without loss.
+func (d Decimal) Int64() (int64, bool) {
+	r := d.Rat()
+	i := new(big.Int).Quo(r.Num(), r.Denom())
+	return i.Int64(), i.IsInt64() && r.IsInt()
+}
+
+// String formats the decimal without exponent, such as -1.050.
+func (d Decimal) String() string {
+	coefficient := d.Coefficient()
+	if d.exponent >= 0 {
+		return coefficient.Mul(coefficient, pow10(d.exponent)).String()
+	}
+
+	digits := coefficient.String()
+	sign := ""
+	if digits[0] == '-' {
+		sign, digits = "-", digits[1:]
+	}
+	scale := int(-d.exponent)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// MarshalCBOR encodes the decimal as a string decimal, which SurrealDB stores without loss.
+func (d Decimal) MarshalCBOR() ([]byte, error) {
+	return getCborEncoder().Marshal(cbor.Tag{
+		Number:  TagStringDecimal,
+		Content: d.String(),
+	})
+}
+
+// UnmarshalCBOR decodes a string decimal, or a number when the field was not defined as a decimal.
+func (d *Decimal) UnmarshalCBOR(data []byte) error {
+	var v interface{}
+	if err := getCborDecoder().Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch value := v.(type) {
+	case DecimalString:
+		return d.parse(string(value))
+	case string:
+		return d.parse(value)
+	case uint64:
+		*d = Decimal{coefficient: new(big.Int).SetUint64(value)}
+	case int64:
+		*d = NewDecimal(value, 0)
+	case big.Int:
+		*d = NewDecimalFromBigInt(&value, 0)
+	case float64:
+		return d.parse(strconv.FormatFloat(value, 'g', -1, 64))
+	default:
+		return fmt.Errorf("cannot decode %T into a decimal", v)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the decimal as a JSON string, as JSON numbers are float64 to most decoders.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a decimal from a JSON string or number.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	return d.parse(s)
+}
+
+func (d *Decimal) parse(s string) error {
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// align returns the coefficients of a and b scaled to their smallest exponent.
+func align(a, b Decimal) (ca, cb *big.Int, exponent int32) {
+	ca, cb = a.Coefficient(), b.Coefficient()
+	switch {
+	case a.exponent > b.exponent:
+		ca.Mul(ca, pow10(a.exponent-b.exponent))
+		return ca, cb, b.exponent
+	case b.exponent > a.exponent:
+		cb.Mul(cb, pow10(b.exponent-a.exponent))
+		return ca, cb, a.exponent
+	default:
+		return ca, cb, a.exponent
+	}
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
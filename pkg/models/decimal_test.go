@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimal(t *testing.T) {
+	for s, expected := range map[string]string{
+		"12":      "12",
+		"-1.050":  "-1.050",
+		"0.001":   "0.001",
+		"-0.5":    "-0.5",
+		"1.5e-3":  "0.0015",
+		"25e2":    "2500",
+		"+3.14":   "3.14",
+		"1234.50": "1234.50",
+	} {
+		d, err := ParseDecimal(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, d.String(), s)
+	}
+	for _, s := range []string{"", "-", "1.2.3", "1e", "abc", "--1", "1e99999999999"} {
+		_, err := ParseDecimal(s)
+		assert.Error(t, err, s)
+	}
+
+	a, b := NewDecimal(110, -2), NewDecimal(3, 0)
+	assert.Equal(t, "4.10", a.Add(b).String())
+	assert.Equal(t, "-1.90", a.Sub(b).String())
+	assert.Equal(t, "3.30", a.Mul(b).String())
+	assert.Equal(t, "0.367", a.Div(b, 3).String())
+	assert.Equal(t, "-0.37", a.Neg().Div(b, 2).String())
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.True(t, NewDecimal(15, -1).Equal(NewDecimal(150, -2)))
+	assert.True(t, Decimal{}.IsZero())
+	assert.Equal(t, "0", Decimal{}.String())
+
+	assert.Equal(t, big.NewRat(11, 10), a.Rat())
+	f, _ := a.Float64()
+	assert.Equal(t, 1.1, f)
+	i, exact := NewDecimal(25, 1).Int64()
+	assert.Equal(t, int64(250), i)
+	assert.True(t, exact)
+	_, exact = a.Int64()
+	assert.False(t, exact)
+}
+
+func TestDecimalEncoding(t *testing.T) {
+	type product struct {
+		Price Decimal `json:"price"`
+	}
+	d, err := ParseDecimal("19.990000000000000000001")
+	assert.NoError(t, err)
+
+	data, err := CborMarshaler{}.Marshal(product{Price: d})
+	assert.NoError(t, err)
+	var raw map[string]cbor.Tag
+	assert.NoError(t, cbor.Unmarshal(data, &raw))
+	assert.Equal(t, cbor.Tag{Number: TagStringDecimal, Content: "19.990000000000000000001"}, raw["price"])
+
+	var decoded product
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+	assert.Equal(t, d, decoded.Price)
+
+	// fields not defined as decimals hold numbers
+	data, err = CborMarshaler{}.Marshal(map[string]interface{}{"price": 12})
+	assert.NoError(t, err)
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+	assert.Equal(t, "12", decoded.Price.String())
+
+	data, err = json.Marshal(product{Price: d})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"price":"19.990000000000000000001"}`, string(data))
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":1.25}`), &decoded))
+	assert.Equal(t, "1.25", decoded.Price.String())
+}
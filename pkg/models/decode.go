@@ -0,0 +1,286 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/internal/codec"
+)
+
+// DecodeOptions selects how NONE and NULL values are decoded by a CborUnmarshaler.
+//
+// By default, NONE decodes into an interface{} as models.None and leaves a pointer as it was,
+// while NULL decodes as nil into interfaces, pointers, slices and maps. Both leave strings,
+// numbers, booleans and structs as they were. A field holding NONE is left out of the records
+// returned by SELECT *, and is then left as it was too, whereas it is returned as NONE when
+// selected explicitly.
+//
+// Setting every option makes NONE, NULL and missing fields decode the same way, as the zero value
+// of their destination.
+type DecodeOptions struct {
+	// NoneAsNil decodes NONE as nil into interfaces, pointers, slices and maps, and sets those of
+	// the struct fields missing from the data to nil.
+	NoneAsNil bool
+	// NullAsNil decodes NULL as the zero value of its destination, including strings, numbers,
+	// booleans and structs.
+	NullAsNil bool
+	// ZeroForNone decodes NONE as the zero value of strings, numbers, booleans and structs, and
+	// sets those of the struct fields missing from the data to their zero value.
+	ZeroForNone bool
+}
+
+func (o DecodeOptions) isZero() bool {
+	return o == DecodeOptions{}
+}
+
+// decodeFieldByField decodes data into dst, walking dst rather than leaving it to the CBOR library
+// when it holds structs with surreal tags, or when opts are set. It returns false when the CBOR
+// library can decode dst by itself.
+func decodeFieldByField(data []byte, dst interface{}, opts DecodeOptions) (bool, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || !walkDecoding(v.Type(), opts) {
+		return false, nil
+	}
+	d := &fieldDecoder{opts: opts}
+	return true, d.decode(data, v.Elem())
+}
+
+// walkDecoding reports whether values of type t must be decoded by a fieldDecoder.
+func walkDecoding(t reflect.Type, opts DecodeOptions) bool {
+	if opts.isZero() {
+		return tagTypes.uses(t)
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Interface:
+		return !t.Implements(cborUnmarshalerType) && !reflect.PointerTo(t).Implements(cborUnmarshalerType)
+	default:
+		return opts.NullAsNil || opts.ZeroForNone
+	}
+}
+
+// DecodePlan decodes data into values of one type like a CborUnmarshaler, having worked out once
+// whether they must be decoded field by field, for the types decoded many times such as the
+// results of prepared queries.
+type DecodePlan struct {
+	t            reflect.Type
+	opts         DecodeOptions
+	fieldByField bool
+}
+
+// Plan returns the plan decoding into values of type t with the options of c.
+func (c CborUnmarshaler) Plan(t reflect.Type) *DecodePlan {
+	return &DecodePlan{t: t, opts: c.Options, fieldByField: walkDecoding(reflect.PointerTo(t), c.Options)}
+}
+
+// Unmarshal decodes data into dst, which must be a pointer to the type of the plan, or is decoded
+// by a CborUnmarshaler otherwise.
+func (p *DecodePlan) Unmarshal(data []byte, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Type().Elem() != p.t {
+		return CborUnmarshaler{Options: p.opts}.Unmarshal(data, dst)
+	}
+	if p.fieldByField {
+		d := &fieldDecoder{opts: p.opts}
+		return d.decode(data, v.Elem())
+	}
+	if err := getCborDecoder().Unmarshal(data, dst); err != nil {
+		return err
+	}
+	replacerAfterDecode(&dst)
+	return nil
+}
+
+func (p *DecodePlan) NewDecoder(r io.Reader) codec.Decoder {
+	return CborUnmarshaler{Options: p.opts}.NewDecoder(r)
+}
+
+type fieldDecoder struct {
+	opts DecodeOptions
+}
+
+func (d *fieldDecoder) decode(data []byte, v reflect.Value) error {
+	if len(data) > 0 && (isNone(data) || data[0] == 0xf6 || data[0] == 0xf7) {
+		if d.zeroes(isNone(data), v.Kind()) {
+			v.SetZero()
+			return nil
+		}
+	}
+
+	if !walkDecoding(v.Type(), d.opts) {
+		return getCborDecoder().Unmarshal(data, v.Addr().Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if err := getCborDecoder().Unmarshal(data, v.Addr().Interface()); err != nil {
+			return err
+		}
+		if d.opts.NoneAsNil && !v.IsNil() {
+			v.Set(reflect.ValueOf(removeNone(v.Elem().Interface())))
+		}
+		return nil
+	case reflect.Pointer:
+		if isNone(data) {
+			// left as it was, as by the CBOR library, since NoneAsNil is not set
+			return getCborDecoder().Unmarshal(data, v.Addr().Interface())
+		}
+		if isNullOrNone(data) {
+			v.SetZero()
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(data, v.Elem())
+	case reflect.Slice:
+		var items []cbor.RawMessage
+		if err := getCborDecoder().Unmarshal(data, &items); err != nil {
+			return err
+		}
+		if items == nil {
+			v.SetZero()
+			return nil
+		}
+		v.Set(reflect.MakeSlice(v.Type(), len(items), len(items)))
+		for i, item := range items {
+			if err := d.decode(item, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Array:
+		var items []cbor.RawMessage
+		if err := getCborDecoder().Unmarshal(data, &items); err != nil {
+			return err
+		}
+		for i := 0; i < len(items) && i < v.Len(); i++ {
+			if err := d.decode(items[i], v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		items := reflect.New(reflect.MapOf(v.Type().Key(), reflect.TypeOf(cbor.RawMessage(nil))))
+		if err := getCborDecoder().Unmarshal(data, items.Interface()); err != nil {
+			return err
+		}
+		if items.Elem().IsNil() {
+			v.SetZero()
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), items.Elem().Len()))
+		}
+		iter := items.Elem().MapRange()
+		for iter.Next() {
+			item := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(iter.Value().Bytes(), item); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), item)
+		}
+		return nil
+	case reflect.Struct:
+		return d.decodeStruct(data, v)
+	default:
+		return getCborDecoder().Unmarshal(data, v.Addr().Interface())
+	}
+}
+
+// zeroes reports whether NONE, or NULL when none is false, decodes as the zero value of a
+// destination of kind k.
+func (d *fieldDecoder) zeroes(none bool, k reflect.Kind) bool {
+	if !none {
+		return d.opts.NullAsNil
+	}
+	switch k {
+	case reflect.Interface, reflect.Pointer, reflect.Slice, reflect.Map:
+		return d.opts.NoneAsNil
+	default:
+		return d.opts.ZeroForNone
+	}
+}
+
+func (d *fieldDecoder) decodeStruct(data []byte, v reflect.Value) error {
+	if isNullOrNone(data) {
+		return nil
+	}
+	var fields map[string]cbor.RawMessage
+	if err := getCborDecoder().Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for _, f := range getStructInfo(v.Type()).fields {
+		field := v.FieldByIndex(f.index)
+		raw, ok := fields[f.name]
+		if !ok {
+			for name, value := range fields {
+				if strings.EqualFold(name, f.name) {
+					raw, ok = value, true
+					break
+				}
+			}
+		}
+		if !ok {
+			// a missing field is a NONE field
+			if d.zeroes(true, field.Kind()) {
+				field.SetZero()
+			}
+			continue
+		}
+
+		if f.record {
+			if isNullOrNone(raw) {
+				field.SetString("")
+				continue
+			}
+			var id RecordID
+			if err := getCborDecoder().Unmarshal(raw, &id); err != nil {
+				return fmt.Errorf("field %s of %s: %w", f.name, v.Type(), err)
+			}
+			field.SetString(id.String())
+			continue
+		}
+		if err := d.decode(raw, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeNone returns v with the None values it holds replaced by nil.
+func removeNone(v interface{}) interface{} {
+	switch value := v.(type) {
+	case CustomNil:
+		return nil
+	case []interface{}:
+		for i, item := range value {
+			value[i] = removeNone(item)
+		}
+	case map[interface{}]interface{}:
+		for key, item := range value {
+			value[key] = removeNone(item)
+		}
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = removeNone(item)
+		}
+	}
+	return v
+}
+
+func isNone(data []byte) bool {
+	return len(data) > 0 && data[0] == 0xc0|byte(TagNone)
+}
+
+// isNullOrNone reports whether data encodes NULL or NONE.
+func isNullOrNone(data []byte) bool {
+	if len(data) == 0 {
+		return true
+	}
+	// null, undefined, or the NONE tag
+	return data[0] == 0xf6 || data[0] == 0xf7 || isNone(data)
+}
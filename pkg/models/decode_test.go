@@ -0,0 +1,100 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type nullableRecord struct {
+	Name  string                 `json:"name"`
+	Age   int                    `json:"age"`
+	Nick  *string                `json:"nick"`
+	Tags  []string               `json:"tags"`
+	Extra interface{}            `json:"extra"`
+	Attrs map[string]interface{} `json:"attrs"`
+}
+
+func TestDecodeOptions(t *testing.T) {
+	none := cbor.RawMessage{0xc6, 0xf6}
+	null := cbor.RawMessage{0xf6}
+	record := func(value cbor.RawMessage) []byte {
+		data, err := CborMarshaler{}.Marshal(map[string]interface{}{
+			"name": value, "age": value, "nick": value, "tags": value, "extra": value,
+			"attrs": map[string]interface{}{"a": value},
+		})
+		assert.NoError(t, err)
+		return data
+	}
+	nick := "nick"
+	filled := func() nullableRecord {
+		return nullableRecord{Name: "name", Age: 1, Nick: &nick, Tags: []string{"a"}, Extra: 1}
+	}
+
+	t.Run("default", func(t *testing.T) {
+		r := filled()
+		assert.NoError(t, CborUnmarshaler{}.Unmarshal(record(none), &r))
+		assert.Equal(t, "name", r.Name)
+		assert.Equal(t, None, r.Extra)
+		assert.Equal(t, None, r.Attrs["a"])
+	})
+
+	t.Run("unified", func(t *testing.T) {
+		unmarshaler := CborUnmarshaler{Options: DecodeOptions{NoneAsNil: true, NullAsNil: true, ZeroForNone: true}}
+		want := nullableRecord{Attrs: map[string]interface{}{"a": nil}}
+		for _, data := range [][]byte{record(none), record(null)} {
+			r := filled()
+			assert.NoError(t, unmarshaler.Unmarshal(data, &r))
+			assert.Equal(t, want, r)
+		}
+
+		// fields missing from the record, as returned by SELECT * for NONE fields
+		missing, err := CborMarshaler{}.Marshal(map[string]interface{}{"attrs": map[string]interface{}{"a": nil}})
+		assert.NoError(t, err)
+		r := filled()
+		assert.NoError(t, unmarshaler.Unmarshal(missing, &r))
+		assert.Equal(t, want, r)
+	})
+
+	t.Run("none as nil only", func(t *testing.T) {
+		r := filled()
+		unmarshaler := CborUnmarshaler{Options: DecodeOptions{NoneAsNil: true}}
+		assert.NoError(t, unmarshaler.Unmarshal(record(none), &r))
+		assert.Equal(t, "name", r.Name)
+		assert.Equal(t, 1, r.Age)
+		assert.Nil(t, r.Nick)
+		assert.Nil(t, r.Tags)
+		assert.Nil(t, r.Extra)
+		assert.Nil(t, r.Attrs["a"])
+	})
+
+	t.Run("interface destination", func(t *testing.T) {
+		var v interface{}
+		unmarshaler := CborUnmarshaler{Options: DecodeOptions{NoneAsNil: true}}
+		assert.NoError(t, unmarshaler.Unmarshal(record(none), &v))
+		assert.Nil(t, v.(map[interface{}]interface{})["name"])
+	})
+}
+
+func TestDecodePlan(t *testing.T) {
+	none := cbor.RawMessage{0xc6, 0xf6}
+	data, err := CborMarshaler{}.Marshal(map[string]interface{}{"name": "name", "nick": none})
+	assert.NoError(t, err)
+
+	for _, unmarshaler := range []CborUnmarshaler{{}, {Options: DecodeOptions{NoneAsNil: true}}} {
+		plan := unmarshaler.Plan(reflect.TypeOf(nullableRecord{}))
+
+		nick := "nick"
+		planned, unplanned := nullableRecord{Nick: &nick}, nullableRecord{Nick: &nick}
+		assert.NoError(t, plan.Unmarshal(data, &planned))
+		assert.NoError(t, unmarshaler.Unmarshal(data, &unplanned))
+		assert.Equal(t, unplanned, planned, "%+v", unmarshaler.Options)
+
+		// other types are decoded without the plan
+		var v map[string]interface{}
+		assert.NoError(t, plan.Unmarshal(data, &v))
+		assert.Equal(t, "name", v["name"])
+	}
+}
@@ -27,6 +27,15 @@ type CustomDuration struct {
 	time.Duration
 }
 
+// Duration is a SurrealDB duration, which encodes with the native duration tag and keeps its
+// nanoseconds.
+type Duration = CustomDuration
+
+// NewDuration returns d as a SurrealDB duration.
+func NewDuration(d time.Duration) Duration {
+	return Duration{d}
+}
+
 func (d *CustomDuration) MarshalCBOR() ([]byte, error) {
 	enc := getCborEncoder()
 
@@ -40,19 +49,34 @@ func (d *CustomDuration) MarshalCBOR() ([]byte, error) {
 	})
 }
 
+// UnmarshalCBOR decodes a duration, from its seconds and nanoseconds, either of which is left out
+// when zero, or from its string form.
 func (d *CustomDuration) UnmarshalCBOR(data []byte) error {
 	dec := getCborDecoder()
 
-	var temp [2]interface{}
-	err := dec.Unmarshal(data, &temp)
-	if err != nil {
+	var tag cbor.RawTag
+	if err := dec.Unmarshal(data, &tag); err != nil {
 		return err
 	}
+	if tag.Number == TagStringDuration {
+		var s string
+		if err := dec.Unmarshal(tag.Content, &s); err != nil {
+			return err
+		}
+		ns, err := ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = CustomDuration{time.Duration(ns)}
+		return nil
+	}
 
-	s := temp[0].(int64)
-	ns := temp[1].(int64)
+	s, ns, err := decodeSecondsAndNanos(tag.Content)
+	if err != nil {
+		return err
+	}
 
-	*d = CustomDuration{time.Duration((float64(s) * constants.OneSecondToNanoSecond) + float64(ns))}
+	*d = CustomDuration{time.Duration(s*constants.OneSecondToNanoSecond + ns)}
 
 	return nil
 }
@@ -61,6 +85,11 @@ func (d *CustomDuration) String() string {
 	return FormatDuration(d.Nanoseconds())
 }
 
+// SurrealString returns the duration as a SurrealQL literal, such as 1h30m.
+func (d *CustomDuration) SurrealString() string {
+	return d.String()
+}
+
 func (d *CustomDuration) ToCustomDurationString() CustomDurationString {
 	return CustomDurationString(d.String())
 }
@@ -89,6 +118,10 @@ func (d *CustomDurationString) ToCustomDuration() CustomDuration {
 //------------------------------------------------------------------------------------------------------------------------------//
 
 func FormatDuration(ns int64) string {
+	if ns == 0 {
+		return "0ns"
+	}
+
 	years := ns / nsPerYear
 	ns %= nsPerYear
 
@@ -1,11 +1,22 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
 
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Future is a SurrealDB <future> value: a block of SurrealQL computed each time the field holding
+// it is read.
 type Future struct {
 	inner string
 }
 
+// NewFuture returns a future computing the given SurrealQL block, e.g. NewFuture("time::now()").
+func NewFuture(block string) Future {
+	return Future{inner: block}
+}
+
 func (f *Future) String() string {
 	return f.inner
 }
@@ -13,3 +24,73 @@ func (f *Future) String() string {
 func (f *Future) SurrealString() string {
 	return fmt.Sprintf("<future> { %s }", f.String())
 }
+
+func (f *Future) MarshalCBOR() ([]byte, error) {
+	enc := getCborEncoder()
+
+	return enc.Marshal(cbor.Tag{
+		Number:  TagFuture,
+		Content: f.inner,
+	})
+}
+
+func (f *Future) UnmarshalCBOR(data []byte) error {
+	dec := getCborDecoder()
+
+	var block string
+	if err := dec.Unmarshal(data, &block); err != nil {
+		return err
+	}
+
+	f.inner = block
+	return nil
+}
+
+//------------------------------------------------------------------------------------------------//
+
+// Computed holds a field that SurrealDB may return either as its computed value or, when the
+// server did not evaluate it, as the Future that defines it. Decoding a future into a plain T
+// field fails, so fields backed by <future> should use Computed[T] instead.
+type Computed[T any] struct {
+	value  T
+	future *Future
+}
+
+// Value returns the computed value. The second return value is false when the server returned
+// the future itself rather than its result.
+func (c *Computed[T]) Value() (T, bool) {
+	return c.value, c.future == nil
+}
+
+// Future returns the future returned by the server, or nil when the value was computed.
+func (c *Computed[T]) Future() *Future {
+	return c.future
+}
+
+func (c *Computed[T]) MarshalCBOR() ([]byte, error) {
+	if c.future != nil {
+		return c.future.MarshalCBOR()
+	}
+	return getCborEncoder().Marshal(c.value)
+}
+
+func (c *Computed[T]) UnmarshalCBOR(data []byte) error {
+	dec := getCborDecoder()
+
+	var tag cbor.RawTag
+	if err := dec.Unmarshal(data, &tag); err == nil && tag.Number == TagFuture {
+		var f Future
+		if err := f.UnmarshalCBOR(tag.Content); err != nil {
+			return err
+		}
+		*c = Computed[T]{future: &f}
+		return nil
+	}
+
+	var value T
+	if err := dec.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*c = Computed[T]{value: value}
+	return nil
+}
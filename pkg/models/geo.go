@@ -0,0 +1,101 @@
+package models
+
+import "math"
+
+// EarthRadius is the mean radius of the Earth in meters, as used by the geo functions of SurrealDB.
+const EarthRadius = 6371008.8
+
+// DistanceTo returns the great-circle distance in meters between two points, computed with the
+// Haversine formula like geo::distance.
+func (gp *GeometryPoint) DistanceTo(other GeometryPoint) float64 {
+	lat1, lat2 := radians(gp.Latitude), radians(other.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(other.Longitude - gp.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox is the area between two parallels and two meridians.
+type BoundingBox struct {
+	// Min holds the lowest latitude and longitude of the box, and Max the highest. A box crossing
+	// the antimeridian has a Min.Longitude greater than its Max.Longitude.
+	Min GeometryPoint
+	Max GeometryPoint
+}
+
+// NewBoundingBox returns the smallest box holding every point within radius meters of center. It
+// is meant as a cheap prefilter of proximity queries, the points of its corners being farther than
+// radius from center.
+func NewBoundingBox(center GeometryPoint, radius float64) BoundingBox {
+	dLat := degrees(radius / EarthRadius)
+	minLat, maxLat := center.Latitude-dLat, center.Latitude+dLat
+	if minLat <= -90 || maxLat >= 90 {
+		// the circle holds a pole, and so every longitude
+		return BoundingBox{
+			Min: GeometryPoint{Latitude: math.Max(minLat, -90), Longitude: -180},
+			Max: GeometryPoint{Latitude: math.Min(maxLat, 90), Longitude: 180},
+		}
+	}
+
+	dLon := degrees(math.Asin(math.Min(1, math.Sin(radius/EarthRadius)/math.Cos(radians(center.Latitude)))))
+	return BoundingBox{
+		Min: GeometryPoint{Latitude: minLat, Longitude: wrapLongitude(center.Longitude - dLon)},
+		Max: GeometryPoint{Latitude: maxLat, Longitude: wrapLongitude(center.Longitude + dLon)},
+	}
+}
+
+// Contains reports whether p lies in the box, borders included.
+func (b BoundingBox) Contains(p GeometryPoint) bool {
+	if p.Latitude < b.Min.Latitude || p.Latitude > b.Max.Latitude {
+		return false
+	}
+	if b.Min.Longitude <= b.Max.Longitude {
+		return p.Longitude >= b.Min.Longitude && p.Longitude <= b.Max.Longitude
+	}
+	return p.Longitude >= b.Min.Longitude || p.Longitude <= b.Max.Longitude
+}
+
+// Polygon returns the box as a polygon, to be used with the INSIDE operator.
+func (b BoundingBox) Polygon() GeometryPolygon {
+	return GeometryPolygon{GeometryLine{
+		{Latitude: b.Min.Latitude, Longitude: b.Min.Longitude},
+		{Latitude: b.Min.Latitude, Longitude: b.Max.Longitude},
+		{Latitude: b.Max.Latitude, Longitude: b.Max.Longitude},
+		{Latitude: b.Max.Latitude, Longitude: b.Min.Longitude},
+		{Latitude: b.Min.Latitude, Longitude: b.Min.Longitude},
+	}}
+}
+
+// MarshalCBOR encodes the box as its polygon.
+func (b BoundingBox) MarshalCBOR() ([]byte, error) {
+	return getCborEncoder().Marshal(b.Polygon())
+}
+
+// Area is a geometry enclosing an area, which other geometries can be inside of.
+type Area interface {
+	isArea()
+}
+
+func (GeometryPolygon) isArea()      {}
+func (GeometryMultiPolygon) isArea() {}
+func (BoundingBox) isArea()          {}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+func wrapLongitude(lon float64) float64 {
+	switch {
+	case lon > 180:
+		return lon - 360
+	case lon < -180:
+		return lon + 360
+	default:
+		return lon
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceTo(t *testing.T) {
+	london := NewGeometryPoint(51.5074, -0.1278)
+	paris := NewGeometryPoint(48.8566, 2.3522)
+
+	assert.InDelta(t, 343_560, london.DistanceTo(paris), 500)
+	assert.InDelta(t, london.DistanceTo(paris), paris.DistanceTo(london), 1e-6)
+	assert.Zero(t, london.DistanceTo(london))
+}
+
+func TestBoundingBox(t *testing.T) {
+	london := NewGeometryPoint(51.5074, -0.1278)
+	box := NewBoundingBox(london, 10_000)
+
+	assert.True(t, box.Contains(london))
+	assert.True(t, box.Contains(NewGeometryPoint(51.55, -0.2)))
+	assert.False(t, box.Contains(NewGeometryPoint(48.8566, 2.3522)))
+
+	// every point within the radius is in the box
+	for _, p := range []GeometryPoint{{Latitude: 51.5974, Longitude: -0.1278}, {Latitude: 51.5074, Longitude: -0.27}} {
+		if london.DistanceTo(p) <= 10_000 {
+			assert.True(t, box.Contains(p))
+		}
+	}
+
+	polygon := box.Polygon()
+	assert.Len(t, polygon[0], 5)
+	assert.Equal(t, polygon[0][0], polygon[0][4])
+
+	// a box crossing the antimeridian
+	fiji := NewBoundingBox(NewGeometryPoint(-17.7, 179.9), 50_000)
+	assert.Greater(t, fiji.Min.Longitude, fiji.Max.Longitude)
+	assert.True(t, fiji.Contains(NewGeometryPoint(-17.7, -179.9)))
+	assert.False(t, fiji.Contains(NewGeometryPoint(-17.7, 0)))
+
+	// a box holding a pole
+	pole := NewBoundingBox(NewGeometryPoint(89.9, 0), 50_000)
+	assert.True(t, pole.Contains(NewGeometryPoint(89.9, 180)))
+}
+
+func TestBoundingBoxEncoding(t *testing.T) {
+	box := NewBoundingBox(NewGeometryPoint(51.5074, -0.1278), 1000)
+
+	encoded, err := CborMarshaler{}.Marshal(map[string]interface{}{"area": box})
+	assert.NoError(t, err)
+
+	var decoded map[string]GeometryPolygon
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(encoded, &decoded))
+	assert.Equal(t, box.Polygon(), decoded["area"])
+}
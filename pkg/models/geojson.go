@@ -0,0 +1,413 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Geometry is implemented by the geometry types of SurrealDB.
+type Geometry interface {
+	// Validate reports whether the geometry is well-formed: coordinates within their range, lines
+	// of at least two points and polygons made of closed rings.
+	Validate() error
+	// GeoJSONType returns the type of the geometry in GeoJSON, such as Point or MultiPolygon.
+	GeoJSONType() string
+}
+
+var (
+	_ Geometry = GeometryPoint{}
+	_ Geometry = GeometryLine{}
+	_ Geometry = GeometryPolygon{}
+	_ Geometry = GeometryMultiPoint{}
+	_ Geometry = GeometryMultiLine{}
+	_ Geometry = GeometryMultiPolygon{}
+	_ Geometry = GeometryCollection{}
+)
+
+// NewGeometryLine returns the line joining points.
+func NewGeometryLine(points ...GeometryPoint) GeometryLine {
+	return points
+}
+
+// NewGeometryPolygon returns the polygon bounded by the exterior ring and holed by the interior
+// rings. Rings that are not closed are closed by repeating their first point.
+func NewGeometryPolygon(exterior GeometryLine, interiors ...GeometryLine) GeometryPolygon {
+	polygon := make(GeometryPolygon, 0, len(interiors)+1)
+	for _, ring := range append([]GeometryLine{exterior}, interiors...) {
+		if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+			ring = append(append(GeometryLine{}, ring...), ring[0])
+		}
+		polygon = append(polygon, ring)
+	}
+	return polygon
+}
+
+func NewGeometryMultiPoint(points ...GeometryPoint) GeometryMultiPoint {
+	return points
+}
+
+func NewGeometryMultiLine(lines ...GeometryLine) GeometryMultiLine {
+	return lines
+}
+
+func NewGeometryMultiPolygon(polygons ...GeometryPolygon) GeometryMultiPolygon {
+	return polygons
+}
+
+func NewGeometryCollection(geometries ...Geometry) GeometryCollection {
+	collection := make(GeometryCollection, len(geometries))
+	for i, g := range geometries {
+		collection[i] = g
+	}
+	return collection
+}
+
+func (gp GeometryPoint) Validate() error {
+	if math.IsNaN(gp.Latitude) || gp.Latitude < -90 || gp.Latitude > 90 {
+		return fmt.Errorf("invalid latitude %v", gp.Latitude)
+	}
+	if math.IsNaN(gp.Longitude) || gp.Longitude < -180 || gp.Longitude > 180 {
+		return fmt.Errorf("invalid longitude %v", gp.Longitude)
+	}
+	return nil
+}
+
+func (gl GeometryLine) Validate() error {
+	if len(gl) < 2 {
+		return fmt.Errorf("a line needs at least 2 points, got %d", len(gl))
+	}
+	for i, p := range gl {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("point %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (gp GeometryPolygon) Validate() error {
+	if len(gp) == 0 {
+		return errors.New("a polygon needs an exterior ring")
+	}
+	for i, ring := range gp {
+		if len(ring) < 4 {
+			return fmt.Errorf("ring %d: a ring needs at least 4 points, got %d", i, len(ring))
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return fmt.Errorf("ring %d: the ring is not closed", i)
+		}
+		if err := ring.Validate(); err != nil {
+			return fmt.Errorf("ring %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (gm GeometryMultiPoint) Validate() error {
+	for i, p := range gm {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("point %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (gm GeometryMultiLine) Validate() error {
+	for i, l := range gm {
+		if err := l.Validate(); err != nil {
+			return fmt.Errorf("line %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (gm GeometryMultiPolygon) Validate() error {
+	for i, p := range gm {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("polygon %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (gc GeometryCollection) Validate() error {
+	for i, item := range gc {
+		g, ok := item.(Geometry)
+		if !ok {
+			return fmt.Errorf("geometry %d: %T is not a geometry", i, item)
+		}
+		if err := g.Validate(); err != nil {
+			return fmt.Errorf("geometry %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (GeometryPoint) GeoJSONType() string        { return "Point" }
+func (GeometryLine) GeoJSONType() string         { return "LineString" }
+func (GeometryPolygon) GeoJSONType() string      { return "Polygon" }
+func (GeometryMultiPoint) GeoJSONType() string   { return "MultiPoint" }
+func (GeometryMultiLine) GeoJSONType() string    { return "MultiLineString" }
+func (GeometryMultiPolygon) GeoJSONType() string { return "MultiPolygon" }
+func (GeometryCollection) GeoJSONType() string   { return "GeometryCollection" }
+
+// geoJSON is a GeoJSON geometry object
+type geoJSON struct {
+	Type        string            `json:"type"`
+	Coordinates json.RawMessage   `json:"coordinates,omitempty"`
+	Geometries  []json.RawMessage `json:"geometries,omitempty"`
+}
+
+// position is a GeoJSON position: longitude then latitude
+type position [2]float64
+
+func (gp GeometryPoint) position() position {
+	return position{gp.Longitude, gp.Latitude}
+}
+
+func (p position) point() GeometryPoint {
+	return GeometryPoint{Latitude: p[1], Longitude: p[0]}
+}
+
+func positions(points []GeometryPoint) []position {
+	out := make([]position, len(points))
+	for i, p := range points {
+		out[i] = p.position()
+	}
+	return out
+}
+
+func points(positions []position) []GeometryPoint {
+	out := make([]GeometryPoint, len(positions))
+	for i, p := range positions {
+		out[i] = p.point()
+	}
+	return out
+}
+
+func marshalGeoJSON(g Geometry, coordinates interface{}) ([]byte, error) {
+	raw, err := json.Marshal(coordinates)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(geoJSON{Type: g.GeoJSONType(), Coordinates: raw})
+}
+
+// unmarshalGeoJSON decodes the coordinates of the GeoJSON geometry data of type g into coordinates.
+func unmarshalGeoJSON(data []byte, g Geometry, coordinates interface{}) error {
+	var obj geoJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	if obj.Type != g.GeoJSONType() {
+		return fmt.Errorf("expected a GeoJSON %s, got %q", g.GeoJSONType(), obj.Type)
+	}
+	return json.Unmarshal(obj.Coordinates, coordinates)
+}
+
+// MarshalJSON encodes the point as a GeoJSON Point.
+func (gp GeometryPoint) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON(gp, gp.position())
+}
+
+func (gp *GeometryPoint) UnmarshalJSON(data []byte) error {
+	var p position
+	if err := unmarshalGeoJSON(data, gp, &p); err != nil {
+		return err
+	}
+	*gp = p.point()
+	return nil
+}
+
+// MarshalJSON encodes the line as a GeoJSON LineString.
+func (gl GeometryLine) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON(gl, positions(gl))
+}
+
+func (gl *GeometryLine) UnmarshalJSON(data []byte) error {
+	var p []position
+	if err := unmarshalGeoJSON(data, gl, &p); err != nil {
+		return err
+	}
+	*gl = points(p)
+	return nil
+}
+
+// MarshalJSON encodes the polygon as a GeoJSON Polygon.
+func (gp GeometryPolygon) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON(gp, polygonPositions(gp))
+}
+
+func (gp *GeometryPolygon) UnmarshalJSON(data []byte) error {
+	var p [][]position
+	if err := unmarshalGeoJSON(data, gp, &p); err != nil {
+		return err
+	}
+	*gp = polygonPoints(p)
+	return nil
+}
+
+// MarshalJSON encodes the points as a GeoJSON MultiPoint.
+func (gm GeometryMultiPoint) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON(gm, positions(gm))
+}
+
+func (gm *GeometryMultiPoint) UnmarshalJSON(data []byte) error {
+	var p []position
+	if err := unmarshalGeoJSON(data, gm, &p); err != nil {
+		return err
+	}
+	*gm = points(p)
+	return nil
+}
+
+// MarshalJSON encodes the lines as a GeoJSON MultiLineString.
+func (gm GeometryMultiLine) MarshalJSON() ([]byte, error) {
+	lines := make([][]position, len(gm))
+	for i, l := range gm {
+		lines[i] = positions(l)
+	}
+	return marshalGeoJSON(gm, lines)
+}
+
+func (gm *GeometryMultiLine) UnmarshalJSON(data []byte) error {
+	var p [][]position
+	if err := unmarshalGeoJSON(data, gm, &p); err != nil {
+		return err
+	}
+	*gm = make(GeometryMultiLine, len(p))
+	for i, l := range p {
+		(*gm)[i] = points(l)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the polygons as a GeoJSON MultiPolygon.
+func (gm GeometryMultiPolygon) MarshalJSON() ([]byte, error) {
+	polygons := make([][][]position, len(gm))
+	for i, p := range gm {
+		polygons[i] = polygonPositions(p)
+	}
+	return marshalGeoJSON(gm, polygons)
+}
+
+func (gm *GeometryMultiPolygon) UnmarshalJSON(data []byte) error {
+	var p [][][]position
+	if err := unmarshalGeoJSON(data, gm, &p); err != nil {
+		return err
+	}
+	*gm = make(GeometryMultiPolygon, len(p))
+	for i, polygon := range p {
+		(*gm)[i] = polygonPoints(polygon)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the collection as a GeoJSON GeometryCollection.
+func (gc GeometryCollection) MarshalJSON() ([]byte, error) {
+	geometries := make([]json.RawMessage, len(gc))
+	for i, item := range gc {
+		if _, ok := item.(Geometry); !ok {
+			return nil, fmt.Errorf("geometry %d: %T is not a geometry", i, item)
+		}
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		geometries[i] = raw
+	}
+	return json.Marshal(geoJSON{Type: gc.GeoJSONType(), Geometries: geometries})
+}
+
+func (gc *GeometryCollection) UnmarshalJSON(data []byte) error {
+	var obj geoJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	if obj.Type != gc.GeoJSONType() {
+		return fmt.Errorf("expected a GeoJSON %s, got %q", gc.GeoJSONType(), obj.Type)
+	}
+	*gc = make(GeometryCollection, len(obj.Geometries))
+	for i, raw := range obj.Geometries {
+		g, err := ParseGeoJSON(raw)
+		if err != nil {
+			return fmt.Errorf("geometry %d: %w", i, err)
+		}
+		(*gc)[i] = g
+	}
+	return nil
+}
+
+// ParseGeoJSON decodes a GeoJSON geometry object into the geometry type matching its type.
+func ParseGeoJSON(data []byte) (Geometry, error) {
+	var obj geoJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	var g interface {
+		Geometry
+		json.Unmarshaler
+	}
+	switch obj.Type {
+	case "Point":
+		g = &GeometryPoint{}
+	case "LineString":
+		g = &GeometryLine{}
+	case "Polygon":
+		g = &GeometryPolygon{}
+	case "MultiPoint":
+		g = &GeometryMultiPoint{}
+	case "MultiLineString":
+		g = &GeometryMultiLine{}
+	case "MultiPolygon":
+		g = &GeometryMultiPolygon{}
+	case "GeometryCollection":
+		g = &GeometryCollection{}
+	default:
+		return nil, fmt.Errorf("unsupported GeoJSON type %q", obj.Type)
+	}
+	if err := g.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	// return the geometry by value, as the constructors do
+	return geometryValue(g), nil
+}
+
+func geometryValue(g Geometry) Geometry {
+	switch v := g.(type) {
+	case *GeometryPoint:
+		return *v
+	case *GeometryLine:
+		return *v
+	case *GeometryPolygon:
+		return *v
+	case *GeometryMultiPoint:
+		return *v
+	case *GeometryMultiLine:
+		return *v
+	case *GeometryMultiPolygon:
+		return *v
+	case *GeometryCollection:
+		return *v
+	default:
+		return g
+	}
+}
+
+func polygonPositions(gp GeometryPolygon) [][]position {
+	rings := make([][]position, len(gp))
+	for i, ring := range gp {
+		rings[i] = positions(ring)
+	}
+	return rings
+}
+
+func polygonPoints(rings [][]position) GeometryPolygon {
+	polygon := make(GeometryPolygon, len(rings))
+	for i, ring := range rings {
+		polygon[i] = points(ring)
+	}
+	return polygon
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoJSON(t *testing.T) {
+	a, b, c := NewGeometryPoint(1, 2), NewGeometryPoint(3, 4), NewGeometryPoint(5, 2)
+	line := NewGeometryLine(a, b)
+	polygon := NewGeometryPolygon(NewGeometryLine(a, b, c))
+	geometries := []Geometry{
+		a,
+		line,
+		polygon,
+		NewGeometryMultiPoint(a, b),
+		NewGeometryMultiLine(line, line),
+		NewGeometryMultiPolygon(polygon),
+		NewGeometryCollection(a, line, polygon),
+	}
+
+	for _, g := range geometries {
+		t.Run(g.GeoJSONType(), func(t *testing.T) {
+			assert.NoError(t, g.Validate())
+
+			data, err := json.Marshal(g)
+			assert.NoError(t, err)
+			parsed, err := ParseGeoJSON(data)
+			assert.NoError(t, err)
+			assert.Equal(t, g, parsed)
+		})
+	}
+
+	data, err := json.Marshal(a)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"Point","coordinates":[2,1]}`, string(data), "positions are longitude first")
+
+	var p GeometryPoint
+	assert.Error(t, json.Unmarshal([]byte(`{"type":"LineString","coordinates":[[1,2],[3,4]]}`), &p))
+	_, err = ParseGeoJSON([]byte(`{"type":"Feature"}`))
+	assert.Error(t, err)
+}
+
+func TestGeometryValidate(t *testing.T) {
+	a, b := NewGeometryPoint(1, 2), NewGeometryPoint(3, 4)
+
+	assert.Error(t, NewGeometryPoint(91, 0).Validate())
+	assert.Error(t, NewGeometryPoint(0, 181).Validate())
+	assert.Error(t, NewGeometryLine(a).Validate())
+	assert.Error(t, GeometryPolygon{NewGeometryLine(a, b, a)}.Validate())
+	assert.Error(t, GeometryPolygon{NewGeometryLine(a, b, NewGeometryPoint(5, 6), b)}.Validate(), "ring not closed")
+	assert.NoError(t, NewGeometryPolygon(NewGeometryLine(a, b, NewGeometryPoint(5, 6))).Validate())
+	assert.Error(t, GeometryCollection{"point"}.Validate())
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPublicID is returned when a public token cannot be mapped back to a record id, either
+// because it is malformed or because it was not issued with the same secret.
+var ErrInvalidPublicID = errors.New("invalid public record id")
+
+// MinObfuscatorSecretLength is the minimum length of the secret given to NewIDObfuscator.
+const MinObfuscatorSecretLength = 32
+
+// RecordIDEncoder maps record ids to opaque public tokens and back.
+type RecordIDEncoder interface {
+	EncodeRecordID(id RecordID) (string, error)
+	DecodeRecordID(token string) (RecordID, error)
+}
+
+// IDObfuscator is a RecordIDEncoder hiding both the table name and the id behind a URL safe token.
+//
+// The record id is encrypted with AES-GCM, using a nonce derived from the record id with HMAC-SHA256.
+// The same record id always maps to the same token, and tokens cannot be forged or altered without
+// the secret.
+type IDObfuscator struct {
+	aead   cipher.AEAD
+	macKey []byte
+}
+
+// NewIDObfuscator returns an IDObfuscator deriving its keys from secret, which must be at least
+// MinObfuscatorSecretLength bytes long.
+func NewIDObfuscator(secret []byte) (*IDObfuscator, error) {
+	if len(secret) < MinObfuscatorSecretLength {
+		return nil, fmt.Errorf("secret must be at least %d bytes long", MinObfuscatorSecretLength)
+	}
+
+	block, err := aes.NewCipher(deriveKey(secret, "surrealdb.go public id encryption"))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IDObfuscator{
+		aead:   aead,
+		macKey: deriveKey(secret, "surrealdb.go public id nonce"),
+	}, nil
+}
+
+// EncodeRecordID returns the public token of id.
+func (o *IDObfuscator) EncodeRecordID(id RecordID) (string, error) {
+	plain, err := getCborEncoder().Marshal(&id)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := deriveKey(o.macKey, string(plain))[:o.aead.NonceSize()]
+	sealed := o.aead.Seal(nonce, nonce, plain, nil)
+
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// DecodeRecordID returns the record id behind a token returned by EncodeRecordID.
+func (o *IDObfuscator) DecodeRecordID(token string) (RecordID, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < o.aead.NonceSize() {
+		return RecordID{}, ErrInvalidPublicID
+	}
+
+	nonce, ciphertext := sealed[:o.aead.NonceSize()], sealed[o.aead.NonceSize():]
+	plain, err := o.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return RecordID{}, ErrInvalidPublicID
+	}
+
+	var id RecordID
+	if err := getCborDecoder().Unmarshal(plain, &id); err != nil {
+		return RecordID{}, ErrInvalidPublicID
+	}
+	return id, nil
+}
+
+func deriveKey(secret []byte, label string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(label))
+	return mac.Sum(nil)
+}
+
+// PublicRecordID is a record id marshalling to JSON as the public token returned by its Encoder,
+// for the values sent outside, such as the ids in the responses of an API. The encoder is set on
+// each value, including before unmarshalling into it, so that the JSON encoding of RecordID, used
+// by other code in the process, is left unchanged:
+//
+//	response := userResponse{ID: models.NewPublicRecordID(user.ID, obfuscator)}
+//
+//	request := userRequest{ID: models.PublicRecordID{Encoder: obfuscator}}
+//	err := json.Unmarshal(body, &request)
+type PublicRecordID struct {
+	ID      RecordID
+	Encoder RecordIDEncoder
+}
+
+// NewPublicRecordID returns id, to be marshalled to JSON as its token returned by enc.
+func NewPublicRecordID(id RecordID, enc RecordIDEncoder) PublicRecordID {
+	return PublicRecordID{ID: id, Encoder: enc}
+}
+
+func (p PublicRecordID) MarshalJSON() ([]byte, error) {
+	if p.Encoder == nil {
+		return nil, fmt.Errorf("public record id %s has no encoder", p.ID.String())
+	}
+	token, err := p.Encoder.EncodeRecordID(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(token)
+}
+
+func (p *PublicRecordID) UnmarshalJSON(data []byte) error {
+	if p.Encoder == nil {
+		return fmt.Errorf("public record id has no encoder to decode its token")
+	}
+	var token string
+	if err := json.Unmarshal(data, &token); err != nil {
+		return err
+	}
+	id, err := p.Encoder.DecodeRecordID(token)
+	if err != nil {
+		return err
+	}
+	p.ID = id
+	return nil
+}
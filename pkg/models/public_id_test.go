@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testObfuscatorSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestIDObfuscator(t *testing.T) {
+	o, err := NewIDObfuscator(testObfuscatorSecret)
+	assert.NoError(t, err)
+
+	ids := []RecordID{
+		NewRecordID("users", "john"),
+		NewRecordID("users", uint64(42)),
+		NewRecordID("数据库", "🦀"),
+	}
+	for _, id := range ids {
+		token, err := o.EncodeRecordID(id)
+		assert.NoError(t, err)
+		assert.NotContains(t, token, id.Table, "the table name must not leak")
+
+		again, err := o.EncodeRecordID(id)
+		assert.NoError(t, err)
+		assert.Equal(t, token, again, "tokens should be stable")
+
+		decoded, err := o.DecodeRecordID(token)
+		assert.NoError(t, err)
+		assert.Equal(t, id, decoded)
+	}
+
+	t.Run("tokens are bound to the secret", func(t *testing.T) {
+		token, err := o.EncodeRecordID(NewRecordID("users", "john"))
+		assert.NoError(t, err)
+
+		other, err := NewIDObfuscator([]byte(strings.Repeat("x", MinObfuscatorSecretLength)))
+		assert.NoError(t, err)
+		_, err = other.DecodeRecordID(token)
+		assert.ErrorIs(t, err, ErrInvalidPublicID)
+	})
+
+	t.Run("altered tokens are rejected", func(t *testing.T) {
+		for _, token := range []string{"", "not base64!", "AAAA", "YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXo"} {
+			_, err := o.DecodeRecordID(token)
+			assert.ErrorIs(t, err, ErrInvalidPublicID)
+		}
+	})
+
+	t.Run("short secrets are rejected", func(t *testing.T) {
+		_, err := NewIDObfuscator([]byte("secret"))
+		assert.Error(t, err)
+	})
+}
+
+func TestPublicRecordID(t *testing.T) {
+	o, err := NewIDObfuscator(testObfuscatorSecret)
+	assert.NoError(t, err)
+
+	type user struct {
+		ID   PublicRecordID `json:"id"`
+		Name string         `json:"name"`
+	}
+	u := user{ID: NewPublicRecordID(NewRecordID("users", "john"), o), Name: "John"}
+
+	data, err := json.Marshal(u)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "users")
+
+	decoded := user{ID: PublicRecordID{Encoder: o}}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, u, decoded)
+
+	var noEncoder user
+	assert.Error(t, json.Unmarshal(data, &noEncoder))
+	_, err = json.Marshal(user{ID: PublicRecordID{ID: NewRecordID("users", "john")}})
+	assert.Error(t, err)
+
+	t.Run("record ids keep their encoding", func(t *testing.T) {
+		data, err := json.Marshal(NewRecordID("users", "john"))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"Table":"users","ID":"john"}`, string(data))
+	})
+}
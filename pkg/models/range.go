@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/fxamacker/cbor/v2"
 )
@@ -147,3 +148,132 @@ func convertToString(v any) string {
 	// todo: implement
 	return ""
 }
+
+//---------------------------------------------------------------------------------------------------------------------//
+
+// RecordIDRange is a range of the record ids of a table, such as person:1..1000, which the
+// server scans without reading the other records of the table.
+type RecordIDRange struct {
+	Table Table
+	// Begin and End bound the ids of the range, nil for an unbounded side.
+	Begin *RecordIDBound
+	End   *RecordIDBound
+}
+
+// RecordIDBound is a bound of a RecordIDRange.
+type RecordIDBound struct {
+	ID       interface{}
+	Included bool
+}
+
+// Included returns a bound including id.
+func Included(id interface{}) *RecordIDBound {
+	return &RecordIDBound{ID: id, Included: true}
+}
+
+// Excluded returns a bound excluding id.
+func Excluded(id interface{}) *RecordIDBound {
+	return &RecordIDBound{ID: id}
+}
+
+// NewRecordIDRange returns the range begin..end of the ids of table, which includes begin and
+// excludes end like in SurrealQL. A nil begin or end leaves that side unbounded.
+func NewRecordIDRange(table Table, begin, end interface{}) RecordIDRange {
+	r := RecordIDRange{Table: table}
+	if begin != nil {
+		r.Begin = Included(begin)
+	}
+	if end != nil {
+		r.End = Excluded(end)
+	}
+	return r
+}
+
+// String returns the range as written in SurrealQL, such as person:1..=1000.
+func (r RecordIDRange) String() string {
+	var b strings.Builder
+	b.WriteString(EscapeIdent(string(r.Table)))
+	b.WriteByte(':')
+	if r.Begin != nil {
+		b.WriteString(formatID(r.Begin.ID))
+		if !r.Begin.Included {
+			b.WriteByte('>')
+		}
+	}
+	b.WriteString("..")
+	if r.End != nil {
+		if r.End.Included {
+			b.WriteByte('=')
+		}
+		b.WriteString(formatID(r.End.ID))
+	}
+	return b.String()
+}
+
+// MarshalCBOR encodes the range as a record id whose id is a range.
+func (r RecordIDRange) MarshalCBOR() ([]byte, error) {
+	return getCborEncoder().Marshal(cbor.Tag{
+		Number: TagRecordID,
+		Content: []interface{}{string(r.Table), cbor.Tag{
+			Number:  TagRange,
+			Content: []interface{}{r.Begin.tag(), r.End.tag()},
+		}},
+	})
+}
+
+// UnmarshalCBOR decodes a record id whose id is a range.
+func (r *RecordIDRange) UnmarshalCBOR(data []byte) error {
+	dec := getCborDecoder()
+
+	var id cbor.RawTag
+	if err := dec.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	if id.Number != TagRecordID {
+		return fmt.Errorf("cannot decode tag %d into a record id range", id.Number)
+	}
+	var content struct {
+		_     struct{} `cbor:",toarray"`
+		Table string
+		Range cbor.RawTag
+	}
+	if err := dec.Unmarshal(id.Content, &content); err != nil {
+		return err
+	}
+	if content.Range.Number != TagRange {
+		return fmt.Errorf("record id %s is not a range", content.Table)
+	}
+	var bounds [2]cbor.RawTag
+	if err := dec.Unmarshal(content.Range.Content, &bounds); err != nil {
+		return err
+	}
+
+	decoded := RecordIDRange{Table: Table(content.Table)}
+	for i, bound := range []**RecordIDBound{&decoded.Begin, &decoded.End} {
+		switch bounds[i].Number {
+		case TagNone:
+			continue
+		case TagBoundIncluded, TagBoundExcluded:
+			*bound = &RecordIDBound{Included: bounds[i].Number == TagBoundIncluded}
+			if err := dec.Unmarshal(bounds[i].Content, &(*bound).ID); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("cannot decode tag %d into a range bound", bounds[i].Number)
+		}
+	}
+	*r = decoded
+	return nil
+}
+
+// tag returns the bound as a CBOR bound, NONE when unbounded.
+func (b *RecordIDBound) tag() cbor.Tag {
+	switch {
+	case b == nil:
+		return cbor.Tag{Number: TagNone}
+	case b.Included:
+		return cbor.Tag{Number: TagBoundIncluded, Content: b.ID}
+	default:
+		return cbor.Tag{Number: TagBoundExcluded, Content: b.ID}
+	}
+}
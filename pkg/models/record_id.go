@@ -16,14 +16,15 @@ type RecordIDType interface {
 	~int | ~string | []any | map[string]any
 }
 
+// ParseRecordID parses a record id string such as "users:john". Either part may be escaped with
+// ⟨⟩ or backticks, which allows identifiers containing any character, including ':' in the id.
 func ParseRecordID(idStr string) *RecordID {
-	expectedLen := 2
-	bits := strings.Split(idStr, ":")
-	if len(bits) != expectedLen {
+	table, id, found := cutUnescaped(idStr, ':')
+	if !found {
 		panic(fmt.Errorf("invalid id string. Expected format is 'tablename:indentifier'"))
 	}
 	return &RecordID{
-		Table: bits[0], ID: bits[1],
+		Table: unescapeIdent(table), ID: unescapeIdent(id),
 	}
 }
 
@@ -55,10 +56,118 @@ func (r *RecordID) UnmarshalCBOR(data []byte) error {
 	return nil
 }
 
+// String returns the record id as written in SurrealQL, escaping the table and id when needed.
 func (r *RecordID) String() string {
-	return fmt.Sprintf("%s:%s", r.Table, r.ID)
+	return fmt.Sprintf("%s:%s", EscapeIdent(r.Table), formatID(r.ID))
 }
 
 func (r *RecordID) SurrealString() string {
-	return fmt.Sprintf("r'%s'", r.String())
+	return fmt.Sprintf("r'%s'", strings.ReplaceAll(r.String(), "'", `\'`))
+}
+
+// formatID formats the id part of a record id, escaping string ids when needed.
+func formatID(id interface{}) string {
+	if s, ok := id.(string); ok {
+		return EscapeIdent(s)
+	}
+	return fmt.Sprintf("%v", id)
+}
+
+// EscapeIdent returns the identifier s, such as a table, field or index name, as written in
+// SurrealQL. It is wrapped in ⟨⟩ unless it only holds ASCII letters, digits and underscores, does
+// not start with a digit, which SurrealQL would read as a number, and is not a reserved keyword.
+// Backslashes and ⟩ are escaped with a backslash.
+func EscapeIdent(s string) string {
+	simple := s != "" && !(s[0] >= '0' && s[0] <= '9') && !reservedKeywords[strings.ToUpper(s)]
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_') {
+			simple = false
+			break
+		}
+	}
+	if simple {
+		return s
+	}
+	return "⟨" + identEscaper.Replace(s) + "⟩"
+}
+
+// EscapeFieldPath escapes every part of a field path such as address.city with EscapeIdent. It
+// returns an error when a part of the path is empty.
+func EscapeFieldPath(path string) (string, error) {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		if part == "" {
+			return "", fmt.Errorf("invalid field path %q", path)
+		}
+		parts[i] = EscapeIdent(part)
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// reservedKeywords are the keywords SurrealQL does not read as identifiers where a statement or
+// a value may start.
+var reservedKeywords = map[string]bool{
+	"ALTER": true, "ANALYZE": true, "BEGIN": true, "BREAK": true, "CANCEL": true, "COMMIT": true,
+	"CONTINUE": true, "CREATE": true, "DEFINE": true, "DELETE": true, "FALSE": true, "FOR": true,
+	"IF": true, "INFO": true, "INSERT": true, "KILL": true, "LET": true, "LIVE": true, "NONE": true,
+	"NULL": true, "OPTION": true, "REBUILD": true, "RELATE": true, "REMOVE": true, "RETURN": true,
+	"SELECT": true, "SHOW": true, "SLEEP": true, "THROW": true, "TRUE": true, "UPDATE": true,
+	"UPSERT": true, "USE": true,
+}
+
+var identEscaper = strings.NewReplacer(`\`, `\\`, "⟩", `\⟩`)
+
+// unescapeIdent reverses EscapeIdent, also accepting backtick escaping.
+func unescapeIdent(s string) string {
+	switch {
+	case strings.HasPrefix(s, "⟨") && strings.HasSuffix(s, "⟩") && len(s) > len("⟨⟩"):
+		return unescapeBackslashes(strings.TrimSuffix(strings.TrimPrefix(s, "⟨"), "⟩"))
+	case strings.HasPrefix(s, "`") && strings.HasSuffix(s, "`") && len(s) > 1:
+		return unescapeBackslashes(s[1 : len(s)-1])
+	default:
+		return s
+	}
+}
+
+// unescapeBackslashes replaces each character escaped with a backslash by the character itself.
+func unescapeBackslashes(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	escaped := false
+	for _, c := range s {
+		if c == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// cutUnescaped slices s around the first sep that is not inside ⟨⟩ or backticks.
+func cutUnescaped(s string, sep rune) (before, after string, found bool) {
+	var closing rune
+	escaped := false
+	for i, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case closing != 0:
+			if c == closing {
+				closing = 0
+			}
+		case c == '⟨':
+			closing = '⟩'
+		case c == '`':
+			closing = '`'
+		case c == sep:
+			return s[:i], s[i+len(string(sep)):], true
+		}
+	}
+	return s, "", false
 }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordIDRange(t *testing.T) {
+	assert.Equal(t, "person:1..1000", NewRecordIDRange("person", 1, 1000).String())
+	assert.Equal(t, "person:1..", NewRecordIDRange("person", 1, nil).String())
+	assert.Equal(t, "⟨user-log⟩:⟨a b⟩>..=z", RecordIDRange{
+		Table: "user-log",
+		Begin: Excluded("a b"),
+		End:   Included("z"),
+	}.String())
+
+	r := RecordIDRange{Table: "person", Begin: Excluded("a"), End: Included([]interface{}{"b", uint64(2)})}
+	data, err := CborMarshaler{}.Marshal(r)
+	assert.NoError(t, err)
+
+	var raw cbor.Tag
+	assert.NoError(t, cbor.Unmarshal(data, &raw))
+	assert.Equal(t, TagRecordID, raw.Number)
+	assert.Equal(t, []interface{}{"person", cbor.Tag{Number: TagRange, Content: []interface{}{
+		cbor.Tag{Number: TagBoundExcluded, Content: "a"},
+		cbor.Tag{Number: TagBoundIncluded, Content: []interface{}{"b", uint64(2)}},
+	}}}, raw.Content)
+
+	var decoded RecordIDRange
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+	assert.Equal(t, r, decoded)
+
+	data, err = CborMarshaler{}.Marshal(NewRecordIDRange("person", nil, uint64(10)))
+	assert.NoError(t, err)
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+	assert.Equal(t, NewRecordIDRange("person", nil, uint64(10)), decoded)
+
+	data, err = CborMarshaler{}.Marshal(NewRecordID("person", 1))
+	assert.NoError(t, err)
+	assert.Error(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// multiByteIdentifiers covers emoji, CJK, right-to-left text and combining characters, which
+// must survive every conversion byte for byte.
+var multiByteIdentifiers = []string{
+	"Ϭlub",
+	"→owns→Ϭlub",
+	"🦀🚀",
+	"👩‍👩‍👧",
+	"数据库",
+	"テーブル",
+	"שלום",
+	"مرحبا",
+	"été", // decomposed accents, must not be normalized
+	"a:b",
+	"tab⟩le",
+	"O'Brien",
+}
+
+func TestRecordIDString(t *testing.T) {
+	cases := []struct {
+		id       RecordID
+		expected string
+	}{
+		{NewRecordID("users", "john"), "users:john"},
+		{NewRecordID("users", 42), "users:42"},
+		{NewRecordID("users", "john doe"), "users:⟨john doe⟩"},
+		{NewRecordID("数据库", "Ϭlub"), "⟨数据库⟩:⟨Ϭlub⟩"},
+		{NewRecordID("users", "a⟩b"), `users:⟨a\⟩b⟩`},
+		{NewRecordID("users", "123"), "users:⟨123⟩"},
+		{NewRecordID("123abc", "1a"), "⟨123abc⟩:⟨1a⟩"},
+		{NewRecordID("users", `a\`), `users:⟨a\\⟩`},
+		{NewRecordID("users", `a\⟩b`), `users:⟨a\\\⟩b⟩`},
+		{NewRecordID("select", "null"), "⟨select⟩:⟨null⟩"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, c.id.String())
+	}
+}
+
+func TestEscapeFieldPath(t *testing.T) {
+	escaped, err := EscapeFieldPath("address.zip code.1st")
+	assert.NoError(t, err)
+	assert.Equal(t, "address.⟨zip code⟩.⟨1st⟩", escaped)
+
+	for _, path := range []string{"", "address.", ".city", "a..b"} {
+		_, err := EscapeFieldPath(path)
+		assert.Error(t, err, "path %q should be rejected", path)
+	}
+}
+
+func TestParseRecordIDEscaped(t *testing.T) {
+	// digits would be read as a number, and backslashes as the start of an escape
+	for _, ident := range []string{"123", "1abc", `back\slash`, `end\`, `a\⟩b`, "`"} {
+		id := NewRecordID(ident, ident)
+		parsed := ParseRecordID(id.String())
+
+		assert.Equal(t, ident, parsed.Table, "table %q should round trip", ident)
+		assert.Equal(t, ident, parsed.ID, "id %q should round trip", ident)
+	}
+}
+
+func TestParseRecordIDMultiByte(t *testing.T) {
+	for _, ident := range multiByteIdentifiers {
+		id := NewRecordID(ident, ident)
+		parsed := ParseRecordID(id.String())
+
+		assert.Equal(t, ident, parsed.Table, "table %q should round trip", ident)
+		assert.Equal(t, ident, parsed.ID, "id %q should round trip", ident)
+	}
+
+	parsed := ParseRecordID("`my table`:⟨a:b⟩")
+	assert.Equal(t, "my table", parsed.Table)
+	assert.Equal(t, "a:b", parsed.ID)
+}
+
+func TestRecordIDCborMultiByte(t *testing.T) {
+	em := getCborEncoder()
+	dm := getCborDecoder()
+
+	for _, ident := range multiByteIdentifiers {
+		id := NewRecordID(ident, ident)
+		encoded, err := em.Marshal(&id)
+		assert.Nil(t, err, "Should not encounter an error while encoding")
+
+		var decoded RecordID
+		err = dm.Unmarshal(encoded, &decoded)
+		assert.Nil(t, err, "Should not encounter an error while decoding")
+		assert.Equal(t, id, decoded)
+	}
+}
@@ -6,8 +6,10 @@ import (
 )
 
 func replacerBeforeEncode(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
 	valueType := reflect.TypeOf(value)
-	valueKind := valueType.Kind()
 
 	if valueType == reflect.TypeOf(time.Duration(0)) {
 		oldVal := value.(time.Duration)
@@ -15,8 +17,7 @@ func replacerBeforeEncode(value interface{}) interface{} {
 		return newValue
 	}
 
-	if valueKind == reflect.Map {
-		oldValue := value.(map[string]interface{})
+	if oldValue, ok := value.(map[string]interface{}); ok {
 		newValue := make(map[interface{}]interface{})
 		for k, v := range oldValue {
 			newKey := replacerBeforeEncode(k)
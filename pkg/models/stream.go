@@ -0,0 +1,250 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// maxStreamNesting bounds the nesting of the items read by an ArrayDecoder
+const maxStreamNesting = 64
+
+const cborBreak = 0xff
+
+// ErrNotArray is returned by an ArrayDecoder reading a value that is not an array.
+var ErrNotArray = errors.New("cbor value is not an array")
+
+// ArrayDecoder reads the items of a CBOR array one at a time from a reader, so that arrays larger
+// than the available memory, such as exports of large tables, can be processed item by item.
+// Only the item being read is held in memory.
+//
+//	dec := models.NewArrayDecoder(file)
+//	for {
+//		var person Person
+//		err := dec.Decode(&person)
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+type ArrayDecoder struct {
+	r *bufio.Reader
+
+	started bool
+	// remaining is the number of items not read yet, -1 for an indefinite length array
+	remaining int
+	buf       bytes.Buffer
+	err       error
+}
+
+// NewArrayDecoder returns a decoder of the CBOR array read from r.
+func NewArrayDecoder(r io.Reader) *ArrayDecoder {
+	return &ArrayDecoder{r: bufio.NewReader(r)}
+}
+
+// Len returns the number of items of the array, or -1 when the array has an indefinite length.
+func (d *ArrayDecoder) Len() (int, error) {
+	if err := d.start(); err != nil {
+		return 0, err
+	}
+	return d.remaining, nil
+}
+
+// Next returns the encoding of the next item of the array, and io.EOF once there are no items
+// left. The returned bytes are only valid until the next call.
+func (d *ArrayDecoder) Next() (cbor.RawMessage, error) {
+	if err := d.start(); err != nil {
+		return nil, err
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if d.remaining == 0 {
+		d.err = io.EOF
+		return nil, io.EOF
+	}
+	if d.remaining < 0 {
+		b, err := d.r.Peek(1)
+		if err != nil {
+			d.err = unexpectedEOF(err)
+			return nil, d.err
+		}
+		if b[0] == cborBreak {
+			_, _ = d.r.ReadByte()
+			d.err = io.EOF
+			return nil, io.EOF
+		}
+	}
+
+	d.buf.Reset()
+	if err := d.readItem(0); err != nil {
+		d.err = fmt.Errorf("reading cbor array item: %w", unexpectedEOF(err))
+		return nil, d.err
+	}
+	if d.remaining > 0 {
+		d.remaining--
+	}
+	return d.buf.Bytes(), nil
+}
+
+// Decode decodes the next item of the array into v, and returns io.EOF once there are no items left.
+func (d *ArrayDecoder) Decode(v interface{}) error {
+	item, err := d.Next()
+	if err != nil {
+		return err
+	}
+	return CborUnmarshaler{}.Unmarshal(item, v)
+}
+
+// Skip skips the next item of the array without decoding it, and returns io.EOF once there are
+// no items left.
+func (d *ArrayDecoder) Skip() error {
+	_, err := d.Next()
+	return err
+}
+
+func (d *ArrayDecoder) start() error {
+	if d.started {
+		return nil
+	}
+	d.started = true
+
+	major, arg, indefinite, err := d.readHead(false)
+	if err != nil {
+		d.err = unexpectedEOF(err)
+		return d.err
+	}
+	if major != 4 {
+		d.err = ErrNotArray
+		return d.err
+	}
+	if indefinite {
+		d.remaining = -1
+	} else if arg > uint64(^uint(0)>>1) {
+		d.err = fmt.Errorf("cbor array too long: %d items", arg)
+		return d.err
+	} else {
+		d.remaining = int(arg)
+	}
+	return nil
+}
+
+// readHead reads the head of a data item: its major type and argument. When keep is true, the
+// bytes read are appended to the buffer.
+func (d *ArrayDecoder) readHead(keep bool) (major byte, arg uint64, indefinite bool, err error) {
+	initial, err := d.r.ReadByte()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if keep {
+		d.buf.WriteByte(initial)
+	}
+
+	major, info := initial>>5, initial&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), false, nil
+	case info == 31:
+		if major == 0 || major == 1 || major == 6 {
+			return 0, 0, false, fmt.Errorf("invalid indefinite length for major type %d", major)
+		}
+		return major, 0, true, nil
+	case info > 27:
+		return 0, 0, false, fmt.Errorf("invalid additional information %d", info)
+	}
+
+	size := 1 << (info - 24)
+	var b [8]byte
+	if _, err := io.ReadFull(d.r, b[:size]); err != nil {
+		return 0, 0, false, err
+	}
+	if keep {
+		d.buf.Write(b[:size])
+	}
+	switch size {
+	case 1:
+		arg = uint64(b[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(b[:2]))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(b[:4]))
+	default:
+		arg = binary.BigEndian.Uint64(b[:8])
+	}
+	return major, arg, false, nil
+}
+
+// readItem reads a complete data item into the buffer.
+func (d *ArrayDecoder) readItem(depth int) error {
+	if depth > maxStreamNesting {
+		return fmt.Errorf("cbor item nested more than %d levels", maxStreamNesting)
+	}
+
+	major, arg, indefinite, err := d.readHead(true)
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case 0, 1:
+		return nil
+	case 2, 3:
+		if indefinite {
+			return d.readUntilBreak(depth)
+		}
+		_, err := io.CopyN(&d.buf, d.r, int64(arg))
+		return err
+	case 4, 5:
+		if indefinite {
+			return d.readUntilBreak(depth)
+		}
+		items := arg
+		if major == 5 {
+			items *= 2
+		}
+		for i := uint64(0); i < items; i++ {
+			if err := d.readItem(depth + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case 6:
+		return d.readItem(depth + 1)
+	default:
+		if indefinite {
+			return errors.New("unexpected break")
+		}
+		return nil
+	}
+}
+
+// readUntilBreak reads the items of an indefinite length item, up to its break.
+func (d *ArrayDecoder) readUntilBreak(depth int) error {
+	for {
+		b, err := d.r.Peek(1)
+		if err != nil {
+			return err
+		}
+		if b[0] == cborBreak {
+			_, _ = d.r.ReadByte()
+			d.buf.WriteByte(cborBreak)
+			return nil
+		}
+		if err := d.readItem(depth + 1); err != nil {
+			return err
+		}
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
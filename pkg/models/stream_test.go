@@ -0,0 +1,87 @@
+package models
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrayDecoder(t *testing.T) {
+	type person struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+		ID   RecordID `json:"id"`
+	}
+	people := []person{
+		{Name: "tobie", Tags: []string{"a", "b"}, ID: NewRecordID("person", "tobie")},
+		{Name: "jaime", ID: NewRecordID("person", []interface{}{"jaime", uint64(2)})},
+	}
+
+	encoded, err := CborMarshaler{}.Marshal(people)
+	assert.NoError(t, err)
+
+	dec := NewArrayDecoder(bytes.NewReader(encoded))
+	n, err := dec.Len()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	var decoded []person
+	for {
+		var p person
+		err := dec.Decode(&p)
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		decoded = append(decoded, p)
+	}
+	assert.Equal(t, people, decoded)
+}
+
+func TestArrayDecoderIndefiniteLength(t *testing.T) {
+	// [_ 1, "ab" (in two chunks), {_ "a": [_ ]}, 1.5 ]
+	encoded := []byte{
+		0x9f,
+		0x01,
+		0x7f, 0x61, 'a', 0x61, 'b', 0xff,
+		0xbf, 0x61, 'a', 0x9f, 0xff, 0xff,
+		0xf9, 0x3e, 0x00,
+		0xff,
+	}
+
+	dec := NewArrayDecoder(bytes.NewReader(encoded))
+	n, err := dec.Len()
+	assert.NoError(t, err)
+	assert.Equal(t, -1, n)
+
+	var i int
+	assert.NoError(t, dec.Decode(&i))
+	assert.Equal(t, 1, i)
+
+	var s string
+	assert.NoError(t, dec.Decode(&s))
+	assert.Equal(t, "ab", s)
+
+	assert.NoError(t, dec.Skip())
+
+	var f float64
+	assert.NoError(t, dec.Decode(&f))
+	assert.Equal(t, 1.5, f)
+
+	assert.Equal(t, io.EOF, dec.Skip())
+}
+
+func TestArrayDecoderErrors(t *testing.T) {
+	_, err := NewArrayDecoder(bytes.NewReader([]byte{0x01})).Next()
+	assert.ErrorIs(t, err, ErrNotArray)
+
+	// an array of two items, the second truncated
+	_, err = NewArrayDecoder(bytes.NewReader([]byte{0x82, 0x01, 0x63, 'a'})).Next()
+	assert.NoError(t, err)
+
+	dec := NewArrayDecoder(bytes.NewReader([]byte{0x82, 0x01, 0x63, 'a'}))
+	assert.NoError(t, dec.Skip())
+	assert.ErrorIs(t, dec.Skip(), io.ErrUnexpectedEOF)
+}
@@ -5,3 +5,8 @@ type Table string
 func (t Table) String() string {
 	return string(t)
 }
+
+// SurrealString returns the table name as written in SurrealQL, escaping it when needed.
+func (t Table) SurrealString() string {
+	return EscapeIdent(string(t))
+}
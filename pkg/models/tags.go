@@ -0,0 +1,436 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// SurrealTag is the struct tag configuring how a field is stored in SurrealDB, independently of
+// its json tag, which is otherwise used. Its value is the name of the field followed by options,
+// separated by commas:
+//
+//	type Person struct {
+//		ID      *RecordID `json:"id" surreal:"id,omitempty"`
+//		Name    string    `json:"name" surreal:"full_name"`
+//		Manager string    `json:"manager" surreal:"manager,record"`
+//		Nick    *string   `json:"nick" surreal:"nick,none"`
+//		Secret  string    `json:"-" surreal:"secret"`
+//	}
+//
+// The options are:
+//   - omitempty: the field is omitted when it holds its zero value.
+//   - none: a nil field is sent as NONE, which removes the field, rather than as NULL.
+//   - record: a string field holds a record id, such as "person:tobie", sent as a record link and
+//     read back from one. The id is sent as a string, so use a *RecordID field for numeric ids.
+//
+// A field tagged with "-" is neither sent nor read.
+const SurrealTag = "surreal"
+
+type fieldInfo struct {
+	index     []int
+	name      string
+	omitEmpty bool
+	none      bool
+	record    bool
+}
+
+// structInfo holds the fields of a struct type as they are stored, and whether any of them has a
+// surreal tag.
+type structInfo struct {
+	fields []fieldInfo
+	tagged bool
+}
+
+var structInfos sync.Map
+
+func getStructInfo(t reflect.Type) *structInfo {
+	if info, ok := structInfos.Load(t); ok {
+		return info.(*structInfo)
+	}
+	info := &structInfo{}
+	collectFields(t, nil, info)
+	structInfos.Store(t, info)
+	return info
+}
+
+func collectFields(t reflect.Type, index []int, info *structInfo) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		tag, surreal := f.Tag.Lookup(SurrealTag)
+		if !surreal {
+			if tag = f.Tag.Get("cbor"); tag == "" {
+				tag = f.Tag.Get("json")
+			}
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if surreal {
+			info.tagged = true
+		}
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			collectFields(f.Type, fieldIndex, info)
+			continue
+		}
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		field := fieldInfo{index: fieldIndex, name: name}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				field.omitEmpty = true
+			case "none":
+				field.none = surreal
+			case "record":
+				field.record = surreal && f.Type.Kind() == reflect.String
+			}
+		}
+		info.fields = append(info.fields, field)
+	}
+}
+
+// FieldNames returns the names under which the fields of the struct type t, or of the struct t
+// points to, are stored, read from their surreal, cbor or json tags like the codec does.
+func FieldNames(t reflect.Type) ([]string, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", t)
+	}
+
+	info := getStructInfo(t)
+	names := make([]string, len(info.fields))
+	for i, field := range info.fields {
+		names[i] = field.name
+	}
+	return names, nil
+}
+
+// CheckFieldPath checks that path, a field path such as address.city, names a field stored by the
+// struct type t, or by the struct t points to, with the field names read from their tags like
+// FieldNames. The path goes through the elements of slices and arrays, as SurrealQL does. The part
+// of the path below a map, an interface or a type with its own CBOR encoding is not checked.
+func CheckFieldPath(t reflect.Type, path string) error {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Map || t.Kind() == reflect.Interface || t.Implements(cborUnmarshalerType) ||
+			reflect.PointerTo(t).Implements(cborUnmarshalerType) {
+			return nil
+		}
+		if t.Kind() != reflect.Struct {
+			return fmt.Errorf("field %s is a %s, which has no field %s", strings.Join(parts[:i], "."), t, part)
+		}
+
+		found := false
+		for _, field := range getStructInfo(t).fields {
+			if field.name == part {
+				t = t.FieldByIndex(field.index).Type
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s has no field %s", t, strings.Join(parts[:i+1], "."))
+		}
+	}
+	return nil
+}
+
+var (
+	cborMarshalerType   = reflect.TypeOf((*cbor.Marshaler)(nil)).Elem()
+	cborUnmarshalerType = reflect.TypeOf((*cbor.Unmarshaler)(nil)).Elem()
+)
+
+// surrealTagsUsage tells which types hold structs with surreal tags, and must be encoded or decoded
+// field by field rather than by the CBOR library.
+type surrealTagsUsage struct {
+	cache sync.Map
+	// dynamic is true when a type holding interfaces counts as using tags, as its values may
+	dynamic bool
+}
+
+var (
+	// tagTypes tells which types hold structs with surreal tags whatever their values
+	tagTypes = &surrealTagsUsage{}
+	// dynamicTagTypes tells which types may hold structs with surreal tags in interfaces
+	dynamicTagTypes = &surrealTagsUsage{dynamic: true}
+)
+
+func (u *surrealTagsUsage) uses(t reflect.Type) bool {
+	if used, ok := u.cache.Load(t); ok {
+		return used.(bool)
+	}
+	used := u.compute(t, map[reflect.Type]bool{})
+	u.cache.Store(t, used)
+	return used
+}
+
+func (u *surrealTagsUsage) compute(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+
+	if t.Implements(cborMarshalerType) || t.Implements(cborUnmarshalerType) ||
+		reflect.PointerTo(t).Implements(cborMarshalerType) || reflect.PointerTo(t).Implements(cborUnmarshalerType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return u.dynamic
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return u.compute(t.Elem(), visiting)
+	case reflect.Map:
+		return u.compute(t.Elem(), visiting)
+	case reflect.Struct:
+		info := getStructInfo(t)
+		if info.tagged {
+			return true
+		}
+		for _, f := range info.fields {
+			if u.compute(t.FieldByIndex(f.index).Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// encodeSurrealTags returns v with the structs having surreal tags replaced by maps, holding their
+// fields as named and encoded according to the tags. v is returned as is when it holds none, which
+// is found without allocating for untagged payloads such as query variables.
+func encodeSurrealTags(v interface{}) (interface{}, error) {
+	if !holdsSurrealTags(v) {
+		return v, nil
+	}
+	encoded, _, err := encodeValue(reflect.ValueOf(v))
+	return encoded, err
+}
+
+// holdsSurrealTags reports whether x holds a struct with surreal tags. The values of the types
+// held by query variables and RPC parameters are checked without reflection.
+func holdsSurrealTags(x interface{}) bool {
+	switch x := x.(type) {
+	case nil, string, bool, int, int64, uint64, float64, []byte, Table, RecordID, *RecordID:
+		return false
+	case []interface{}:
+		for _, item := range x {
+			if holdsSurrealTags(item) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		for _, item := range x {
+			if holdsSurrealTags(item) {
+				return true
+			}
+		}
+		return false
+	case map[interface{}]interface{}:
+		for _, item := range x {
+			if holdsSurrealTags(item) {
+				return true
+			}
+		}
+		return false
+	default:
+		return needsTagEncoding(reflect.ValueOf(x))
+	}
+}
+
+// needsTagEncoding reports whether v holds a struct with surreal tags, only walking the values of
+// v when its type holds interfaces whose values may be such structs.
+func needsTagEncoding(v reflect.Value) bool {
+	if tagTypes.uses(v.Type()) {
+		return true
+	}
+	if !dynamicTagTypes.uses(v.Type()) {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		return !v.IsNil() && holdsSurrealTags(v.Interface())
+	case reflect.Pointer:
+		return !v.IsNil() && needsTagEncoding(v.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if needsTagEncoding(v.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if needsTagEncoding(iter.Value()) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for _, f := range getStructInfo(v.Type()).fields {
+			if needsTagEncoding(v.FieldByIndex(f.index)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// encodeValue returns the value to encode in place of v, and whether it differs from v.
+func encodeValue(v reflect.Value) (interface{}, bool, error) {
+	if !needsTagEncoding(v) {
+		return v.Interface(), false, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return v.Interface(), false, nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return v.Interface(), false, nil
+		}
+		items := make([]interface{}, v.Len())
+		changed := false
+		for i := range items {
+			item, itemChanged, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, false, err
+			}
+			items[i] = item
+			changed = changed || itemChanged
+		}
+		if !changed {
+			return v.Interface(), false, nil
+		}
+		return items, true, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return v.Interface(), false, nil
+		}
+		items := make(map[interface{}]interface{}, v.Len())
+		changed := false
+		iter := v.MapRange()
+		for iter.Next() {
+			item, itemChanged, err := encodeValue(iter.Value())
+			if err != nil {
+				return nil, false, err
+			}
+			items[iter.Key().Interface()] = item
+			changed = changed || itemChanged
+		}
+		if !changed {
+			return v.Interface(), false, nil
+		}
+		return items, true, nil
+	case reflect.Struct:
+		return encodeStruct(v)
+	default:
+		return v.Interface(), false, nil
+	}
+}
+
+func encodeStruct(v reflect.Value) (interface{}, bool, error) {
+	info := getStructInfo(v.Type())
+
+	values := make([]interface{}, len(info.fields))
+	changed := info.tagged
+	// onlyDynamic is true when the fields that changed are all interfaces, in which case the
+	// struct is copied with their new values rather than replaced by a map
+	onlyDynamic := true
+	for i, f := range info.fields {
+		value, fieldChanged, err := encodeValue(v.FieldByIndex(f.index))
+		if err != nil {
+			return nil, false, err
+		}
+		values[i] = value
+		if fieldChanged {
+			changed = true
+			onlyDynamic = onlyDynamic && v.FieldByIndex(f.index).Kind() == reflect.Interface
+		}
+	}
+	if !changed {
+		return v.Interface(), false, nil
+	}
+
+	if !info.tagged && onlyDynamic {
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i, f := range info.fields {
+			if field := copied.FieldByIndex(f.index); field.Kind() == reflect.Interface && values[i] != nil {
+				field.Set(reflect.ValueOf(values[i]))
+			}
+		}
+		return copied.Interface(), true, nil
+	}
+
+	fields := make(map[string]interface{}, len(info.fields))
+	for i, f := range info.fields {
+		field := v.FieldByIndex(f.index)
+		switch {
+		case f.omitEmpty && isEmptyValue(field):
+			continue
+		case f.none && isNilValue(field):
+			fields[f.name] = None
+		case f.record:
+			if field.String() == "" {
+				fields[f.name] = nil
+				continue
+			}
+			id, err := parseRecordLink(field.String())
+			if err != nil {
+				return nil, false, fmt.Errorf("field %s of %s: %w", f.name, v.Type(), err)
+			}
+			fields[f.name] = id
+		default:
+			fields[f.name] = values[i]
+		}
+	}
+	return fields, true, nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// parseRecordLink parses the record id of a field with the record option. Like ParseRecordID, the
+// id is read as a string.
+func parseRecordLink(s string) (*RecordID, error) {
+	table, id, found := cutUnescaped(s, ':')
+	if !found {
+		return nil, fmt.Errorf("invalid record id %q, expected table:id", s)
+	}
+	return &RecordID{Table: unescapeIdent(table), ID: unescapeIdent(id)}, nil
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type taggedAddress struct {
+	City string `json:"city" surreal:"town"`
+}
+
+type taggedPerson struct {
+	ID      *RecordID       `json:"id" surreal:"id,omitempty"`
+	Name    string          `json:"name" surreal:"full_name"`
+	Manager string          `json:"manager" surreal:"manager,record"`
+	Nick    *string         `json:"nick" surreal:"nick,none"`
+	Secret  string          `json:"-" surreal:"secret"`
+	Token   string          `json:"token" surreal:"-"`
+	Age     int             `json:"age"`
+	Home    taggedAddress   `json:"home"`
+	Past    []taggedAddress `json:"past"`
+}
+
+func TestSurrealTagsEncode(t *testing.T) {
+	person := taggedPerson{
+		Name:    "Tobie",
+		Manager: "person:jaime",
+		Secret:  "s",
+		Token:   "t",
+		Age:     30,
+		Home:    taggedAddress{City: "London"},
+		Past:    []taggedAddress{{City: "Paris"}},
+	}
+
+	encoded, err := CborMarshaler{}.Marshal([]interface{}{person})
+	assert.NoError(t, err)
+
+	var decoded []map[string]cbor.RawMessage
+	assert.NoError(t, cbor.Unmarshal(encoded, &decoded))
+	fields := decoded[0]
+
+	assert.NotContains(t, fields, "id")
+	assert.NotContains(t, fields, "token")
+	assert.NotContains(t, fields, "name")
+	assert.Contains(t, fields, "full_name")
+	assert.Contains(t, fields, "secret")
+	assert.Contains(t, fields, "age")
+	assert.Equal(t, []byte{0xc6, 0xf6}, []byte(fields["nick"]), "a nil field with the none option must be NONE")
+
+	var manager RecordID
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(fields["manager"], &manager))
+	assert.Equal(t, NewRecordID("person", "jaime"), manager)
+
+	var home map[string]string
+	assert.NoError(t, cbor.Unmarshal(fields["home"], &home))
+	assert.Equal(t, map[string]string{"town": "London"}, home)
+
+	_, err = CborMarshaler{}.Marshal(taggedPerson{Manager: "jaime"})
+	assert.Error(t, err)
+}
+
+func TestSurrealTagsUntaggedPayload(t *testing.T) {
+	type untagged struct {
+		Name  string      `json:"name"`
+		Extra interface{} `json:"extra"`
+	}
+	var payload interface{} = []interface{}{"SELECT * FROM $tb", map[string]interface{}{
+		"tb":    Table("person"),
+		"id":    NewRecordID("person", "tobie"),
+		"tags":  []interface{}{"a", int64(1), 2.5},
+		"owner": untagged{Name: "Tobie", Extra: map[string]interface{}{"age": 30}},
+	}}
+
+	var err error
+	allocs := testing.AllocsPerRun(100, func() {
+		_, err = encodeSurrealTags(payload)
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, allocs, "payloads without surreal tags must not be walked with allocations")
+
+	// a tagged struct held by an interface is still encoded
+	tagged := []interface{}{map[string]interface{}{"owner": untagged{Extra: taggedAddress{City: "London"}}}}
+	encoded, err := encodeSurrealTags(tagged)
+	assert.NoError(t, err)
+	owner := encoded.([]interface{})[0].(map[interface{}]interface{})["owner"].(untagged)
+	assert.Equal(t, map[string]interface{}{"town": "London"}, owner.Extra)
+}
+
+func TestSurrealTagsDecode(t *testing.T) {
+	nick := "tobie"
+	person := taggedPerson{
+		ID:      &RecordID{Table: "person", ID: "tobie"},
+		Name:    "Tobie",
+		Manager: "person:⟨1⟩",
+		Nick:    &nick,
+		Secret:  "s",
+		Age:     30,
+		Home:    taggedAddress{City: "London"},
+		Past:    []taggedAddress{{City: "Paris"}},
+	}
+
+	encoded, err := CborMarshaler{}.Marshal(person)
+	assert.NoError(t, err)
+
+	var decoded struct {
+		Result []taggedPerson `json:"result"`
+	}
+	data, err := CborMarshaler{}.Marshal(map[string]interface{}{"result": []cbor.RawMessage{encoded}})
+	assert.NoError(t, err)
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+	assert.Equal(t, []taggedPerson{person}, decoded.Result)
+}
+
+func TestFieldNames(t *testing.T) {
+	names, err := FieldNames(reflect.TypeOf(&taggedPerson{}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "full_name", "manager", "nick", "secret", "age", "home", "past"}, names)
+
+	_, err = FieldNames(reflect.TypeOf(""))
+	assert.Error(t, err)
+}
+
+func TestCheckFieldPath(t *testing.T) {
+	person := reflect.TypeOf(&taggedPerson{})
+	for _, path := range []string{"full_name", "home.town", "past.town", "id.anything"} {
+		assert.NoError(t, CheckFieldPath(person, path), path)
+	}
+	for _, path := range []string{"name", "token", "home.city", "age.value", ""} {
+		assert.Error(t, CheckFieldPath(person, path), path)
+	}
+}
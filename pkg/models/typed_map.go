@@ -0,0 +1,203 @@
+package models
+
+import (
+	"math"
+	"math/big"
+	"time"
+)
+
+// TypedMap is an object decoded with a documented set of Go types, for the records whose shape
+// is not known in advance. Its values are:
+//   - string, bool, float64 and []byte
+//   - int64 for integers, or uint64 for the integers above math.MaxInt64
+//   - *big.Int for big integers and Decimal for decimals
+//   - time.Time for datetimes, in UTC, and time.Duration for durations
+//   - RecordID, Table, UUID and the geometry types
+//   - nil for NULL
+//   - []interface{} for arrays and TypedMap for objects, holding values of these types
+//
+// The fields holding NONE are left out, as NONE is the absence of a value.
+type TypedMap map[string]interface{}
+
+// UnmarshalCBOR decodes an object, converting its values to the types documented on TypedMap.
+func (m *TypedMap) UnmarshalCBOR(data []byte) error {
+	var v map[string]interface{}
+	if err := getCborDecoder().Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v == nil {
+		*m = nil
+		return nil
+	}
+
+	*m = typedObject(v)
+	return nil
+}
+
+// MarshalCBOR encodes the object, durations included.
+func (m TypedMap) MarshalCBOR() ([]byte, error) {
+	if m == nil {
+		return getCborEncoder().Marshal(nil)
+	}
+	return getCborEncoder().Marshal(untyped(m))
+}
+
+// GetString returns the value of key if it is a string.
+func (m TypedMap) GetString(key string) (string, bool) {
+	v, ok := m[key].(string)
+	return v, ok
+}
+
+// GetBool returns the value of key if it is a boolean.
+func (m TypedMap) GetBool(key string) (v, ok bool) {
+	v, ok = m[key].(bool)
+	return v, ok
+}
+
+// GetInt returns the value of key if it is an integer that fits in an int64.
+func (m TypedMap) GetInt(key string) (int64, bool) {
+	v, ok := m[key].(int64)
+	return v, ok
+}
+
+// GetFloat returns the value of key if it is a number, converting integers and decimals.
+func (m TypedMap) GetFloat(key string) (float64, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case Decimal:
+		f, _ := v.Float64()
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
+// GetDecimal returns the value of key if it is a decimal or an integer.
+func (m TypedMap) GetDecimal(key string) (Decimal, bool) {
+	switch v := m[key].(type) {
+	case Decimal:
+		return v, true
+	case int64:
+		return NewDecimal(v, 0), true
+	case uint64:
+		return NewDecimalFromBigInt(new(big.Int).SetUint64(v), 0), true
+	case *big.Int:
+		return NewDecimalFromBigInt(v, 0), true
+	default:
+		return Decimal{}, false
+	}
+}
+
+// GetTime returns the value of key if it is a datetime.
+func (m TypedMap) GetTime(key string) (time.Time, bool) {
+	v, ok := m[key].(time.Time)
+	return v, ok
+}
+
+// GetDuration returns the value of key if it is a duration.
+func (m TypedMap) GetDuration(key string) (time.Duration, bool) {
+	v, ok := m[key].(time.Duration)
+	return v, ok
+}
+
+// GetRecordID returns the value of key if it is a record id.
+func (m TypedMap) GetRecordID(key string) (RecordID, bool) {
+	v, ok := m[key].(RecordID)
+	return v, ok
+}
+
+// GetMap returns the value of key if it is an object.
+func (m TypedMap) GetMap(key string) (TypedMap, bool) {
+	v, ok := m[key].(TypedMap)
+	return v, ok
+}
+
+// GetSlice returns the value of key if it is an array.
+func (m TypedMap) GetSlice(key string) ([]interface{}, bool) {
+	v, ok := m[key].([]interface{})
+	return v, ok
+}
+
+func typedObject(object map[string]interface{}) TypedMap {
+	m := make(TypedMap, len(object))
+	for key, value := range object {
+		if _, none := value.(CustomNil); none {
+			continue
+		}
+		m[key] = typedValue(value)
+	}
+	return m
+}
+
+// typedValue converts a value decoded into an interface{} to the types documented on TypedMap.
+func typedValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if s, ok := key.(string); ok {
+				object[s] = value
+			}
+		}
+		return typedObject(object)
+	case map[string]interface{}:
+		return typedObject(v)
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, item := range v {
+			array[i] = typedValue(item)
+		}
+		return array
+	case CustomNil:
+		return nil
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v)
+		}
+		return v
+	case big.Int:
+		return &v
+	case DecimalString:
+		if d, err := ParseDecimal(string(v)); err == nil {
+			return d
+		}
+		return string(v)
+	case CustomDateTime:
+		return v.Time.UTC()
+	case time.Time:
+		return v.UTC()
+	case CustomDuration:
+		return v.Duration
+	case CustomDurationString:
+		return v.ToDuration()
+	default:
+		return v
+	}
+}
+
+// untyped converts the values of TypedMap back to types encoded like the database types.
+func untyped(value interface{}) interface{} {
+	switch v := value.(type) {
+	case TypedMap:
+		object := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			object[key] = untyped(value)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, item := range v {
+			array[i] = untyped(item)
+		}
+		return array
+	case time.Duration:
+		return CustomDuration{v}
+	default:
+		return v
+	}
+}
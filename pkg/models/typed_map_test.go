@@ -0,0 +1,80 @@
+package models
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedMap(t *testing.T) {
+	at := time.Date(2024, 10, 30, 12, 5, 0, 123, time.UTC)
+	data, err := CborMarshaler{}.Marshal(map[string]interface{}{
+		"id":       NewRecordID("user", "john"),
+		"name":     "John",
+		"age":      42,
+		"big":      uint64(math.MaxUint64),
+		"negative": -1,
+		"score":    1.5,
+		"balance":  NewDecimal(1999, -2),
+		"created":  CustomDateTime{at},
+		"ttl":      CustomDuration{time.Hour},
+		"deleted":  None,
+		"parent":   nil,
+		"address":  map[string]interface{}{"city": "London", "zip": None},
+		"tags":     []interface{}{"a", 1, None},
+	})
+	assert.NoError(t, err)
+
+	var m TypedMap
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &m))
+
+	id, ok := m.GetRecordID("id")
+	assert.True(t, ok)
+	assert.Equal(t, NewRecordID("user", "john"), id)
+	name, _ := m.GetString("name")
+	assert.Equal(t, "John", name)
+	age, ok := m.GetInt("age")
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), age)
+	assert.Equal(t, uint64(math.MaxUint64), m["big"])
+	assert.Equal(t, int64(-1), m["negative"])
+	score, _ := m.GetFloat("score")
+	assert.Equal(t, 1.5, score)
+	balance, ok := m.GetDecimal("balance")
+	assert.True(t, ok)
+	assert.Equal(t, "19.99", balance.String())
+	created, ok := m.GetTime("created")
+	assert.True(t, ok)
+	assert.Equal(t, at, created)
+	ttl, ok := m.GetDuration("ttl")
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, ttl)
+
+	assert.NotContains(t, m, "deleted", "NONE is the absence of a value")
+	assert.Contains(t, m, "parent")
+	assert.Nil(t, m["parent"])
+	address, ok := m.GetMap("address")
+	assert.True(t, ok)
+	assert.Equal(t, TypedMap{"city": "London"}, address)
+	tags, _ := m.GetSlice("tags")
+	assert.Equal(t, []interface{}{"a", int64(1), nil}, tags)
+
+	_, ok = m.GetInt("name")
+	assert.False(t, ok)
+	_, ok = m.GetString("missing")
+	assert.False(t, ok)
+
+	// durations are encoded back as durations
+	data, err = CborMarshaler{}.Marshal(m)
+	assert.NoError(t, err)
+	var again TypedMap
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &again))
+	assert.Equal(t, m, again)
+
+	d, ok := TypedMap{"n": new(big.Int).SetInt64(5)}.GetDecimal("n")
+	assert.True(t, ok)
+	assert.Equal(t, "5", d.String())
+}
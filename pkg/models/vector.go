@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// VectorElement is the type of the elements of a Vector.
+type VectorElement interface {
+	float32 | float64
+}
+
+// Vector is an embedding, stored as an array of floats by SurrealDB and searched with vector
+// indexes and the knn operator.
+type Vector[T VectorElement] []T
+
+var (
+	vectorEncModeOnce sync.Once
+	vectorEncMode     cbor.EncMode
+)
+
+// MarshalCBOR encodes the vector as an array of floats, each in the shortest form that holds its
+// value exactly, which is often half the size of float64 values.
+func (v Vector[T]) MarshalCBOR() ([]byte, error) {
+	vectorEncModeOnce.Do(func() {
+		em, err := cbor.EncOptions{ShortestFloat: cbor.ShortestFloat16}.EncMode()
+		if err != nil {
+			panic(err)
+		}
+		vectorEncMode = em
+	})
+	if v == nil {
+		return vectorEncMode.Marshal(nil)
+	}
+	return vectorEncMode.Marshal([]T(v))
+}
+
+// UnmarshalCBOR decodes an array of numbers, integers included.
+func (v *Vector[T]) UnmarshalCBOR(data []byte) error {
+	var values []interface{}
+	if err := getCborDecoder().Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if values == nil {
+		*v = nil
+		return nil
+	}
+
+	vector := make(Vector[T], len(values))
+	for i, value := range values {
+		switch n := value.(type) {
+		case float64:
+			vector[i] = T(n)
+		case float32:
+			vector[i] = T(n)
+		case uint64:
+			vector[i] = T(n)
+		case int64:
+			vector[i] = T(n)
+		default:
+			return fmt.Errorf("cannot decode %T into an element of a vector", value)
+		}
+	}
+	*v = vector
+	return nil
+}
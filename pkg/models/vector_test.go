@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVector(t *testing.T) {
+	v := Vector[float64]{0.5, 1, -2.25, 0.1}
+	data, err := CborMarshaler{}.Marshal(v)
+	assert.NoError(t, err)
+	// 0.5, 1 and -2.25 fit in half precision floats, 0.1 does not fit in a float32
+	assert.Equal(t, 1+3*3+9, len(data))
+
+	var decoded Vector[float64]
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+	assert.Equal(t, v, decoded)
+
+	v32 := Vector[float32]{0.1, 3}
+	data, err = CborMarshaler{}.Marshal(map[string]interface{}{"embedding": v32})
+	assert.NoError(t, err)
+	var record struct {
+		Embedding Vector[float32] `json:"embedding"`
+	}
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &record))
+	assert.Equal(t, v32, record.Embedding)
+
+	// vectors inserted as integers
+	data, err = cbor.Marshal([]interface{}{1, -2, 2.5})
+	assert.NoError(t, err)
+	assert.NoError(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+	assert.Equal(t, Vector[float64]{1, -2, 2.5}, decoded)
+
+	data, err = cbor.Marshal([]interface{}{"a"})
+	assert.NoError(t, err)
+	assert.Error(t, CborUnmarshaler{}.Unmarshal(data, &decoded))
+}
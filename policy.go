@@ -0,0 +1,82 @@
+package surrealdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// MethodPolicy tunes the requests of an RPC method, see WithMethodPolicies.
+type MethodPolicy struct {
+	// Timeout bounds each attempt of a request. A request timing out fails with an error wrapping
+	// constants.ErrTimeout, which Retry retries. No timeout when zero, besides the context of the DB.
+	Timeout time.Duration
+	// Retry replaces the retry policy of the DB for the method, see DB.WithRetryPolicy. Unlike the
+	// policy of the DB, it retries the method even when it writes data: setting it declares that
+	// retrying the method is safe.
+	Retry *RetryPolicy
+}
+
+// WithMethodPolicies sets the timeout and retries of requests by RPC method, such as query,
+// select or create, so that they are tuned in one place:
+//
+//	surrealdb.WithMethodPolicies(map[string]surrealdb.MethodPolicy{
+//		"query":  {Timeout: 30 * time.Second},
+//		"select": {Timeout: 2 * time.Second, Retry: &surrealdb.RetryPolicy{MaxAttempts: 5, InitialBackoff: 50 * time.Millisecond}},
+//	})
+func WithMethodPolicies(policies map[string]MethodPolicy) Option {
+	return func(c *config) error {
+		c.methodPolicies = make(map[string]MethodPolicy, len(policies))
+		for method, policy := range policies {
+			if policy.Timeout < 0 {
+				return fmt.Errorf("timeout of method %s must not be negative", method)
+			}
+			if policy.Retry != nil {
+				if policy.Retry.MaxAttempts < 1 {
+					return fmt.Errorf("retry policy of method %s must make at least 1 attempt", method)
+				}
+				retry := *policy.Retry
+				policy.Retry = &retry
+			}
+			c.methodPolicies[method] = policy
+		}
+		return nil
+	}
+}
+
+// retryPolicyFor returns the retry policy of method, and whether it retries method whatever its
+// idempotency.
+func (db *DB) retryPolicyFor(method string) (policy *RetryPolicy, anyMethod bool) {
+	if p, ok := db.methodPolicies[method]; ok && p.Retry != nil {
+		return p.Retry, true
+	}
+	return db.retryPolicy, false
+}
+
+// attemptContext returns the context of an attempt of a request of method, ctx bounded by the
+// timeout of the method. The context is nil when neither ctx nor the method set one.
+func (db *DB) attemptContext(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	timeout := db.methodPolicies[method].Timeout
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// attemptError returns err, converted to a timeout when the attempt failed because of the timeout
+// of its method rather than ctx, the context of the request.
+func (db *DB) attemptError(ctx context.Context, method string, err error) error {
+	if !errors.Is(err, context.DeadlineExceeded) || db.methodPolicies[method].Timeout <= 0 {
+		return err
+	}
+	if ctx != nil && ctx.Err() != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s took longer than %s", constants.ErrTimeout, method, db.methodPolicies[method].Timeout)
+}
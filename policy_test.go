@@ -0,0 +1,62 @@
+package surrealdb_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+	"github.com/surrealdb/surrealdb.go/pkg/logger"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestMethodPolicies(t *testing.T) {
+	var lock sync.Mutex
+	calls := map[string]int{}
+	count := func(method string) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return calls[method]
+	}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		calls[req.Method]++
+		call := calls[req.Method]
+		lock.Unlock()
+		if call == 1 || req.Method == "query" {
+			// too slow for the timeout of the method
+			time.Sleep(200 * time.Millisecond)
+		}
+		return map[string]interface{}{}, nil
+	})
+
+	retry := &surrealdb.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithLogger(logger.New(slog.NewTextHandler(io.Discard, nil))),
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithMethodPolicies(map[string]surrealdb.MethodPolicy{
+			"create": {Timeout: 50 * time.Millisecond, Retry: retry},
+			"query":  {Timeout: 50 * time.Millisecond},
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = surrealdb.Create[map[string]interface{}](db, models.Table("users"), map[string]interface{}{})
+	require.NoError(t, err, "the policy of create retries it after its timeout")
+	require.Equal(t, 2, count("create"))
+
+	_, err = surrealdb.Query[interface{}](db, "SLEEP 1s", nil)
+	require.ErrorIs(t, err, constants.ErrTimeout)
+	require.Equal(t, 1, count("query"))
+
+	_, err = surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithMethodPolicies(map[string]surrealdb.MethodPolicy{"select": {Retry: &surrealdb.RetryPolicy{}}}))
+	require.Error(t, err)
+}
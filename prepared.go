@@ -0,0 +1,181 @@
+package surrealdb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// builtinParams are the parameters defined by the server
+var builtinParams = map[string]bool{
+	"access": true, "after": true, "auth": true, "before": true, "event": true, "input": true,
+	"parent": true, "scope": true, "session": true, "this": true, "token": true, "value": true,
+}
+
+// Prepared is a query parsed once and run many times with different variables, see Prepare.
+type Prepared[TResult any] struct {
+	sql    string
+	params []string
+	// encoded is the query encoded once, sent in place of sql by the DBs encoding requests with
+	// the models.CborMarshaler
+	encoded *models.EncodedString
+	// plans are the decode plans of the results, by the options of the models.CborUnmarshaler
+	// decoding them
+	plans sync.Map
+}
+
+// Prepare parses a query to be run many times with Exec. The $parameters of the query are
+// extracted once, so that Exec can check the variables it is given without parsing the query
+// again. Parameters defined by the query with LET, and those defined by the server such as
+// $auth or $this, need no variable. The query is encoded once too, and the way its results are
+// decoded is worked out on the first Exec, for the DBs using the default codec.
+//
+//	adults := surrealdb.MustPrepare[[]User]("SELECT * FROM user WHERE age > $min")
+//	res, err := adults.Exec(ctx, db, map[string]interface{}{"min": 18})
+func Prepare[TResult any](sql string) (*Prepared[TResult], error) {
+	if strings.TrimSpace(sql) == "" {
+		return nil, fmt.Errorf("no query to prepare")
+	}
+	statements := splitStatements(sql)
+
+	defined := map[string]bool{}
+	seen := map[string]bool{}
+	var params []string
+	for _, stmt := range statements {
+		for i, param := range stmt.params {
+			if stmt.keyword == "LET" && i == 0 {
+				defined[param] = true
+				continue
+			}
+			if defined[param] || builtinParams[param] || seen[param] {
+				continue
+			}
+			seen[param] = true
+			params = append(params, param)
+		}
+	}
+	sort.Strings(params)
+
+	encoded, err := models.NewEncodedString(sql)
+	if err != nil {
+		return nil, err
+	}
+	return &Prepared[TResult]{sql: sql, params: params, encoded: encoded}, nil
+}
+
+// MustPrepare is Prepare, panicking when the query cannot be prepared. It simplifies the
+// preparation of queries in package variables.
+func MustPrepare[TResult any](sql string) *Prepared[TResult] {
+	p, err := Prepare[TResult](sql)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// SQL returns the prepared query.
+func (p *Prepared[TResult]) SQL() string {
+	return p.sql
+}
+
+// Params returns the names of the parameters the query expects, in alphabetical order.
+func (p *Prepared[TResult]) Params() []string {
+	return append([]string{}, p.params...)
+}
+
+// Exec runs the query with vars, like Query with a request bounded by ctx. Every parameter of the
+// query must be given a variable, either in vars, in the session with DB.Let, or as a default
+// variable with WithDefaultVars or DB.SetDefaultVar, and every variable of vars must be used by
+// the query.
+func (p *Prepared[TResult]) Exec(ctx context.Context, db *DB, vars map[string]interface{}) (*[]QueryResult[TResult], error) {
+	if err := p.checkVars(db, vars); err != nil {
+		return nil, err
+	}
+	if vars == nil {
+		vars = map[string]interface{}{}
+	}
+	db = db.WithContext(ctx)
+
+	var sql interface{} = p.sql
+	if _, ok := db.marshaler.(models.CborMarshaler); ok {
+		sql = p.encoded
+	}
+	unmarshaler, ok := db.con.GetUnmarshaler().(models.CborUnmarshaler)
+	if !ok {
+		var res connection.RPCResponse[[]QueryResult[TResult]]
+		if err := db.send(&res, "query", sql, vars); err != nil {
+			return nil, err
+		}
+		return res.Result, nil
+	}
+
+	var res connection.RPCResponse[cbor.RawMessage]
+	if err := db.send(&res, "query", sql, vars); err != nil {
+		return nil, err
+	}
+	if res.Result == nil {
+		return nil, nil
+	}
+	var results []QueryResult[TResult]
+	if err := connection.DecodeResult(p.plan(unmarshaler), "query", *res.Result, &results); err != nil {
+		db.stats.record(err)
+		return nil, err
+	}
+	return &results, nil
+}
+
+// plan returns the decode plan of the results with the options of unmarshaler.
+func (p *Prepared[TResult]) plan(unmarshaler models.CborUnmarshaler) *models.DecodePlan {
+	if plan, ok := p.plans.Load(unmarshaler.Options); ok {
+		return plan.(*models.DecodePlan)
+	}
+	plan, _ := p.plans.LoadOrStore(unmarshaler.Options,
+		unmarshaler.Plan(reflect.TypeOf((*[]QueryResult[TResult])(nil)).Elem()))
+	return plan.(*models.DecodePlan)
+}
+
+func (p *Prepared[TResult]) checkVars(db *DB, vars map[string]interface{}) error {
+	var missing, unused []string
+
+	session := db.SessionState().Variables
+	// the default variables are replaced rather than modified, so they can be read once locked
+	db.defaultVarsLock.RLock()
+	defaults := db.defaultVars
+	db.defaultVarsLock.RUnlock()
+	for _, param := range p.params {
+		_, inVars := vars[param]
+		_, inSession := session[param]
+		_, inDefaults := defaults[param]
+		if !inVars && !inSession && !inDefaults {
+			missing = append(missing, "$"+param)
+		}
+	}
+
+	for name := range vars {
+		if !p.expects(name) {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+
+	switch {
+	case len(missing) > 0:
+		return fmt.Errorf("missing variables for %s", strings.Join(missing, ", "))
+	case len(unused) > 0:
+		return fmt.Errorf("variables %s are not used by the query", strings.Join(unused, ", "))
+	default:
+		return nil
+	}
+}
+
+func (p *Prepared[TResult]) expects(name string) bool {
+	i := sort.SearchStrings(p.params, name)
+	return i < len(p.params) && p.params[i] == name
+}
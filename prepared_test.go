@@ -0,0 +1,77 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func (s *SurrealDBTestSuite) TestPreparedExec() {
+	_, err := surrealdb.Create[testUser](s.db, models.NewRecordID("users", "john"), testUser{Username: "john"})
+	s.Require().NoError(err)
+
+	byName := surrealdb.MustPrepare[[]testUser]("SELECT * FROM users WHERE username = $name")
+
+	ctx := context.Background()
+	res, err := byName.Exec(ctx, s.db, map[string]interface{}{"name": "john"})
+	s.Require().NoError(err)
+	s.Len((*res)[0].Result, 1)
+
+	_, err = byName.Exec(ctx, s.db, map[string]interface{}{})
+	s.ErrorContains(err, "$name")
+
+	_, err = byName.Exec(ctx, s.db, map[string]interface{}{"name": "john", "nmae": "john"})
+	s.ErrorContains(err, "nmae")
+}
+
+func TestPrepare(t *testing.T) {
+	p, err := surrealdb.Prepare[[]testUser](`
+		-- $commented is not a parameter
+		LET $adult = 18;
+		SELECT * FROM users WHERE age > $adult AND name != '$quoted' AND owner = $auth.id AND team = $team;
+		UPDATE users SET seen = $now WHERE team = $team`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"now", "team"}, p.Params())
+
+	_, err = surrealdb.Prepare[[]testUser]("  ")
+	require.Error(t, err)
+}
+
+func TestPreparedDefaultVars(t *testing.T) {
+	queries := make(chan []interface{}, 1)
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		queries <- req.Params
+
+		users := []interface{}{map[string]interface{}{"id": models.NewRecordID("users", "john"), "username": "john"}}
+		return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": users}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithDefaultVars(map[string]interface{}{"tenant": "acme"}),
+	)
+	require.NoError(t, err)
+
+	byTenant := surrealdb.MustPrepare[[]testUser]("SELECT * FROM users WHERE tenant = $tenant AND team = $team")
+	ctx := context.Background()
+	_, err = byTenant.Exec(ctx, db, map[string]interface{}{})
+	require.ErrorContains(t, err, "$team")
+	require.NotContains(t, err.Error(), "$tenant")
+
+	require.NoError(t, db.SetDefaultVar("team", "core"))
+	for i := 0; i < 2; i++ {
+		res, err := byTenant.Exec(ctx, db, nil)
+		require.NoError(t, err)
+		john := models.NewRecordID("users", "john")
+		require.Equal(t, []testUser{{ID: &john, Username: "john"}}, (*res)[0].Result)
+
+		params := <-queries
+		require.Equal(t, byTenant.SQL(), params[0])
+		require.Equal(t, map[interface{}]interface{}{"tenant": "acme", "team": "core"}, params[1])
+	}
+}
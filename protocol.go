@@ -0,0 +1,97 @@
+package surrealdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// protocol is the RPC protocol of the SurrealDB versions starting with since: the RPC method
+// called for each method of the client. The client keeps its own method names, so that a server
+// adding or renaming methods only needs a new protocol rather than a change of the DB API.
+// Parameters are CBOR encoded the same way by every protocol so far.
+type protocol struct {
+	since   string
+	methods map[string]string
+}
+
+// protocols are the known protocols, newest first.
+var protocols = []protocol{
+	{since: "2.0.0", methods: rpcMethods(
+		"ping", "use", "info", "version", "signup", "signin", "authenticate", "invalidate", "let", "unset",
+		"live", "kill", "query", "run", "graphql", "select", "create", "insert", "insert_relation",
+		"update", "upsert", "merge", "patch", "delete", "relate",
+	)},
+	{since: "1.5.0", methods: rpcMethods(
+		"ping", "use", "info", "version", "signup", "signin", "authenticate", "invalidate", "let", "unset",
+		"live", "kill", "query", "run", "select", "create", "insert", "update", "merge", "patch", "delete",
+		"relate",
+	)},
+	{since: "1.0.0", methods: rpcMethods(
+		"ping", "use", "info", "version", "signup", "signin", "authenticate", "invalidate", "let", "unset",
+		"live", "kill", "query", "select", "create", "insert", "update", "merge", "patch", "delete",
+	)},
+}
+
+// rpcMethods returns the methods of a protocol whose RPC methods have the name of the client
+// methods.
+func rpcMethods(names ...string) map[string]string {
+	methods := make(map[string]string, len(names))
+	for _, name := range names {
+		methods[name] = name
+	}
+	return methods
+}
+
+// protocolFor returns the protocol of a server version, such as 1.5.4, v2.0.0 or the
+// surrealdb-2.1.0 reported by the version method.
+func protocolFor(version string) (protocol, error) {
+	version = strings.TrimPrefix(version, "surrealdb-")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		// pre-releases and builds share the protocol of their version
+		version = version[:i]
+	}
+
+	for _, p := range protocols {
+		cmp, err := CompareVersions(version, p.since)
+		if err != nil {
+			return protocol{}, err
+		}
+		if cmp >= 0 {
+			return p, nil
+		}
+	}
+	return protocol{}, fmt.Errorf("SurrealDB %s is not supported, the oldest supported version is %s",
+		version, protocols[len(protocols)-1].since)
+}
+
+// rpcMethod returns the RPC method called for method. Methods unknown to every protocol, such as
+// methods added by a server newer than the client, are called as is.
+func (p protocol) rpcMethod(method string) (string, error) {
+	if p.methods == nil {
+		// no server version set: the newest protocol, without restricting the methods
+		p = protocols[0]
+	} else if _, ok := p.methods[method]; !ok && protocols[0].methods[method] != "" {
+		return "", fmt.Errorf("%w: %s requires a newer SurrealDB than %s", constants.ErrMethodNotAvailable, method, p.since)
+	}
+
+	if rpc, ok := p.methods[method]; ok {
+		return rpc, nil
+	}
+	return method, nil
+}
+
+// WithServerVersion sets the version of the server, such as 1.5.4, so that requests are sent
+// with the RPC protocol of that version. The methods the server lacks then fail with
+// constants.ErrMethodNotAvailable before being sent. The newest protocol is used by default.
+func WithServerVersion(version string) Option {
+	return func(c *config) error {
+		p, err := protocolFor(version)
+		if err != nil {
+			return err
+		}
+		c.protocol = p
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package surrealdb_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/contrib/devenv"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestServerVersion(t *testing.T) {
+	var lock sync.Mutex
+	var methods []string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		methods = append(methods, req.Method)
+		lock.Unlock()
+		return map[string]interface{}{}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithServerVersion("1.4.2"),
+	)
+	require.NoError(t, err)
+
+	_, err = surrealdb.Upsert[map[string]interface{}](db, models.NewRecordID("user", "a"), map[string]interface{}{})
+	require.ErrorIs(t, err, constants.ErrMethodNotAvailable, "upsert was added in SurrealDB 2.0")
+	require.ErrorIs(t, surrealdb.Relate(db, &surrealdb.Relationship{}), constants.ErrMethodNotAvailable)
+	_, err = surrealdb.Select[map[string]interface{}](db, models.NewRecordID("user", "a"))
+	require.NoError(t, err)
+	require.NoError(t, db.SendBatch(context.Background(), []connection.RPCRequest{{Method: "newer_than_the_client"}})[0].Err,
+		"methods unknown to the client are sent as is")
+
+	lock.Lock()
+	require.Equal(t, []string{"select", "newer_than_the_client"}, methods)
+	lock.Unlock()
+
+	for _, version := range []string{"surrealdb-2.1.0", "v2.0.0-beta.2", "1.5.0"} {
+		_, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithServerVersion(version))
+		require.NoError(t, err, version)
+	}
+	for _, version := range []string{"0.3.0", "latest"} {
+		_, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithServerVersion(version))
+		require.Error(t, err, version)
+	}
+}
+
+// TestProtocolConformance checks the RPC protocol of each server version listed in
+// SURREALDB_CONFORMANCE_VERSIONS against a server of that version started in Docker: the methods
+// of the protocol must exist on the server, and the methods it lacks must not.
+func TestProtocolConformance(t *testing.T) {
+	versions := os.Getenv("SURREALDB_CONFORMANCE_VERSIONS")
+	if versions == "" {
+		t.Skip("SURREALDB_CONFORMANCE_VERSIONS is not set, such as v1.5.4,v2.1.0")
+	}
+
+	// invalidate comes last, as it signs out
+	methods := []string{
+		"ping", "use", "info", "version", "signup", "signin", "authenticate", "let", "unset", "live", "kill",
+		"query", "run", "graphql", "select", "create", "insert", "insert_relation", "update", "upsert",
+		"merge", "patch", "delete", "relate", "invalidate",
+	}
+	for _, version := range strings.Split(versions, ",") {
+		t.Run(version, func(t *testing.T) {
+			ctx := context.Background()
+			server, err := devenv.Start(ctx, devenv.Options{Version: version})
+			require.NoError(t, err)
+			defer func() { _ = server.Stop() }()
+
+			pinned, err := server.Connect(ctx, surrealdb.WithNamespace("test", "test"), surrealdb.WithServerVersion(version))
+			require.NoError(t, err)
+			unpinned, err := server.Connect(ctx, surrealdb.WithNamespace("test", "test"))
+			require.NoError(t, err)
+
+			for _, method := range methods {
+				// the parameters are missing, so the server answers with an error either way
+				err := pinned.SendBatch(ctx, []connection.RPCRequest{{Method: method}})[0].Err
+				if errors.Is(err, constants.ErrMethodNotAvailable) {
+					err = unpinned.SendBatch(ctx, []connection.RPCRequest{{Method: method}})[0].Err
+					require.ErrorIs(t, err, constants.ErrMethodNotFound, "%s is excluded from the protocol but exists", method)
+				} else {
+					require.False(t, errors.Is(err, constants.ErrMethodNotFound), "%s is in the protocol but does not exist", method)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package surrealdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// RetryPolicy retries idempotent requests that failed with a transient error, such as a timeout,
+// a reset or closed connection, a transaction conflict or a busy server, waiting an exponential
+// backoff with jitter between attempts. When a throttled request came with a Retry-After wait,
+// it waits at least as long, and gives up instead when the wait would outlast the deadline of the
+// context of the DB.
+//
+// The idempotent methods are select, info, version, ping, use, let, unset and authenticate.
+// Requests that write data, such as create or merge, are never retried, as they may have been
+// applied by the server before the failure.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. It doubles for each following retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts. A throttled request asking to wait longer is not
+	// retried, and fails with its connection.ThrottledError.
+	MaxBackoff time.Duration
+	// RetryQueries retries query requests too. Only enable it when all queries are read-only or
+	// idempotent.
+	RetryQueries bool
+}
+
+// DefaultRetryPolicy makes up to 3 attempts, waiting 100ms then 200ms, with jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+var idempotentMethods = map[string]bool{
+	"select":       true,
+	"info":         true,
+	"version":      true,
+	"ping":         true,
+	"use":          true,
+	"let":          true,
+	"unset":        true,
+	"authenticate": true,
+}
+
+// WithRetryPolicy returns a handle of db retrying its idempotent requests according to policy, as
+// do the handles derived from it with WithContext. Like WithContext, the handle shares the
+// connection and session of db, whose own retry policy is left unchanged.
+func (db *DB) WithRetryPolicy(policy RetryPolicy) *DB {
+	return &DB{client: db.client, ctx: db.ctx, retryPolicy: &policy}
+}
+
+// retries reports whether a request failing with err should be retried after attempt attempts.
+func (p *RetryPolicy) retries(method string, attempt int, err error) bool {
+	if p == nil || !idempotentMethods[method] && !(method == "query" && p.RetryQueries) {
+		return false
+	}
+	return p.retriesAfter(attempt, err)
+}
+
+// retriesAfter is retries, for a request of a method known to be safe to retry.
+func (p *RetryPolicy) retriesAfter(attempt int, err error) bool {
+	if p == nil || err == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+	return IsTransient(err)
+}
+
+// backoffAfter returns the wait before the retry following attempt, which failed with err: the
+// backoff, or the wait asked for by a throttled response when it is longer. ok is false when that
+// wait is longer than MaxBackoff, and the request must not be retried.
+func (p *RetryPolicy) backoffAfter(attempt int, err error) (wait time.Duration, ok bool) {
+	wait = p.backoff(attempt)
+	var throttled *connection.ThrottledError
+	if errors.As(err, &throttled) && throttled.RetryAfter > wait {
+		if p.MaxBackoff > 0 && throttled.RetryAfter > p.MaxBackoff {
+			return 0, false
+		}
+		return throttled.RetryAfter, true
+	}
+	return wait, true
+}
+
+// backoff returns the wait before the retry following attempt, with full jitter.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.InitialBackoff << (attempt - 1)
+	if wait <= 0 || (p.MaxBackoff > 0 && wait > p.MaxBackoff) {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1)) //nolint:gosec
+}
+
+// IsTransient reports whether err is a failure that may not happen again when the request is
+// retried, such as a timeout, a network failure, a transaction conflict or a busy server.
+func IsTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, constants.ErrTimeout),
+		errors.Is(err, constants.ErrTransactionConflict),
+		errors.Is(err, constants.ErrThrottled),
+		errors.Is(err, net.ErrClosed),
+		errors.Is(err, io.ErrClosedPipe),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.EPIPE),
+		errors.As(err, &netErr):
+		return true
+	}
+	return strings.Contains(err.Error(), "channel closed")
+}
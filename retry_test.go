@@ -0,0 +1,191 @@
+package surrealdb_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+	"github.com/surrealdb/surrealdb.go/pkg/logger"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var lock sync.Mutex
+	var selectCalls, createCalls int
+	counts := func() (int, int) {
+		lock.Lock()
+		defer lock.Unlock()
+		return selectCalls, createCalls
+	}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		calls := &selectCalls
+		if req.Method == "create" {
+			calls = &createCalls
+		}
+		*calls++
+		first := *calls == 1
+		lock.Unlock()
+		if first {
+			// drop the connection on the first attempt
+			conn, _, err := req.Writer.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+			return nil, mock.ErrResponseWritten
+		}
+		return []interface{}{}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithLogger(logger.New(slog.NewTextHandler(io.Discard, nil))),
+		surrealdb.WithNamespace("test", "test"),
+	)
+	require.NoError(t, err)
+	db = db.WithRetryPolicy(surrealdb.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	t.Run("idempotent requests are retried", func(t *testing.T) {
+		_, err := surrealdb.Select[[]map[string]interface{}](db, models.Table("users"))
+		require.NoError(t, err)
+		selects, _ := counts()
+		require.Equal(t, 2, selects)
+	})
+
+	t.Run("writes are not retried", func(t *testing.T) {
+		_, err := surrealdb.Create[map[string]interface{}](db, models.Table("users"), map[string]interface{}{})
+		require.Error(t, err)
+		require.True(t, surrealdb.IsTransient(err))
+		_, creates := counts()
+		require.Equal(t, 1, creates)
+	})
+}
+
+func TestWithRetryPolicy_Concurrent(t *testing.T) {
+	var calls atomic.Int64
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		if req.Method == "select" {
+			calls.Add(1)
+			// drop the connection on every attempt
+			conn, _, err := req.Writer.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+			return nil, mock.ErrResponseWritten
+		}
+		return nil, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithLogger(logger.New(slog.NewTextHandler(io.Discard, nil))),
+		surrealdb.WithNamespace("test", "test"),
+	)
+	require.NoError(t, err)
+
+	const n = 8
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			retrying := db.WithRetryPolicy(surrealdb.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+			_, err := surrealdb.Select[[]map[string]interface{}](retrying.WithContext(context.Background()), models.Table("users"))
+			require.Error(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := surrealdb.Select[[]map[string]interface{}](db, models.Table("users"))
+			require.Error(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// the handles with a policy made two attempts each, and db itself was never retried
+	require.Equal(t, int64(n*2+n), calls.Load())
+}
+
+func TestRetryThrottled(t *testing.T) {
+	var lock sync.Mutex
+	var calls int
+	var retryAfter string
+	reset := func(wait string) {
+		lock.Lock()
+		defer lock.Unlock()
+		calls, retryAfter = 0, wait
+	}
+	count := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return calls
+	}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		calls++
+		first, wait := calls == 1, retryAfter
+		lock.Unlock()
+		if first {
+			req.Writer.Header().Set("Retry-After", wait)
+			req.Writer.WriteHeader(http.StatusTooManyRequests)
+			_, _ = req.Writer.Write([]byte("Too Many Requests"))
+			return nil, mock.ErrResponseWritten
+		}
+		return []interface{}{}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithLogger(logger.New(slog.NewTextHandler(io.Discard, nil))),
+		surrealdb.WithNamespace("test", "test"),
+	)
+	require.NoError(t, err)
+
+	t.Run("without retry policy", func(t *testing.T) {
+		reset("7")
+		_, err := surrealdb.Select[[]map[string]interface{}](db, models.Table("users"))
+		require.ErrorIs(t, err, constants.ErrThrottled)
+		var throttled *connection.ThrottledError
+		require.ErrorAs(t, err, &throttled)
+		require.Equal(t, http.StatusTooManyRequests, throttled.StatusCode)
+		require.Equal(t, 7*time.Second, throttled.RetryAfter)
+	})
+
+	db = db.WithRetryPolicy(surrealdb.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	t.Run("retried after the wait", func(t *testing.T) {
+		reset("0")
+		_, err := surrealdb.Select[[]map[string]interface{}](db, models.Table("users"))
+		require.NoError(t, err)
+		require.Equal(t, 2, count())
+	})
+
+	t.Run("not retried past the deadline", func(t *testing.T) {
+		reset("60")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		start := time.Now()
+		_, err := surrealdb.Select[[]map[string]interface{}](db.WithContext(ctx), models.Table("users"))
+		require.ErrorIs(t, err, constants.ErrThrottled)
+		require.Equal(t, 1, count())
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("not retried past the max backoff", func(t *testing.T) {
+		db := db.WithRetryPolicy(surrealdb.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second})
+		reset("5")
+		start := time.Now()
+		_, err := surrealdb.Select[[]map[string]interface{}](db, models.Table("users"))
+		require.ErrorIs(t, err, constants.ErrThrottled)
+		var throttled *connection.ThrottledError
+		require.ErrorAs(t, err, &throttled)
+		require.Equal(t, 5*time.Second, throttled.RetryAfter)
+		require.Equal(t, 1, count())
+		require.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,97 @@
+package surrealdb
+
+import (
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// SelectInto is Select decoding the result into dst instead of a new value, to reuse the memory
+// of dst across calls: the backing arrays of the slices of dst are kept, and the rows are decoded
+// in place when they fit. Services selecting many rows at a high rate allocate much less by
+// calling SelectInto with the same dst each time:
+//
+//	var users []User
+//	for range ticker.C {
+//		if err := surrealdb.SelectInto(db, &users, models.Table("user")); err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// dst is reset before decoding, so no value of a previous call is left in it. The values decoded
+// before must not be used once dst is reused, as they may be overwritten.
+func SelectInto[TResult any, TWhat TablesOrRecords](db *DB, dst *TResult, what TWhat) error {
+	if isMultiTarget(what) {
+		return queryInto(db, dst, "SELECT * FROM $what", map[string]interface{}{"what": what})
+	}
+
+	Reset(dst)
+	res := connection.RPCResponse[TResult]{Result: dst}
+	return db.send(&res, "select", what)
+}
+
+// QueryInto is Query decoding the results into dst instead of a new slice, reusing the memory of
+// dst across calls the same way as SelectInto.
+func QueryInto[TResult any](db *DB, dst *[]QueryResult[TResult], sql string, vars map[string]interface{}) error {
+	Reset(dst)
+	res := connection.RPCResponse[[]QueryResult[TResult]]{Result: dst}
+	return db.send(&res, "query", sql, vars)
+}
+
+// queryInto runs sql, which must hold a single statement, and decodes the statement result into dst.
+func queryInto[TResult any](db *DB, dst *TResult, sql string, vars map[string]interface{}) error {
+	var res connection.RPCResponse[[]QueryResult[cbor.RawMessage]]
+	if err := db.send(&res, "query", sql, vars); err != nil {
+		return err
+	}
+	if res.Result == nil || len(*res.Result) == 0 {
+		return constants.InvalidResponse
+	}
+
+	Reset(dst)
+	return decodeQueryResultInto(db, (*res.Result)[0], dst)
+}
+
+// Reset clears the value v points to for it to be decoded into again, keeping the memory it
+// holds: slices are truncated to zero length, keeping their backing arrays and the memory of
+// their items, maps are emptied, and other values are zeroed. A slice that the decoded value does
+// not set is therefore left empty rather than nil.
+func Reset(v interface{}) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return
+	}
+	reset(rv.Elem())
+}
+
+func reset(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Slice:
+		items := v.Slice(0, v.Cap())
+		for i := 0; i < items.Len(); i++ {
+			reset(items.Index(i))
+		}
+		v.SetLen(0)
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			v.SetMapIndex(iter.Key(), reflect.Value{})
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).CanSet() {
+				// unexported fields cannot be reset one by one
+				v.SetZero()
+				return
+			}
+		}
+		for i := 0; i < v.NumField(); i++ {
+			reset(v.Field(i))
+		}
+	default:
+		v.SetZero()
+	}
+}
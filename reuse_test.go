@@ -0,0 +1,67 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestSelectInto(t *testing.T) {
+	responses := []interface{}{
+		[]interface{}{
+			map[string]interface{}{"name": "a", "tags": []string{"x", "y"}},
+			map[string]interface{}{"name": "b", "tags": []string{"z"}},
+			map[string]interface{}{"name": "c"},
+		},
+		[]interface{}{
+			map[string]interface{}{"name": "d"},
+			map[string]interface{}{"name": "e", "tags": []string{"w"}},
+		},
+	}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		result := responses[0]
+		responses = responses[1:]
+		return result, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	type item struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	var items []item
+	require.NoError(t, surrealdb.SelectInto(db, &items, models.Table("item")))
+	require.Len(t, items, 3)
+	require.Equal(t, []string{"x", "y"}, items[0].Tags)
+	first := &items[0]
+
+	require.NoError(t, surrealdb.SelectInto(db, &items, models.Table("item")))
+	require.Equal(t, []item{{Name: "d", Tags: []string{}}, {Name: "e", Tags: []string{"w"}}}, items)
+	require.Same(t, first, &items[0], "the rows must be decoded into the same backing array")
+}
+
+func TestReset(t *testing.T) {
+	type row struct {
+		Name  string
+		Tags  []string
+		Attrs map[string]int
+		Ref   *models.RecordID
+	}
+	rows := []row{{Name: "a", Tags: []string{"x"}, Attrs: map[string]int{"n": 1}, Ref: &models.RecordID{Table: "t", ID: 1}}}
+	tags, attrs := rows[0].Tags, rows[0].Attrs
+
+	surrealdb.Reset(&rows)
+	require.Empty(t, rows)
+	rows = rows[:1]
+	require.Equal(t, row{Tags: []string{}, Attrs: map[string]int{}}, rows[0])
+	require.Equal(t, 1, cap(tags))
+	require.Same(t, &tags[0], &rows[0].Tags[:1][0])
+	require.Empty(t, attrs)
+}
@@ -0,0 +1,255 @@
+package surrealdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+const (
+	// DefaultSagaTable is the table holding the state of the saga runs.
+	DefaultSagaTable = "saga"
+	// DefaultSagaLease is how long a run is considered live after its state was last saved.
+	DefaultSagaLease = 5 * time.Minute
+)
+
+// ErrSagaInterrupted is the cause reported for the runs compensated by RecoverSagas.
+var ErrSagaInterrupted = errors.New("saga was interrupted")
+
+// SagaStatus is the status of a saga run.
+type SagaStatus string
+
+const (
+	SagaRunning      SagaStatus = "running"
+	SagaCompleted    SagaStatus = "completed"
+	SagaCompensating SagaStatus = "compensating"
+	SagaCompensated  SagaStatus = "compensated"
+	// SagaFailed means that some compensations failed, and the data needs manual attention.
+	SagaFailed SagaStatus = "failed"
+)
+
+// SagaStep is a step of a saga. Compensate undoes Do, and may be nil for steps with nothing to undo.
+type SagaStep struct {
+	Name       string
+	Do         func(db *DB) error
+	Compensate func(db *DB) error
+}
+
+// Saga runs multi-record workflows step by step. When a step fails, the steps already done are
+// compensated in reverse order. The progress of each run is stored in Table, so that runs
+// interrupted by a crash can be compensated by RecoverSagas. Steps should be atomic, such as a
+// single statement or transaction, as a step interrupted midway is not compensated.
+type Saga struct {
+	Name  string
+	Steps []SagaStep
+	// Table holds the state of the runs. It defaults to DefaultSagaTable.
+	Table models.Table
+	// Lease is how long a run is considered live after each save of its state, which happens
+	// after every step. It must be longer than the slowest step, as RecoverSagas takes over the
+	// runs whose lease has expired. It defaults to DefaultSagaLease.
+	Lease time.Duration
+}
+
+// SagaError is returned when a saga run failed. It wraps the error of the failed step, and the
+// errors of the compensations that failed.
+type SagaError struct {
+	Saga  string
+	RunID string
+	// Step is the step that failed, empty when the run was interrupted.
+	Step               string
+	Err                error
+	CompensationErrors []error
+}
+
+func (e *SagaError) Error() string {
+	msg := fmt.Sprintf("saga %s run %s failed", e.Saga, e.RunID)
+	if e.Step != "" {
+		msg += fmt.Sprintf(" at step %s", e.Step)
+	}
+	msg += ": " + e.Err.Error()
+	if len(e.CompensationErrors) > 0 {
+		compensations := make([]string, len(e.CompensationErrors))
+		for i, err := range e.CompensationErrors {
+			compensations[i] = err.Error()
+		}
+		msg += fmt.Sprintf(" (compensation failed: %s)", strings.Join(compensations, "; "))
+	}
+	return msg
+}
+
+func (e *SagaError) Unwrap() []error {
+	return append([]error{e.Err}, e.CompensationErrors...)
+}
+
+// sagaState is the stored state of a saga run
+type sagaState struct {
+	ID        *models.RecordID `json:"id,omitempty"`
+	Saga      string           `json:"saga"`
+	Status    SagaStatus       `json:"status"`
+	Completed []string         `json:"completed"`
+	Error     string           `json:"error,omitempty"`
+	// LeaseUntil is the server time until which the run is live
+	LeaseUntil *models.CustomDateTime `json:"lease_until,omitempty"`
+}
+
+// Run runs the saga, storing its progress under runID, which must be unique per run.
+func (s *Saga) Run(db *DB, runID string) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	state := &sagaState{Saga: s.Name, Status: SagaRunning, Completed: []string{}}
+	if err := s.save(db, runID, state); err != nil {
+		return err
+	}
+
+	for _, step := range s.Steps {
+		if err := step.Do(db); err != nil {
+			return s.compensate(db, runID, state, step.Name, err)
+		}
+		state.Completed = append(state.Completed, step.Name)
+		if err := s.save(db, runID, state); err != nil {
+			return s.compensate(db, runID, state, step.Name, err)
+		}
+	}
+
+	state.Status = SagaCompleted
+	return s.save(db, runID, state)
+}
+
+// compensate undoes the completed steps of a run in reverse order.
+func (s *Saga) compensate(db *DB, runID string, state *sagaState, failedStep string, cause error) error {
+	sagaErr := &SagaError{Saga: s.Name, RunID: runID, Step: failedStep, Err: cause}
+
+	state.Status = SagaCompensating
+	state.Error = cause.Error()
+	if err := s.save(db, runID, state); err != nil {
+		sagaErr.CompensationErrors = append(sagaErr.CompensationErrors, err)
+	}
+
+	for len(state.Completed) > 0 {
+		name := state.Completed[len(state.Completed)-1]
+		if step := s.step(name); step != nil && step.Compensate != nil {
+			if err := step.Compensate(db); err != nil {
+				sagaErr.CompensationErrors = append(sagaErr.CompensationErrors,
+					fmt.Errorf("step %s: %w", name, err))
+				break
+			}
+		}
+		state.Completed = state.Completed[:len(state.Completed)-1]
+		if err := s.save(db, runID, state); err != nil {
+			sagaErr.CompensationErrors = append(sagaErr.CompensationErrors, err)
+		}
+	}
+
+	state.Status = SagaCompensated
+	if len(sagaErr.CompensationErrors) > 0 {
+		state.Status = SagaFailed
+	}
+	if err := s.save(db, runID, state); err != nil {
+		sagaErr.CompensationErrors = append(sagaErr.CompensationErrors, err)
+	}
+
+	return sagaErr
+}
+
+// RecoverSagas compensates the runs of the given sagas that were left running or compensating,
+// typically by a crash, once their lease has expired. Each run is claimed by renewing its lease
+// before it is compensated, so that concurrent calls do not compensate the same run twice. Runs of
+// other sagas are left untouched. The errors of the runs that could not be fully compensated are
+// joined in the returned error.
+func RecoverSagas(db *DB, sagas ...*Saga) error {
+	for _, s := range sagas {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, s := range sagas {
+		states, err := querySingle[[]sagaState](db,
+			"UPDATE $table SET lease_until = $lease_until WHERE saga = $saga AND status IN [$running, $compensating] "+
+				"AND (lease_until = NONE OR lease_until <= time::now())",
+			map[string]interface{}{
+				"table":        s.table(),
+				"saga":         s.Name,
+				"running":      SagaRunning,
+				"compensating": SagaCompensating,
+				"lease_until":  s.leaseUntil(db),
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		for i := range *states {
+			state := &(*states)[i]
+			if state.ID == nil {
+				continue
+			}
+			runID := fmt.Sprintf("%v", state.ID.ID)
+			if err := s.compensate(db, runID, state, "", ErrSagaInterrupted); err != nil {
+				var sagaErr *SagaError
+				if errors.As(err, &sagaErr) && len(sagaErr.CompensationErrors) > 0 {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Saga) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("saga needs a name")
+	}
+	seen := map[string]bool{}
+	for _, step := range s.Steps {
+		if step.Name == "" || step.Do == nil {
+			return fmt.Errorf("saga %s: every step needs a name and a Do function", s.Name)
+		}
+		if seen[step.Name] {
+			return fmt.Errorf("saga %s: step %s is defined twice", s.Name, step.Name)
+		}
+		seen[step.Name] = true
+	}
+	return nil
+}
+
+func (s *Saga) step(name string) *SagaStep {
+	for i := range s.Steps {
+		if s.Steps[i].Name == name {
+			return &s.Steps[i]
+		}
+	}
+	return nil
+}
+
+func (s *Saga) table() models.Table {
+	if s.Table == "" {
+		return DefaultSagaTable
+	}
+	return s.Table
+}
+
+// leaseUntil returns the end of the lease of a run saved now, in server time.
+func (s *Saga) leaseUntil(db *DB) *models.CustomDateTime {
+	lease := s.Lease
+	if lease <= 0 {
+		lease = DefaultSagaLease
+	}
+	return &models.CustomDateTime{Time: db.toServerTime(time.Now().Add(lease))}
+}
+
+func (s *Saga) save(db *DB, runID string, state *sagaState) error {
+	id := models.NewRecordID(s.table().String(), runID)
+	stored := *state
+	stored.ID = nil
+	stored.LeaseUntil = s.leaseUntil(db)
+	_, err := Upsert[sagaState](db, id, stored)
+	return err
+}
@@ -0,0 +1,102 @@
+package surrealdb_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func (s *SurrealDBTestSuite) TestSaga() {
+	defer func() {
+		_, err := surrealdb.Delete[[]interface{}](s.db, models.Table(surrealdb.DefaultSagaTable))
+		s.NoError(err)
+	}()
+
+	createStep := func(name string) surrealdb.SagaStep {
+		id := models.NewRecordID("users", name)
+		return surrealdb.SagaStep{
+			Name: name,
+			Do: func(db *surrealdb.DB) error {
+				_, err := surrealdb.Create[testUser](db, id, testUser{Username: name})
+				return err
+			},
+			Compensate: func(db *surrealdb.DB) error {
+				_, err := surrealdb.Delete[testUser](db, id)
+				return err
+			},
+		}
+	}
+	failure := errors.New("payment declined")
+
+	s.Run("completed run", func() {
+		saga := &surrealdb.Saga{Name: "signup", Steps: []surrealdb.SagaStep{createStep("a"), createStep("b")}}
+		s.Require().NoError(saga.Run(s.db, "completed"))
+
+		users, err := surrealdb.Select[[]testUser](s.db, models.Table("users"))
+		s.Require().NoError(err)
+		s.Len(*users, 2)
+		_, err = surrealdb.Delete[[]testUser](s.db, models.Table("users"))
+		s.Require().NoError(err)
+	})
+
+	s.Run("failed step is compensated", func() {
+		saga := &surrealdb.Saga{Name: "signup", Steps: []surrealdb.SagaStep{
+			createStep("a"),
+			createStep("b"),
+			{Name: "pay", Do: func(*surrealdb.DB) error { return failure }},
+		}}
+		err := saga.Run(s.db, "failed")
+		s.Require().ErrorIs(err, failure)
+
+		var sagaErr *surrealdb.SagaError
+		s.Require().ErrorAs(err, &sagaErr)
+		s.Equal("pay", sagaErr.Step)
+		s.Empty(sagaErr.CompensationErrors)
+
+		users, err := surrealdb.Select[[]testUser](s.db, models.Table("users"))
+		s.Require().NoError(err)
+		s.Empty(*users)
+	})
+
+	s.Run("interrupted run is recovered", func() {
+		saga := &surrealdb.Saga{Name: "signup", Steps: []surrealdb.SagaStep{createStep("a"), createStep("b")}}
+		s.Require().NoError(saga.Steps[0].Do(s.db))
+		_, err := surrealdb.Create[map[string]interface{}](s.db, models.NewRecordID(surrealdb.DefaultSagaTable, "crashed"),
+			map[string]interface{}{"saga": "signup", "status": surrealdb.SagaRunning, "completed": []string{"a"}})
+		s.Require().NoError(err)
+
+		s.Require().NoError(surrealdb.RecoverSagas(s.db, saga))
+
+		users, err := surrealdb.Select[[]testUser](s.db, models.Table("users"))
+		s.Require().NoError(err)
+		s.Empty(*users)
+
+		state, err := surrealdb.Select[map[string]interface{}](s.db, models.NewRecordID(surrealdb.DefaultSagaTable, "crashed"))
+		s.Require().NoError(err)
+		s.Equal(string(surrealdb.SagaCompensated), (*state)["status"])
+	})
+
+	s.Run("live run is not recovered", func() {
+		saga := &surrealdb.Saga{Name: "signup", Steps: []surrealdb.SagaStep{createStep("a"), createStep("b")}}
+		s.Require().NoError(saga.Steps[0].Do(s.db))
+		_, err := surrealdb.Create[map[string]interface{}](s.db, models.NewRecordID(surrealdb.DefaultSagaTable, "live"),
+			map[string]interface{}{"saga": "signup", "status": surrealdb.SagaRunning, "completed": []string{"a"},
+				"lease_until": &models.CustomDateTime{Time: time.Now().Add(time.Hour)}})
+		s.Require().NoError(err)
+
+		s.Require().NoError(surrealdb.RecoverSagas(s.db, saga))
+
+		users, err := surrealdb.Select[[]testUser](s.db, models.Table("users"))
+		s.Require().NoError(err)
+		s.Len(*users, 1)
+
+		state, err := surrealdb.Select[map[string]interface{}](s.db, models.NewRecordID(surrealdb.DefaultSagaTable, "live"))
+		s.Require().NoError(err)
+		s.Equal(string(surrealdb.SagaRunning), (*state)["status"])
+		_, err = surrealdb.Delete[[]testUser](s.db, models.Table("users"))
+		s.Require().NoError(err)
+	})
+}
@@ -0,0 +1,68 @@
+package surrealdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/surrealdb/surrealdb.go/internal/rand"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// NewScratchDB opens another connection to the server of db, with the same options and
+// authentication, and defines a database with a unique name in the namespace of db for it, so
+// that tests or jobs running in parallel against a shared server do not see each other's
+// records. cleanup removes the database and closes the connection. ctx bounds the setup only.
+//
+//	scratch, cleanup, err := surrealdb.NewScratchDB(ctx, db)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	t.Cleanup(func() { _ = cleanup() })
+func NewScratchDB(ctx context.Context, db *DB) (scratch *DB, cleanup func() error, err error) {
+	state := db.SessionState()
+	if state.Namespace == "" {
+		return nil, nil, fmt.Errorf("select a namespace before creating a scratch database")
+	}
+	if db.reconnect == nil {
+		return nil, nil, fmt.Errorf("the connection of db cannot be opened again")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	scratch, err = db.reconnect()
+	if err != nil {
+		return nil, nil, err
+	}
+	scratch.retryPolicy = db.retryPolicy
+	scratch.compensateClockSkew = db.compensateClockSkew
+	scratch.clockSkew.Store(db.clockSkew.Load())
+
+	closeScratch := func(err error) error {
+		if closeErr := scratch.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+
+	// the session of db is restored in its namespace only, until the scratch database is defined
+	name := "scratch_" + rand.StringWithCharset(16, "abcdefghijklmnopqrstuvwxyz0123456789")
+	identifier := models.EscapeIdent(name)
+	state.Database = ""
+	setup := scratch.WithContext(ctx)
+	if err := setup.RestoreSession(ctx, state); err != nil {
+		return nil, nil, closeScratch(err)
+	}
+	if _, err := querySingle[interface{}](setup, "DEFINE DATABASE "+identifier, nil); err != nil {
+		return nil, nil, closeScratch(err)
+	}
+	if err := setup.Use(state.Namespace, name); err != nil {
+		return nil, nil, closeScratch(err)
+	}
+
+	cleanup = func() error {
+		_, err := querySingle[interface{}](scratch, "REMOVE DATABASE "+identifier, nil)
+		return closeScratch(err)
+	}
+	return scratch, cleanup, nil
+}
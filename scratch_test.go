@@ -0,0 +1,64 @@
+package surrealdb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestNewScratchDB(t *testing.T) {
+	var lock sync.Mutex
+	var queries, databases []string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		queries = append(queries, req.Params[0].(string))
+		databases = append(databases, req.HTTP.Header.Get("Surreal-NS")+"/"+req.HTTP.Header.Get("Surreal-DB"))
+		lock.Unlock()
+		return []interface{}{map[string]interface{}{"status": "OK", "time": "1ms", "result": nil}}, nil
+	})
+
+	ctx := context.Background()
+	db, err := surrealdb.Connect(ctx, server.URL, surrealdb.WithNamespace("test", "shared"))
+	require.NoError(t, err)
+
+	first, cleanupFirst, err := surrealdb.NewScratchDB(ctx, db)
+	require.NoError(t, err)
+	second, cleanupSecond, err := surrealdb.NewScratchDB(ctx, db)
+	require.NoError(t, err)
+
+	firstDatabase, secondDatabase := first.SessionState().Database, second.SessionState().Database
+	require.Regexp(t, "^scratch_[a-z0-9]{16}$", firstDatabase)
+	require.NotEqual(t, firstDatabase, secondDatabase)
+	require.Equal(t, "shared", db.SessionState().Database)
+
+	_, err = surrealdb.Query[interface{}](first, "SELECT * FROM user", nil)
+	require.NoError(t, err)
+	require.NoError(t, cleanupFirst())
+	require.NoError(t, cleanupSecond())
+
+	require.Equal(t, []string{
+		"DEFINE DATABASE " + firstDatabase,
+		"DEFINE DATABASE " + secondDatabase,
+		"SELECT * FROM user",
+		"REMOVE DATABASE " + firstDatabase,
+		"REMOVE DATABASE " + secondDatabase,
+	}, queries)
+	require.Equal(t, []string{"test/", "test/", "test/" + firstDatabase, "test/" + firstDatabase, "test/" + secondDatabase}, databases)
+
+	// a scratch database is defined in the namespace of the session
+	unscoped, err := surrealdb.Connect(ctx, server.URL)
+	require.NoError(t, err)
+	_, _, err = surrealdb.NewScratchDB(ctx, unscoped)
+	require.Error(t, err)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err = surrealdb.NewScratchDB(canceled, db)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, queries, 5)
+}
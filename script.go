@@ -0,0 +1,196 @@
+package surrealdb
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// Script composes statements, each along with the variables it binds, into a single query run
+// as one transaction with RunScript. The statements may use the parameters defined by the Let
+// statements before them.
+//
+// The variables of the statements are merged. When a statement binds a variable already bound to
+// another value, or named like a Let parameter, the variable is renamed in that statement, to the
+// first of name_2, name_3 and so on that is free, so that each statement reads its own values.
+//
+//	var script surrealdb.Script
+//	err := script.Let("user", "SELECT * FROM ONLY $id", map[string]interface{}{"id": userID})
+//	err = script.Add("UPDATE $user.team SET members += $user.id", nil)
+//	team, err := surrealdb.RunScript[[]Team](db, &script)
+type Script struct {
+	statements []string
+	vars       map[string]interface{}
+	// lets are the parameters defined by the Let statements
+	lets map[string]bool
+}
+
+// Let adds the statement LET $name = (sql), defining the parameter $name for the statements that
+// follow as the result of sql, a statement or an expression binding vars.
+func (s *Script) Let(name, sql string, vars map[string]interface{}) error {
+	name, err := varName(name)
+	if err != nil {
+		return err
+	}
+	if _, bound := s.vars[name]; bound {
+		return fmt.Errorf("parameter $%s is already bound as a variable", name)
+	}
+	sql, err = s.bind(sql, vars)
+	if err != nil {
+		return err
+	}
+
+	if s.lets == nil {
+		s.lets = map[string]bool{}
+	}
+	s.lets[name] = true
+	s.statements = append(s.statements, fmt.Sprintf("LET $%s = (%s)", name, sql))
+	return nil
+}
+
+// Add adds the statement sql, binding vars.
+func (s *Script) Add(sql string, vars map[string]interface{}) error {
+	if strings.TrimSpace(sql) == "" {
+		return fmt.Errorf("empty statement")
+	}
+	sql, err := s.bind(sql, vars)
+	if err != nil {
+		return err
+	}
+	s.statements = append(s.statements, sql)
+	return nil
+}
+
+// Build returns the query of the script, in a transaction, and the variables it binds.
+func (s *Script) Build() (string, map[string]interface{}) {
+	vars := make(map[string]interface{}, len(s.vars))
+	for name, value := range s.vars {
+		vars[name] = value
+	}
+	return "BEGIN TRANSACTION;\n" + strings.Join(s.statements, ";\n") + ";\nCOMMIT TRANSACTION;", vars
+}
+
+// bind merges vars into the variables of the script, and returns sql with the variables that
+// had to be renamed replaced.
+func (s *Script) bind(sql string, vars map[string]interface{}) (string, error) {
+	if s.vars == nil {
+		s.vars = map[string]interface{}{}
+	}
+
+	own := make(map[string]interface{}, len(vars))
+	names := make([]string, 0, len(vars))
+	for key, value := range vars {
+		name, err := varName(key)
+		if err != nil {
+			return "", err
+		}
+		own[name] = value
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	renames := map[string]string{}
+	for _, name := range names {
+		value := own[name]
+		free := func(bound string) bool {
+			if s.lets[bound] {
+				return false
+			}
+			if _, clash := own[bound]; clash && bound != name {
+				return false
+			}
+			existing, taken := s.vars[bound]
+			return !taken || reflect.DeepEqual(existing, value)
+		}
+
+		bound := name
+		for i := 2; !free(bound); i++ {
+			bound = fmt.Sprintf("%s_%d", name, i)
+		}
+		s.vars[bound] = value
+		if bound != name {
+			renames[name] = bound
+		}
+	}
+	return renameParams(sql, renames), nil
+}
+
+// renameParams replaces the $parameters of sql named in renames, leaving the strings and escaped
+// identifiers unchanged.
+func renameParams(sql string, renames map[string]string) string {
+	if len(renames) == 0 {
+		return sql
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(sql); {
+		if quote, ok := openingQuote(sql[i:]); ok {
+			end := i + len(quote) + closingQuote(sql[i+len(quote):], quote)
+			b.WriteString(sql[i:end])
+			i = end
+			continue
+		}
+		if sql[i] != '$' {
+			b.WriteByte(sql[i])
+			i++
+			continue
+		}
+
+		// a parameter name is made of ASCII letters, digits and underscores
+		end := i + 1
+		for end < len(sql) && isPlaceholderName(sql[end:end+1]) {
+			end++
+		}
+		name := sql[i+1 : end]
+		if renamed, ok := renames[name]; ok {
+			name = renamed
+		}
+		b.WriteString("$" + name)
+		i = end
+	}
+	return b.String()
+}
+
+// RunScript runs the statements of script in a transaction, and returns the result of the last
+// one. When a statement fails, the transaction is canceled and its error returned.
+func RunScript[TResult any](db *DB, script *Script) (*TResult, error) {
+	if len(script.statements) == 0 {
+		return nil, fmt.Errorf("empty script")
+	}
+	sql, vars := script.Build()
+
+	var res connection.RPCResponse[[]QueryResult[cbor.RawMessage]]
+	if err := db.send(&res, "query", sql, vars); err != nil {
+		return nil, err
+	}
+	if res.Result == nil || len(*res.Result) == 0 {
+		return nil, constants.InvalidResponse
+	}
+
+	// every statement of a canceled transaction fails, report the one that canceled it
+	results := *res.Result
+	var failed *QueryResult[cbor.RawMessage]
+	for i := range results {
+		if results[i].Status == "OK" {
+			continue
+		}
+		var msg string
+		_ = db.con.GetUnmarshaler().Unmarshal(results[i].Result, &msg)
+		if !strings.Contains(msg, "failed transaction") {
+			failed = &results[i]
+			break
+		}
+		if failed == nil {
+			failed = &results[i]
+		}
+	}
+	if failed != nil {
+		return decodeQueryResult[TResult](db, *failed)
+	}
+	return decodeQueryResult[TResult](db, results[len(results)-1])
+}
@@ -0,0 +1,74 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestScript(t *testing.T) {
+	var script surrealdb.Script
+	require.NoError(t, script.Let("user", "SELECT * FROM ONLY $id", map[string]interface{}{"id": models.NewRecordID("user", "ann")}))
+	require.NoError(t, script.Add("UPDATE $id SET owner = $user.id, note = '$id'", map[string]interface{}{"id": models.NewRecordID("team", "docs")}))
+	require.NoError(t, script.Add("SELECT * FROM $id", map[string]interface{}{"id": models.NewRecordID("user", "ann")}))
+	require.NoError(t, script.Add("RETURN $user", map[string]interface{}{"user": "bob"}))
+	require.Error(t, script.Let("id", "1", nil), "a variable cannot be redefined with LET")
+
+	sql, vars := script.Build()
+	require.Equal(t, "BEGIN TRANSACTION;\n"+
+		"LET $user = (SELECT * FROM ONLY $id);\n"+
+		"UPDATE $id_2 SET owner = $user.id, note = '$id';\n"+
+		"SELECT * FROM $id;\n"+
+		"RETURN $user_2;\n"+
+		"COMMIT TRANSACTION;", sql)
+	require.Equal(t, map[string]interface{}{
+		"id":     models.NewRecordID("user", "ann"),
+		"id_2":   models.NewRecordID("team", "docs"),
+		"user_2": "bob",
+	}, vars)
+}
+
+func TestRunScript(t *testing.T) {
+	type team struct {
+		Name string `json:"name"`
+	}
+
+	failing := false
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		if failing {
+			return []surrealdb.QueryResult[interface{}]{
+				{Status: "ERR", Result: "The query was not executed due to a failed transaction"},
+				{Status: "ERR", Result: "Found 'x' for field `count`, but expected a number"},
+			}, nil
+		}
+		return []surrealdb.QueryResult[interface{}]{
+			{Status: "OK", Result: nil},
+			{Status: "OK", Result: []team{{Name: "docs"}}},
+		}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	var script surrealdb.Script
+	require.NoError(t, script.Let("user", "SELECT * FROM ONLY $id", map[string]interface{}{"id": models.NewRecordID("user", "ann")}))
+	require.NoError(t, script.Add("UPDATE $user.team SET members += $user.id", nil))
+
+	teams, err := surrealdb.RunScript[[]team](db, &script)
+	require.NoError(t, err)
+	require.Equal(t, []team{{Name: "docs"}}, *teams)
+
+	failing = true
+	_, err = surrealdb.RunScript[[]team](db, &script)
+	require.ErrorIs(t, err, constants.ErrQuery)
+	require.ErrorContains(t, err, "expected a number")
+
+	_, err = surrealdb.RunScript[[]team](db, &surrealdb.Script{})
+	require.Error(t, err)
+}
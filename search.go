@@ -0,0 +1,251 @@
+package surrealdb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Matches returns the full-text condition field @ref@ query, which requires a SEARCH index on
+// field. ref numbers the condition, for search::score and search::highlight to refer to it when a
+// query holds several of them. A ref of 0 returns the unnumbered condition field @@ query.
+func Matches(field, query string, ref int) (Condition, error) {
+	escaped, err := models.EscapeFieldPath(field)
+	if err != nil {
+		return Condition{}, err
+	}
+	operator := "@@"
+	if ref > 0 {
+		operator = fmt.Sprintf("@%d@", ref)
+	}
+	name := fmt.Sprintf("search_%d_query", ref)
+	return Condition{
+		SQL:  fmt.Sprintf("%s %s $%s", escaped, operator, name),
+		Vars: map[string]interface{}{name: query},
+	}, nil
+}
+
+// Analyzer is a full-text analyzer, which splits text into the terms indexed by a SEARCH index.
+type Analyzer struct {
+	Name string
+	// Tokenizers split the text, such as blank, camel, class or punct.
+	Tokenizers []string
+	// Filters transform the terms, such as lowercase, ascii, snowball(english) or edgengram(2,10).
+	Filters []string
+}
+
+// SearchIndex is a full-text index on a field, see DefineSearchIndex.
+type SearchIndex struct {
+	Name     string
+	Table    models.Table
+	Field    string
+	Analyzer string
+	// Highlights stores the offsets of the terms, which search::highlight requires.
+	Highlights bool
+}
+
+// analyzerFunction matches a tokenizer or filter, with its arguments if any
+var analyzerFunction = regexp.MustCompile(`^[a-z_]+(\([a-z0-9_]+(,[a-z0-9_]+)*\))?$`)
+
+// AnalyzerStatement returns the DEFINE ANALYZER statement of a.
+func AnalyzerStatement(a Analyzer) (string, error) {
+	if a.Name == "" {
+		return "", fmt.Errorf("analyzer needs a name")
+	}
+	name := models.EscapeIdent(a.Name)
+
+	sql := "DEFINE ANALYZER " + name
+	for _, part := range []struct {
+		keyword   string
+		functions []string
+	}{{"TOKENIZERS", a.Tokenizers}, {"FILTERS", a.Filters}} {
+		if len(part.functions) == 0 {
+			continue
+		}
+		functions := make([]string, len(part.functions))
+		for i, f := range part.functions {
+			functions[i] = strings.ToLower(strings.ReplaceAll(f, " ", ""))
+			if !analyzerFunction.MatchString(functions[i]) {
+				return "", fmt.Errorf("invalid analyzer %s %q", strings.ToLower(part.keyword), f)
+			}
+		}
+		sql += " " + part.keyword + " " + strings.Join(functions, ",")
+	}
+	return sql, nil
+}
+
+// DefineAnalyzer defines the analyzer a. It does nothing when the analyzer is already defined.
+func DefineAnalyzer(db *DB, a Analyzer) error {
+	sql, err := AnalyzerStatement(a)
+	if err != nil {
+		return err
+	}
+	return defineSearch(db, sql)
+}
+
+// SearchIndexStatement returns the DEFINE INDEX statement of idx, ranking the matches with BM25.
+func SearchIndexStatement(idx SearchIndex) (string, error) {
+	if idx.Name == "" || idx.Analyzer == "" {
+		return "", fmt.Errorf("search index needs a name and an analyzer")
+	}
+	field, err := models.EscapeFieldPath(idx.Field)
+	if err != nil {
+		return "", err
+	}
+
+	sql := fmt.Sprintf("DEFINE INDEX %s ON TABLE %s FIELDS %s SEARCH ANALYZER %s BM25",
+		models.EscapeIdent(idx.Name), idx.Table.SurrealString(), field, models.EscapeIdent(idx.Analyzer))
+	if idx.Highlights {
+		sql += " HIGHLIGHTS"
+	}
+	return sql, nil
+}
+
+// DefineSearchIndex defines the index idx. It does nothing when the index is already defined.
+func DefineSearchIndex(db *DB, idx SearchIndex) error {
+	sql, err := SearchIndexStatement(idx)
+	if err != nil {
+		return err
+	}
+	return defineSearch(db, sql)
+}
+
+func defineSearch(db *DB, sql string) error {
+	_, err := querySingle[cbor.RawMessage](db, sql, nil)
+	if errors.Is(err, constants.ErrAlreadyExists) {
+		return nil
+	}
+	return err
+}
+
+// FullTextSearch is a full-text search of the records of a table, see Search.
+type FullTextSearch struct {
+	Table models.Table
+	// Field is the field searched, which must have a SEARCH index with HIGHLIGHTS for the
+	// highlights to be returned.
+	Field string
+	Query string
+	// Filter further restricts the records returned.
+	Filter *Condition
+	// Limit is the maximum number of results, no limit when zero.
+	Limit int
+	// HighlightPrefix and HighlightSuffix surround the matched terms in the highlights, <b> and
+	// </b> when empty.
+	HighlightPrefix string
+	HighlightSuffix string
+}
+
+// SearchResult is a record matched by a full-text search.
+type SearchResult[T any] struct {
+	Record T
+	// Score is the relevance of the record, as computed by search::score.
+	Score float64
+	// Highlights holds the text of the searched field with the matched terms highlighted, one
+	// fragment per value when the field holds an array.
+	Highlights []string
+}
+
+// searchFields are the fields added to the records by Search
+type searchFields struct {
+	Score     float64     `json:"_search_score"`
+	Highlight interface{} `json:"_search_highlight"`
+}
+
+// Search runs a full-text search and returns the matching records, the most relevant first.
+func Search[T any](db *DB, s FullTextSearch) ([]SearchResult[T], error) {
+	match, err := Matches(s.Field, s.Query, 1)
+	if err != nil {
+		return nil, err
+	}
+	where := match
+	if s.Filter != nil {
+		if where, err = And(match, *s.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	projection, vars := SearchProjection(1, s.HighlightPrefix, s.HighlightSuffix)
+	vars["search_table"] = s.Table
+	for name, value := range where.Vars {
+		vars[name] = value
+	}
+
+	sql := "SELECT *, " + projection + " FROM $search_table WHERE " + where.SQL + " ORDER BY _search_score DESC"
+	if s.Limit > 0 {
+		sql += " LIMIT $search_limit"
+		vars["search_limit"] = s.Limit
+	}
+
+	return QuerySearch[T](db, sql, vars)
+}
+
+// SearchProjection returns the projections of the score and highlights of the full-text condition
+// numbered ref, along with the variables they bind, for queries written by hand to be decoded by
+// QuerySearch. The matched terms are highlighted with prefix and suffix, <b> and </b> when empty.
+//
+//	projection, vars := surrealdb.SearchProjection(1, "", "")
+//	vars["query"] = "go"
+//	results, err := surrealdb.QuerySearch[Article](db, "SELECT *, "+projection+
+//		" FROM article WHERE body @1@ $query ORDER BY _search_score DESC", vars)
+func SearchProjection(ref int, prefix, suffix string) (string, map[string]interface{}) {
+	if prefix == "" && suffix == "" {
+		prefix, suffix = "<b>", "</b>"
+	}
+	sql := fmt.Sprintf("search::score(%d) AS _search_score, "+
+		"search::highlight($search_highlight_prefix, $search_highlight_suffix, %[1]d) AS _search_highlight", ref)
+	return sql, map[string]interface{}{
+		"search_highlight_prefix": prefix,
+		"search_highlight_suffix": suffix,
+	}
+}
+
+// QuerySearch runs a query selecting the projections of SearchProjection and returns its rows
+// with their score and highlights.
+func QuerySearch[T any](db *DB, sql string, vars map[string]interface{}) ([]SearchResult[T], error) {
+	rows, err := queryStream[cbor.RawMessage](db, sql, vars)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unmarshaler := db.con.GetUnmarshaler()
+	var results []SearchResult[T]
+	for rows.Next() {
+		raw := rows.Value()
+		var result SearchResult[T]
+		if err := connection.DecodeResult(unmarshaler, "query", raw, &result.Record); err != nil {
+			return nil, err
+		}
+		var fields searchFields
+		if err := unmarshaler.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+		result.Score = fields.Score
+		result.Highlights = highlights(fields.Highlight)
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// highlights returns the fragments of the result of search::highlight, a string or an array of
+// strings when the field holds an array.
+func highlights(v interface{}) []string {
+	switch h := v.(type) {
+	case string:
+		return []string{h}
+	case []interface{}:
+		fragments := make([]string, 0, len(h))
+		for _, item := range h {
+			fragments = append(fragments, highlights(item)...)
+		}
+		return fragments
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestSearch(t *testing.T) {
+	var sql string
+	var vars map[interface{}]interface{}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		sql = req.Params[0].(string)
+		vars = req.Params[1].(map[interface{}]interface{})
+
+		return []interface{}{map[string]interface{}{
+			"status": "OK",
+			"time":   "1ms",
+			"result": []interface{}{
+				map[string]interface{}{"title": "Go", "_search_score": 2.5, "_search_highlight": "<em>Go</em> is fun"},
+				map[string]interface{}{"title": "Rust", "_search_score": 1.5, "_search_highlight": []interface{}{"a", "<em>go</em>"}},
+			},
+		}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	type article struct {
+		Title string `json:"title"`
+	}
+	results, err := surrealdb.Search[article](db, surrealdb.FullTextSearch{
+		Table:           "article",
+		Field:           "body",
+		Query:           "go",
+		Filter:          &surrealdb.Condition{SQL: "published = true"},
+		Limit:           10,
+		HighlightPrefix: "<em>",
+		HighlightSuffix: "</em>",
+	})
+	require.NoError(t, err)
+	require.Contains(t, sql, "WHERE (body @1@ $search_1_query) AND (published = true)")
+	require.Contains(t, sql, "search::score(1)")
+	require.Equal(t, "go", vars["search_1_query"])
+	require.Equal(t, "<em>", vars["search_highlight_prefix"])
+
+	require.Equal(t, []surrealdb.SearchResult[article]{
+		{Record: article{Title: "Go"}, Score: 2.5, Highlights: []string{"<em>Go</em> is fun"}},
+		{Record: article{Title: "Rust"}, Score: 1.5, Highlights: []string{"a", "<em>go</em>"}},
+	}, results)
+}
+
+func TestSearchStatements(t *testing.T) {
+	sql, err := surrealdb.AnalyzerStatement(surrealdb.Analyzer{
+		Name:       "english",
+		Tokenizers: []string{"blank", "class"},
+		Filters:    []string{"lowercase", "snowball(english)", "edgengram(2, 10)"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "DEFINE ANALYZER english TOKENIZERS blank,class FILTERS lowercase,snowball(english),edgengram(2,10)", sql)
+
+	_, err = surrealdb.AnalyzerStatement(surrealdb.Analyzer{Name: "x", Filters: []string{"lowercase; REMOVE TABLE user"}})
+	require.Error(t, err)
+
+	sql, err = surrealdb.SearchIndexStatement(surrealdb.SearchIndex{
+		Name:       "article_body",
+		Table:      "article",
+		Field:      "body",
+		Analyzer:   "english",
+		Highlights: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "DEFINE INDEX article_body ON TABLE article FIELDS body SEARCH ANALYZER english BM25 HIGHLIGHTS", sql)
+
+	match, err := surrealdb.Matches("title", "go", 0)
+	require.NoError(t, err)
+	require.Equal(t, "title @@ $search_0_query", match.SQL)
+
+	projection, vars := surrealdb.SearchProjection(2, "", "")
+	require.Equal(t, "search::score(2) AS _search_score, "+
+		"search::highlight($search_highlight_prefix, $search_highlight_suffix, 2) AS _search_highlight", projection)
+	require.Equal(t, map[string]interface{}{"search_highlight_prefix": "<b>", "search_highlight_suffix": "</b>"}, vars)
+}
@@ -0,0 +1,135 @@
+package surrealdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Selection describes a SELECT statement, see SelectWith. Omit, Split, OrderBy and Fetch are
+// field paths such as address.city.
+type Selection struct {
+	// Fields are the projections as written in SurrealQL, such as name or count(tags) AS tags,
+	// every field when empty. GraphField returns the projection of a graph path.
+	Fields []string
+	// Omit leaves fields out of the records.
+	Omit []string
+	// Where, which may be nil, restricts the records read.
+	Where *Condition
+	// Split returns a record per element of the array held by each field.
+	Split      []string
+	OrderBy    string
+	Descending bool
+	// Limit is the maximum number of records, no limit when zero.
+	Limit int
+	// Fetch replaces the record ids held by the fields with the records they point to.
+	Fetch []string
+}
+
+// SelectWith returns the records of what selected as described by sel, decoded into TResult.
+//
+//	type user struct {
+//		Name       string      `json:"name"`
+//		Workspaces []Workspace `json:"workspaces"`
+//	}
+//	workspaces, err := surrealdb.GraphField("->owns->workspace", "workspaces")
+//	users, err := surrealdb.SelectWith[user](db, models.Table("user"), surrealdb.Selection{
+//		Fields: []string{"name", workspaces},
+//		Fetch:  []string{"workspaces"},
+//	})
+func SelectWith[TResult any, TWhat TablesOrRecords](db *DB, what TWhat, sel Selection) ([]TResult, error) {
+	projection := "*"
+	if len(sel.Fields) > 0 {
+		projection = strings.Join(sel.Fields, ", ")
+	}
+	if len(sel.Omit) > 0 {
+		omit, err := escapeFieldPaths(sel.Omit)
+		if err != nil {
+			return nil, err
+		}
+		projection += " OMIT " + omit
+	}
+
+	sql, vars := selectStatement(projection, what, sel.Where)
+	if len(sel.Split) > 0 {
+		split, err := escapeFieldPaths(sel.Split)
+		if err != nil {
+			return nil, err
+		}
+		sql += " SPLIT " + split
+	}
+	if sel.OrderBy != "" {
+		order, err := models.EscapeFieldPath(sel.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		sql += " ORDER BY " + order
+		if sel.Descending {
+			sql += " DESC"
+		}
+	}
+	if sel.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d", sel.Limit)
+	}
+	if sel.Limit > 0 {
+		sql += " LIMIT $select_limit"
+		vars["select_limit"] = sel.Limit
+	}
+	if len(sel.Fetch) > 0 {
+		fetch, err := escapeFieldPaths(sel.Fetch)
+		if err != nil {
+			return nil, err
+		}
+		sql += " FETCH " + fetch
+	}
+
+	records, err := querySingle[[]TResult](db, sql, vars)
+	if err != nil {
+		return nil, err
+	}
+	return *records, nil
+}
+
+// GraphField returns the projection of the ids of the records that path leads to from each
+// record, named alias. path is a graph path like for Traverse. Fetching alias selects the
+// records rather than their ids, see Selection.Fetch.
+func GraphField(path, alias string) (string, error) {
+	escaped, err := escapeGraphPath(path)
+	if err != nil {
+		return "", err
+	}
+	if alias == "" {
+		return "", fmt.Errorf("graph field %s needs an alias", path)
+	}
+	return escaped + " AS " + models.EscapeIdent(alias), nil
+}
+
+// InSubquery returns the condition that the value of field is one of the values returned by the
+// subquery sql, which binds vars, such as SELECT VALUE id FROM team WHERE active.
+func InSubquery(field, sql string, vars map[string]interface{}) (Condition, error) {
+	escaped, err := models.EscapeFieldPath(field)
+	if err != nil {
+		return Condition{}, err
+	}
+	return Condition{SQL: fmt.Sprintf("%s IN (%s)", escaped, sql), Vars: vars}, nil
+}
+
+// Exists returns the condition that the subquery sql, which binds vars, returns at least one
+// value. The subquery may refer to the record being checked as $parent.
+func Exists(sql string, vars map[string]interface{}) Condition {
+	return Condition{SQL: fmt.Sprintf("array::len((%s)) > 0", sql), Vars: vars}
+}
+
+// escapeFieldPaths escapes the field paths and joins them by commas.
+func escapeFieldPaths(paths []string) (string, error) {
+	escaped := make([]string, len(paths))
+	for i, path := range paths {
+		field, err := models.EscapeFieldPath(path)
+		if err != nil {
+			return "", err
+		}
+		escaped[i] = field
+	}
+	return strings.Join(escaped, ", "), nil
+}
@@ -0,0 +1,66 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestSelectWith(t *testing.T) {
+	type workspace struct {
+		Name string `json:"name"`
+	}
+	type user struct {
+		Name       string      `json:"name"`
+		Workspaces []workspace `json:"workspaces"`
+	}
+
+	var sql string
+	var vars map[interface{}]interface{}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		sql = req.Params[0].(string)
+		vars = req.Params[1].(map[interface{}]interface{})
+		return []surrealdb.QueryResult[interface{}]{{Status: "OK", Result: []user{{Name: "ann", Workspaces: []workspace{{Name: "docs"}}}}}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	workspaces, err := surrealdb.GraphField("->owns->workspace", "workspaces")
+	require.NoError(t, err)
+	member, err := surrealdb.InSubquery("team", "SELECT VALUE id FROM team WHERE active = $active", map[string]interface{}{"active": true})
+	require.NoError(t, err)
+	where, err := surrealdb.And(member, surrealdb.Exists("SELECT id FROM $parent->owns", nil))
+	require.NoError(t, err)
+
+	users, err := surrealdb.SelectWith[user](db, models.Table("user"), surrealdb.Selection{
+		Fields:     []string{"name", workspaces},
+		Omit:       []string{"password"},
+		Where:      &where,
+		Split:      []string{"emails"},
+		OrderBy:    "created at",
+		Descending: true,
+		Limit:      10,
+		Fetch:      []string{"workspaces"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []user{{Name: "ann", Workspaces: []workspace{{Name: "docs"}}}}, users)
+	require.Equal(t, "SELECT name, ->⟨owns⟩->⟨workspace⟩ AS workspaces OMIT password FROM $select_what "+
+		"WHERE (team IN (SELECT VALUE id FROM team WHERE active = $active)) AND (array::len((SELECT id FROM $parent->owns)) > 0) "+
+		"SPLIT emails ORDER BY ⟨created at⟩ DESC LIMIT $select_limit FETCH workspaces", sql)
+	require.Equal(t, true, vars["active"])
+	require.EqualValues(t, 10, vars["select_limit"])
+	require.Equal(t, models.Table("user"), vars["select_what"])
+
+	_, err = surrealdb.SelectWith[user](db, models.Table("user"), surrealdb.Selection{})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM $select_what", sql)
+
+	_, err = surrealdb.GraphField("->owns; DELETE user", "workspaces")
+	require.Error(t, err)
+}
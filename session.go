@@ -0,0 +1,145 @@
+package surrealdb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// SessionState is the state of the session of a connection: the selected namespace and database,
+// the authentication token and the variables defined with Let. It lets reconnection strategies and
+// connection pools rebuild a session on a fresh connection with RestoreSession.
+type SessionState struct {
+	Namespace string
+	Database  string
+	// Token is the token obtained with SignIn or SignUp, or given to Authenticate.
+	Token string
+	// User is the user signed in with SignIn or SignUp, reported to the audit hook. For record
+	// users and tokens, it is the identity the token was issued for, such as user:john.
+	User string
+	// Access is the access method the token was issued through, for record users.
+	Access    string
+	Variables map[string]interface{}
+}
+
+// SessionState returns a snapshot of the state of the session.
+func (db *DB) SessionState() SessionState {
+	db.sessionLock.RLock()
+	defer db.sessionLock.RUnlock()
+
+	state := db.session
+	if db.session.Variables != nil {
+		state.Variables = make(map[string]interface{}, len(db.session.Variables))
+		for key, value := range db.session.Variables {
+			state.Variables[key] = value
+		}
+	}
+	return state
+}
+
+// RestoreSession rebuilds a session captured with SessionState: it selects the namespace and
+// database, authenticates with the token, and defines the variables. It stops once ctx is done.
+func (db *DB) RestoreSession(ctx context.Context, state SessionState) error {
+	db = db.WithContext(ctx)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if state.Namespace != "" || state.Database != "" {
+		if err := db.Use(state.Namespace, state.Database); err != nil {
+			return fmt.Errorf("restoring namespace and database: %w", err)
+		}
+	}
+
+	if state.Token != "" {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := db.Authenticate(state.Token); err != nil {
+			return fmt.Errorf("restoring authentication: %w", err)
+		}
+		db.setAuthentication(state.Token, state.User)
+	}
+
+	var errs []error
+	for key, value := range state.Variables {
+		if key == constants.AuthTokenKey {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := db.Let(key, value); err != nil {
+			errs = append(errs, fmt.Errorf("restoring variable %s: %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (db *DB) updateSession(update func(s *SessionState)) {
+	db.sessionLock.Lock()
+	defer db.sessionLock.Unlock()
+	update(&db.session)
+}
+
+// setVariable records a variable defined in the session.
+func (db *DB) setVariable(key string, val interface{}) {
+	db.updateSession(func(s *SessionState) {
+		if s.Variables == nil {
+			s.Variables = map[string]interface{}{}
+		}
+		s.Variables[key] = val
+	})
+}
+
+// setAuthentication records the token of the session, and the user it was issued for. When user
+// is empty, such as for record users and tokens, it is read from the claims of the token.
+func (db *DB) setAuthentication(token, user string) {
+	claims := readTokenClaims(token)
+	if user == "" {
+		user = claims.ID
+	}
+	db.updateSession(func(s *SessionState) {
+		s.Token = token
+		s.User = user
+		s.Access = claims.Access
+	})
+}
+
+func (db *DB) authenticatedUser() (user, access string) {
+	db.sessionLock.RLock()
+	defer db.sessionLock.RUnlock()
+	return db.session.User, db.session.Access
+}
+
+// tokenClaims are the claims of a token issued by SurrealDB that identify who it was issued for.
+type tokenClaims struct {
+	// ID is the record of a record user, or the name of a system user.
+	ID     string `json:"ID"`
+	Access string `json:"AC"`
+}
+
+// readTokenClaims reads the claims of a JWT without verifying it, which the server does, so that
+// the audit hook can tell who the connection is authenticated as. It returns no claims for
+// tokens it cannot read.
+func readTokenClaims(token string) tokenClaims {
+	var claims tokenClaims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return claims
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return tokenClaims{}
+	}
+	return claims
+}
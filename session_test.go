@@ -0,0 +1,64 @@
+package surrealdb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func (s *SurrealDBTestSuite) TestSessionState() {
+	s.Require().NoError(s.db.Let("owner", "john"))
+	defer func() {
+		s.Require().NoError(s.db.Unset("owner"))
+	}()
+
+	state := s.db.SessionState()
+	s.Equal("test", state.Namespace)
+	s.Equal("test", state.Database)
+	s.NotEmpty(state.Token)
+	s.Equal("john", state.Variables["owner"])
+
+	fresh, err := surrealdb.New(getURL())
+	s.Require().NoError(err)
+	defer fresh.Close()
+
+	s.Require().NoError(fresh.RestoreSession(context.Background(), state))
+	s.Equal(state, fresh.SessionState())
+
+	res, err := surrealdb.Query[string](fresh, "RETURN $owner", map[string]interface{}{})
+	s.Require().NoError(err)
+	s.Equal("john", (*res)[0].Result)
+
+	_, err = surrealdb.Select[[]testUser](fresh, models.Table("users"))
+	s.Require().NoError(err, "the fresh connection should be authenticated")
+}
+
+func TestRestoreSessionCanceled(t *testing.T) {
+	var lock sync.Mutex
+	var methods []string
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		lock.Lock()
+		methods = append(methods, req.Method)
+		lock.Unlock()
+		return nil, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = db.RestoreSession(ctx, surrealdb.SessionState{Namespace: "test", Database: "test", Token: "token"})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, db.SessionState().Namespace)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.NotContains(t, methods, "authenticate")
+}
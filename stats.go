@@ -0,0 +1,179 @@
+package surrealdb
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+)
+
+// Stats holds counters about the requests made through a DB.
+type Stats struct {
+	// Methods holds the request counters by RPC method. A request retried by the retry policy
+	// counts once, with the latency of all its attempts.
+	Methods map[string]MethodStats
+	// InFlight is the number of requests waiting for their response.
+	InFlight int64
+	// ConnectRetries is the number of failed attempts to connect made by Connect before it
+	// succeeded, see WithRetry.
+	ConnectRetries int
+	// DecodeErrors counts the results that could not be decoded into the requested type, by RPC
+	// method. A growing count usually means that the schema of the data drifted from the Go types.
+	DecodeErrors map[string]uint64
+	// LiveNotifications is the number of live query notifications delivered, and
+	// NotificationLag the total time they waited to be delivered once received.
+	LiveNotifications uint64
+	NotificationLag   time.Duration
+	// DroppedNotifications is the number of live query notifications dropped because their
+	// consumer had fallen too far behind, see WithNotificationBuffer.
+	DroppedNotifications uint64
+	// HTTP holds the request and connection reuse counters of the http engine. It is nil with
+	// other engines.
+	HTTP *connection.HTTPStats
+}
+
+// MethodStats holds the counters of the requests of an RPC method.
+type MethodStats struct {
+	Requests uint64
+	Errors   uint64
+	// TotalLatency is the sum of the latencies of the requests, and MaxLatency the highest.
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// MeanLatency returns the mean latency of the requests.
+func (s MethodStats) MeanLatency() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Requests)
+}
+
+// ErrorRate returns the share of the requests that failed, between 0 and 1.
+func (s MethodStats) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Requests)
+}
+
+type stats struct {
+	inFlight atomic.Int64
+
+	lock            sync.Mutex
+	methods         map[string]*MethodStats
+	decodeErrors    map[string]uint64
+	connectRetries  int
+	notifications   uint64
+	notificationLag time.Duration
+	dropped         uint64
+}
+
+func (s *stats) started() {
+	s.inFlight.Add(1)
+}
+
+// completed records a request started with started.
+func (s *stats) completed(method string, latency time.Duration, err error) {
+	s.inFlight.Add(-1)
+	s.record(err)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.methods == nil {
+		s.methods = map[string]*MethodStats{}
+	}
+	m, ok := s.methods[method]
+	if !ok {
+		m = &MethodStats{}
+		s.methods[method] = m
+	}
+	m.Requests++
+	if err != nil {
+		m.Errors++
+	}
+	m.TotalLatency += latency
+	if latency > m.MaxLatency {
+		m.MaxLatency = latency
+	}
+}
+
+func (s *stats) record(err error) {
+	var decodeErr *connection.DecodeError
+	if !errors.As(err, &decodeErr) {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.decodeErrors == nil {
+		s.decodeErrors = map[string]uint64{}
+	}
+	s.decodeErrors[decodeErr.Method]++
+}
+
+func (s *stats) notification(n connection.NotificationDelivery) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.notifications++
+	s.notificationLag += n.Lag
+}
+
+func (s *stats) notificationDropped() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.dropped++
+}
+
+// hooks returns hooks calling those given, and counting the notifications into s.
+func (s *stats) hooks(hooks *connection.Hooks) *connection.Hooks {
+	counted := connection.Hooks{}
+	if hooks != nil {
+		counted = *hooks
+	}
+	onNotification := counted.OnNotification
+	counted.OnNotification = func(n connection.NotificationDelivery) {
+		s.notification(n)
+		if onNotification != nil {
+			onNotification(n)
+		}
+	}
+	onDropped := counted.OnNotificationDropped
+	counted.OnNotificationDropped = func(n connection.NotificationDelivery) {
+		s.notificationDropped()
+		if onDropped != nil {
+			onDropped(n)
+		}
+	}
+	return &counted
+}
+
+// Stats returns a snapshot of the counters of the DB.
+func (db *DB) Stats() Stats {
+	db.stats.lock.Lock()
+	defer db.stats.lock.Unlock()
+
+	snapshot := Stats{
+		Methods:           make(map[string]MethodStats, len(db.stats.methods)),
+		InFlight:          db.stats.inFlight.Load(),
+		ConnectRetries:    db.stats.connectRetries,
+		DecodeErrors:      make(map[string]uint64, len(db.stats.decodeErrors)),
+		LiveNotifications: db.stats.notifications,
+		NotificationLag:   db.stats.notificationLag,
+
+		DroppedNotifications: db.stats.dropped,
+	}
+	for method, m := range db.stats.methods {
+		snapshot.Methods[method] = *m
+	}
+	for method, count := range db.stats.decodeErrors {
+		snapshot.DecodeErrors[method] = count
+	}
+	if httpCon, ok := db.con.(*connection.HTTPConnection); ok {
+		httpStats := httpCon.Stats()
+		snapshot.HTTP = &httpStats
+	}
+	return snapshot
+}
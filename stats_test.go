@@ -0,0 +1,44 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestStats(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		if req.Method == "delete" {
+			return nil, &connection.RPCError{Code: -32000, Message: "Not enough permissions to perform this action"}
+		}
+		return []interface{}{}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = surrealdb.Select[[]testUser](db, models.Table("users"))
+		require.NoError(t, err)
+	}
+	_, err = surrealdb.Delete[[]testUser](db, models.Table("users"))
+	require.Error(t, err)
+	_, err = surrealdb.Select[string](db, models.Table("users"))
+	require.Error(t, err)
+
+	stats := db.Stats()
+	require.Equal(t, uint64(4), stats.Methods["select"].Requests)
+	require.Equal(t, uint64(1), stats.Methods["select"].Errors)
+	require.Equal(t, 0.25, stats.Methods["select"].ErrorRate())
+	require.Equal(t, uint64(1), stats.Methods["delete"].Errors)
+	require.Positive(t, stats.Methods["select"].MeanLatency())
+	require.Equal(t, uint64(1), stats.DecodeErrors["select"])
+	require.Zero(t, stats.InFlight)
+	require.Zero(t, stats.ConnectRetries)
+}
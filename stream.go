@@ -0,0 +1,165 @@
+package surrealdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/internal/codec"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Rows iterates over the rows returned by a query, see QueryStream.
+type Rows[T any] struct {
+	unmarshaler codec.Unmarshaler
+	statements  []QueryResult[cbor.RawMessage]
+
+	// items reads the rows of the current statement, and single holds the result of a statement
+	// that is not an array, read as a single row.
+	items  *models.ArrayDecoder
+	single cbor.RawMessage
+
+	current T
+	err     error
+}
+
+// QueryStream runs sql, bounded by ctx, and returns an iterator over the rows of its statements,
+// in order. The result of a statement that is not an array, such as SELECT ONLY, is a single row.
+//
+// The whole response is received and held in memory, still encoded, before QueryStream returns:
+// it is not streamed from the server. Only the decoding is done row by row, as the iterator
+// advances, which spares holding every decoded row at once. A statement that failed on the server
+// stops the iteration with a QueryError.
+//
+//	rows, err := surrealdb.QueryStream[User](ctx, db, "SELECT * FROM user", nil)
+//	if err != nil {
+//		return err
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//		user := rows.Value()
+//	}
+//	return rows.Err()
+func QueryStream[T any](ctx context.Context, db *DB, sql string, vars map[string]interface{}) (*Rows[T], error) {
+	return queryStream[T](db.WithContext(ctx), sql, vars)
+}
+
+// queryStream runs sql like QueryStream, bounded by the context of db.
+func queryStream[T any](db *DB, sql string, vars map[string]interface{}) (*Rows[T], error) {
+	var res connection.RPCResponse[[]QueryResult[cbor.RawMessage]]
+	if err := db.send(&res, "query", sql, vars); err != nil {
+		return nil, err
+	}
+
+	rows := &Rows[T]{unmarshaler: db.con.GetUnmarshaler()}
+	if res.Result != nil {
+		rows.statements = *res.Result
+	}
+	return rows, nil
+}
+
+// QueryAll runs sql and returns the rows of its statements, in order, like QueryStream. It suits
+// the statements whose rows are not needed one at a time, or are few. Unlike Query, a statement
+// that failed on the server is returned as an error, along with the rows read before it.
+func QueryAll[T any](db *DB, sql string, vars map[string]interface{}) ([]T, error) {
+	rows, err := queryStream[T](db, sql, vars)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []T
+	for rows.Next() {
+		result = append(result, rows.Value())
+	}
+	return result, rows.Err()
+}
+
+// Next decodes the next row, making it available through Value. It returns false once the rows
+// are exhausted, or when an error occurred, which is then returned by Err.
+func (r *Rows[T]) Next() bool {
+	var zero T
+	r.current = zero
+	if r.err != nil {
+		return false
+	}
+
+	var raw cbor.RawMessage
+	for {
+		if r.single != nil {
+			raw, r.single = r.single, nil
+			break
+		}
+		if r.items != nil {
+			item, err := r.items.Next()
+			if err == nil {
+				raw = item
+				break
+			}
+			if !errors.Is(err, io.EOF) {
+				r.err = fmt.Errorf("invalid query result: %w", err)
+				return false
+			}
+			r.items = nil
+		}
+		if len(r.statements) == 0 {
+			return false
+		}
+		if r.err = r.nextStatement(); r.err != nil {
+			return false
+		}
+	}
+
+	if err := connection.DecodeResult(r.unmarshaler, "query", raw, &r.current); err != nil {
+		r.err = err
+		return false
+	}
+	return true
+}
+
+// Value returns the row decoded by the last call to Next.
+func (r *Rows[T]) Value() T {
+	return r.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (r *Rows[T]) Err() error {
+	return r.err
+}
+
+// Close releases the rows not read yet. Next returns false once the rows are closed.
+func (r *Rows[T]) Close() error {
+	r.statements = nil
+	r.items = nil
+	r.single = nil
+	return nil
+}
+
+// nextStatement moves the iteration to the rows of the next statement.
+func (r *Rows[T]) nextStatement() error {
+	stmt := r.statements[0]
+	r.statements = r.statements[1:]
+
+	if stmt.Status != "OK" {
+		var msg string
+		if err := r.unmarshaler.Unmarshal(stmt.Result, &msg); err != nil {
+			return err
+		}
+		return &QueryError{Message: msg}
+	}
+
+	if len(stmt.Result) == 0 {
+		return nil
+	}
+	if stmt.Result[0]>>5 != 4 {
+		// not an array: the whole result is one row
+		r.single = stmt.Result
+		return nil
+	}
+	r.items = models.NewArrayDecoder(bytes.NewReader(stmt.Result))
+	return nil
+}
@@ -0,0 +1,46 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+func TestQueryStream(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		return []surrealdb.QueryResult[interface{}]{
+			{Status: "OK", Result: []testUser{{Username: "a"}, {Username: "b"}}},
+			{Status: "OK", Result: []testUser{}},
+			{Status: "OK", Result: testUser{Username: "c"}},
+			{Status: "ERR", Result: "The query was not executed due to a failed transaction"},
+		}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	rows, err := surrealdb.QueryStream[testUser](context.Background(), db, "SELECT * FROM users", nil)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		names = append(names, rows.Value().Username)
+	}
+	require.Equal(t, []string{"a", "b", "c"}, names)
+	require.ErrorIs(t, rows.Err(), constants.ErrQuery)
+
+	all, err := surrealdb.QueryAll[testUser](db, "SELECT * FROM users", nil)
+	require.ErrorIs(t, err, constants.ErrQuery)
+	require.Equal(t, []testUser{{Username: "a"}, {Username: "b"}, {Username: "c"}}, all)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = surrealdb.QueryStream[testUser](ctx, db, "SELECT * FROM users", nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
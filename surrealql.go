@@ -0,0 +1,157 @@
+package surrealdb
+
+import (
+	"strings"
+	"unicode"
+)
+
+type statement struct {
+	// text is the statement without its trailing separator and spaces
+	text string
+	// separator is the ';' ending the statement, along with the spaces and comments around it
+	separator string
+	// keyword is the first word of the statement, in upper case
+	keyword string
+	// hasClauseAfterTimeout is set when the statement ends with a clause of clausesAfterTimeout
+	hasClauseAfterTimeout bool
+	// params are the names of the $parameters found in the statement, in order
+	params []string
+}
+
+// operandKeywords are the keywords followed by a field, a value or a table, where a word such as
+// timeout names a field rather than a clause.
+var operandKeywords = map[string]bool{
+	"SELECT": true, "VALUE": true, "ONLY": true, "FROM": true, "AS": true, "OMIT": true,
+	"CREATE": true, "UPDATE": true, "UPSERT": true, "DELETE": true, "RELATE": true, "INSERT": true,
+	"INTO": true, "SET": true, "UNSET": true, "CONTENT": true, "MERGE": true, "PATCH": true,
+	"REPLACE": true, "RETURN": true, "WHERE": true, "BY": true, "SPLIT": true, "FETCH": true,
+	"INDEX": true, "LIMIT": true, "START": true, "AT": true, "AND": true, "OR": true, "NOT": true,
+	"IS": true, "IN": true, "CONTAINS": true, "CONTAINSNOT": true, "CONTAINSALL": true,
+	"CONTAINSANY": true, "CONTAINSNONE": true, "INSIDE": true, "NOTINSIDE": true, "ALLINSIDE": true,
+	"ANYINSIDE": true, "NONEINSIDE": true, "OUTSIDE": true, "INTERSECTS": true,
+}
+
+// splitStatements splits sql into statements, ignoring the semicolons and keywords found in
+// strings, identifiers, comments and blocks.
+//
+// A clause of clausesAfterTimeout is only recognized in clause position, following a complete
+// field, value or table: a word following an operator, a comma or one of the operandKeywords,
+// such as in SELECT timeout FROM job or ORDER BY parallel, names a field instead.
+func splitStatements(sql string) []statement {
+	var statements []statement
+	runes := []rune(sql)
+
+	start := 0
+	lastToken := 0 // end of the last token of the current statement
+	depth := 0
+	current := statement{}
+	// afterOperand is set when the last token at depth 0 completes a field, value or table
+	afterOperand := false
+
+	word := func(from int) int {
+		to := from
+		for to < len(runes) && (unicode.IsLetter(runes[to]) || unicode.IsDigit(runes[to]) || runes[to] == '_') {
+			to++
+		}
+		return to
+	}
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == '-' && i+1 < len(runes) && runes[i+1] == '-',
+			c == '/' && i+1 < len(runes) && runes[i+1] == '/',
+			c == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+			continue
+		case c == '\'' || c == '"' || c == '`' || c == '⟨':
+			closing := c
+			if c == '⟨' {
+				closing = '⟩'
+			}
+			i++
+			for i < len(runes) && runes[i] != closing {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+			if i > len(runes) {
+				i = len(runes)
+			}
+			lastToken = i
+			if depth == 0 {
+				afterOperand = true
+			}
+			continue
+		case c == '(' || c == '{' || c == '[':
+			depth++
+		case c == ')' || c == '}' || c == ']':
+			depth--
+		case c == ';' && depth <= 0:
+			current.text = string(runes[start:lastToken])
+			current.separator = string(runes[lastToken : i+1])
+			statements = append(statements, current)
+			current = statement{}
+			start = i + 1
+			lastToken = start
+			depth = 0
+			afterOperand = false
+			i++
+			continue
+		case c == '$':
+			end := word(i + 1)
+			if end > i+1 {
+				current.params = append(current.params, string(runes[i+1:end]))
+			}
+			i = end
+			lastToken = i
+			if depth == 0 {
+				afterOperand = true
+			}
+			continue
+		case unicode.IsLetter(c) || c == '_':
+			end := word(i)
+			if depth == 0 {
+				keyword := strings.ToUpper(string(runes[i:end]))
+				if current.keyword == "" && lastToken == start {
+					current.keyword = keyword
+				}
+				if clausesAfterTimeout[keyword] && afterOperand {
+					current.hasClauseAfterTimeout = true
+				}
+				afterOperand = !operandKeywords[keyword]
+			}
+			i = end
+			lastToken = i
+			continue
+		}
+
+		if !unicode.IsSpace(c) {
+			lastToken = i + 1
+			if depth == 0 {
+				// digits end a number and closing brackets a block, other symbols are operators
+				afterOperand = unicode.IsDigit(c) || c == ')' || c == '}' || c == ']'
+			}
+		}
+		i++
+	}
+
+	if start < len(runes) {
+		current.text = string(runes[start:lastToken])
+		current.separator = string(runes[lastToken:])
+		statements = append(statements, current)
+	}
+
+	return statements
+}
@@ -0,0 +1,145 @@
+package surrealdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// Identifier is the value of an identifier placeholder of a template, see Tmpl.
+type Identifier struct {
+	Name  string
+	value string
+	path  bool
+}
+
+// Ident binds the placeholder {name} to a table, field or other identifier, escaped with
+// models.EscapeIdent, so it may hold any character.
+func Ident(name, value string) Identifier {
+	return Identifier{Name: name, value: value}
+}
+
+// FieldPath binds the placeholder {name} to a field path such as address.city, escaping every
+// part of the path separately with models.EscapeFieldPath.
+func FieldPath(name, path string) Identifier {
+	return Identifier{Name: name, value: path, path: true}
+}
+
+// Tmpl replaces the {name} placeholders of a query with escaped identifiers, for the cases where
+// a table or field name must vary and cannot be bound as a $variable. Values must still be bound as
+// variables. For example:
+//
+//	sql, err := surrealdb.Tmpl("SELECT * FROM {table} WHERE {field} = $value",
+//		surrealdb.Ident("table", table), surrealdb.FieldPath("field", field))
+//
+// A placeholder is a name made of letters, digits and underscores between braces, with no spaces.
+// Braces inside strings and escaped identifiers are kept as they are. Every placeholder must be
+// bound, and every identifier must be used.
+func Tmpl(query string, idents ...Identifier) (string, error) {
+	values := make(map[string]string, len(idents))
+	for _, ident := range idents {
+		if _, defined := values[ident.Name]; defined {
+			return "", fmt.Errorf("identifier %s is bound twice", ident.Name)
+		}
+		escaped, err := ident.escape()
+		if err != nil {
+			return "", fmt.Errorf("identifier %s: %w", ident.Name, err)
+		}
+		values[ident.Name] = escaped
+	}
+
+	used := make(map[string]bool, len(idents))
+	var b strings.Builder
+	for i := 0; i < len(query); {
+		if quote, ok := openingQuote(query[i:]); ok {
+			end := i + len(quote) + closingQuote(query[i+len(quote):], quote)
+			b.WriteString(query[i:end])
+			i = end
+			continue
+		}
+		if query[i] != '{' {
+			b.WriteByte(query[i])
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(query[i:], '}')
+		name := ""
+		if end > 0 {
+			name = query[i+1 : i+end]
+		}
+		if !isPlaceholderName(name) {
+			// not a placeholder, such as an object or a block
+			b.WriteByte('{')
+			i++
+			continue
+		}
+
+		value, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("placeholder {%s} is not bound", name)
+		}
+		used[name] = true
+		b.WriteString(value)
+		i += end + 1
+	}
+
+	for name := range values {
+		if !used[name] {
+			return "", fmt.Errorf("identifier %s is not used", name)
+		}
+	}
+
+	return b.String(), nil
+}
+
+func (i Identifier) escape() (string, error) {
+	if i.path {
+		return models.EscapeFieldPath(i.value)
+	}
+	if i.value == "" {
+		return "", fmt.Errorf("empty identifier")
+	}
+	return models.EscapeIdent(i.value), nil
+}
+
+// quotes maps the opening quotes of SurrealQL strings and escaped identifiers to their closing quote.
+var quotes = map[string]string{"'": "'", `"`: `"`, "`": "`", "⟨": "⟩"}
+
+// openingQuote returns the quote s starts with, if any.
+func openingQuote(s string) (string, bool) {
+	for quote := range quotes {
+		if strings.HasPrefix(s, quote) {
+			return quote, true
+		}
+	}
+	return "", false
+}
+
+// closingQuote returns the length of s up to and including the quote closing opening, skipping
+// the characters escaped with a backslash, or the length of s when the quote is not closed.
+func closingQuote(s, opening string) int {
+	closing := quotes[opening]
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case strings.HasPrefix(s[i:], closing):
+			return i + len(closing)
+		}
+	}
+	return len(s)
+}
+
+func isPlaceholderName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package surrealdb_test
+
+import (
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTmpl(t *testing.T) {
+	t.Run("identifiers are escaped", func(t *testing.T) {
+		sql, err := surrealdb.Tmpl("SELECT {field} FROM {table} WHERE {field} = $value",
+			surrealdb.Ident("table", "users⟩; DELETE users; SELECT * FROM ⟨x"),
+			surrealdb.FieldPath("field", "address.city"),
+		)
+		require.NoError(t, err)
+		require.Equal(t,
+			`SELECT address.city FROM ⟨users\⟩; DELETE users; SELECT * FROM ⟨x⟩ WHERE address.city = $value`,
+			sql)
+
+		sql, err = surrealdb.Tmpl("SELECT {field} FROM {table}",
+			surrealdb.Ident("table", `users\`), surrealdb.FieldPath("field", "select.1st"))
+		require.NoError(t, err)
+		require.Equal(t, `SELECT ⟨select⟩.⟨1st⟩ FROM ⟨users\\⟩`, sql)
+	})
+
+	t.Run("objects and blocks are kept", func(t *testing.T) {
+		sql, err := surrealdb.Tmpl("CREATE {table} CONTENT { name: $name, tags: {} }", surrealdb.Ident("table", "users"))
+		require.NoError(t, err)
+		require.Equal(t, "CREATE users CONTENT { name: $name, tags: {} }", sql)
+	})
+
+	t.Run("strings and escaped identifiers are kept", func(t *testing.T) {
+		sql, err := surrealdb.Tmpl(`SELECT * FROM {table} WHERE a = '{table}' OR b = "it\"s {table}" OR ⟨{table}⟩ = `+"`{table}`",
+			surrealdb.Ident("table", "users"))
+		require.NoError(t, err)
+		require.Equal(t, `SELECT * FROM users WHERE a = '{table}' OR b = "it\"s {table}" OR ⟨{table}⟩ = `+"`{table}`", sql)
+	})
+
+	invalid := map[string]func() (string, error){
+		"unbound placeholder": func() (string, error) {
+			return surrealdb.Tmpl("SELECT * FROM {table}")
+		},
+		"unused identifier": func() (string, error) {
+			return surrealdb.Tmpl("SELECT * FROM users", surrealdb.Ident("table", "users"))
+		},
+		"identifier bound twice": func() (string, error) {
+			return surrealdb.Tmpl("SELECT * FROM {table}", surrealdb.Ident("table", "a"), surrealdb.Ident("table", "b"))
+		},
+		"empty identifier": func() (string, error) {
+			return surrealdb.Tmpl("SELECT * FROM {table}", surrealdb.Ident("table", ""))
+		},
+		"empty field path part": func() (string, error) {
+			return surrealdb.Tmpl("SELECT {field} FROM users", surrealdb.FieldPath("field", "address..city"))
+		},
+	}
+	for name, tmpl := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := tmpl()
+			require.Error(t, err)
+		})
+	}
+}
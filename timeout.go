@@ -0,0 +1,82 @@
+package surrealdb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// WithDeadlinePropagation sends the deadline of the context of the DB, see DB.WithContext, to the
+// server along with queries. A TIMEOUT clause with the remaining time is added to the SELECT,
+// CREATE, UPDATE, UPSERT, DELETE and RELATE statements of the query, so that the server aborts them
+// instead of running on after the client gave up. Statements that already have a TIMEOUT clause,
+// or a PARALLEL, TEMPFILES or EXPLAIN clause that must follow it, are left as they are.
+func WithDeadlinePropagation() Option {
+	return func(c *config) error {
+		c.propagateDeadline = true
+		return nil
+	}
+}
+
+// timeoutStatements are the statements accepting a TIMEOUT clause
+var timeoutStatements = map[string]bool{
+	"SELECT": true,
+	"CREATE": true,
+	"UPDATE": true,
+	"UPSERT": true,
+	"DELETE": true,
+	"RELATE": true,
+}
+
+// clausesAfterTimeout are the clauses that must follow a TIMEOUT clause
+var clausesAfterTimeout = map[string]bool{
+	"TIMEOUT":   true,
+	"PARALLEL":  true,
+	"TEMPFILES": true,
+	"EXPLAIN":   true,
+}
+
+// withDeadline returns the params of a query request with the deadline of ctx, the context of the
+// request, added to its statements.
+func (db *DB) withDeadline(ctx context.Context, method string, params []interface{}) []interface{} {
+	if !db.propagateDeadline || method != "query" || ctx == nil || len(params) == 0 {
+		return params
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return params
+	}
+	// a prepared query encoded once is sent as a string again, with the timeout
+	sql := connection.RequestStart{Method: method, Params: params}.Statement()
+	if sql == "" {
+		return params
+	}
+
+	// the server takes milliseconds, round up so that the timeout is never zero
+	remaining := time.Until(deadline).Truncate(time.Millisecond) + time.Millisecond
+	if remaining <= 0 {
+		return params
+	}
+
+	withTimeout := append([]interface{}{}, params...)
+	withTimeout[0] = addStatementTimeout(sql, remaining)
+	return withTimeout
+}
+
+// addStatementTimeout adds a TIMEOUT clause to the statements of sql that accept one.
+func addStatementTimeout(sql string, timeout time.Duration) string {
+	clause := " TIMEOUT " + models.FormatDuration(int64(timeout))
+
+	var b strings.Builder
+	for _, stmt := range splitStatements(sql) {
+		b.WriteString(stmt.text)
+		if timeoutStatements[stmt.keyword] && !stmt.hasClauseAfterTimeout {
+			b.WriteString(clause)
+		}
+		b.WriteString(stmt.separator)
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package surrealdb_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestDeadlinePropagation(t *testing.T) {
+	queries := make(chan string, 1)
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		queries <- req.Params[0].(string)
+		return []interface{}{}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithDeadlinePropagation(),
+	)
+	require.NoError(t, err)
+
+	sql := "-- users; with a comment\n" +
+		"SELECT * FROM users WHERE name = 'a;b' AND tags CONTAINS { x: [1, 2] };\n" +
+		"LET $x = 1;\n" +
+		"UPDATE users SET seen = true TIMEOUT 1s;\n" +
+		"SELECT * FROM users PARALLEL;\n" +
+		"CREATE ⟨odd;table⟩ CONTENT { a: (SELECT * FROM b) }"
+
+	t.Run("without deadline", func(t *testing.T) {
+		_, err := surrealdb.Query[interface{}](db, sql, map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, sql, <-queries)
+	})
+
+	t.Run("with deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		_, err := surrealdb.Query[interface{}](db.WithContext(ctx), sql, map[string]interface{}{})
+		require.NoError(t, err)
+		sent := <-queries
+		require.Regexp(t, `name = 'a;b' AND tags CONTAINS \{ x: \[1, 2\] \} TIMEOUT [0-9hms]+;\n`, sent)
+		require.Contains(t, sent, "LET $x = 1;\n")
+		require.Contains(t, sent, "UPDATE users SET seen = true TIMEOUT 1s;\n")
+		require.Contains(t, sent, "SELECT * FROM users PARALLEL;\n")
+		require.Regexp(t, `CREATE ⟨odd;table⟩ CONTENT \{ a: \(SELECT \* FROM b\) \} TIMEOUT [0-9hms]+$`, sent)
+	})
+
+	t.Run("fields named like a clause", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		for _, sql := range []string{
+			"SELECT timeout FROM job",
+			"SELECT id, timeout AS parallel FROM job",
+			"SELECT * FROM timeout",
+			"SELECT * FROM job WHERE timeout > 1s AND explain = true",
+			"SELECT * FROM job ORDER BY timeout DESC",
+			"SELECT * FROM job ORDER BY tempfiles",
+			"UPDATE job SET timeout = 5s, parallel = false",
+		} {
+			_, err := surrealdb.Query[interface{}](db.WithContext(ctx), sql, map[string]interface{}{})
+			require.NoError(t, err)
+			require.Regexp(t, `^`+regexp.QuoteMeta(sql)+` TIMEOUT [0-9hms]+$`, <-queries)
+		}
+
+		_, err := surrealdb.Query[interface{}](db.WithContext(ctx), "SELECT timeout FROM job ORDER BY timeout EXPLAIN", map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, "SELECT timeout FROM job ORDER BY timeout EXPLAIN", <-queries)
+	})
+
+	t.Run("waiting stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := surrealdb.Query[interface{}](db.WithContext(ctx), "SELECT * FROM users", map[string]interface{}{})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("handles keep their own context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		canceled := db.WithContext(ctx)
+
+		// the context of db is not replaced by the one of the handle
+		_, err := surrealdb.Query[interface{}](db, "SELECT * FROM users", map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM users", <-queries)
+
+		_, err = surrealdb.Query[interface{}](canceled, "SELECT * FROM users", map[string]interface{}{})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
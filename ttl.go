@@ -0,0 +1,146 @@
+package surrealdb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+const (
+	// DefaultExpiryField is the field holding the expiration datetime of a record.
+	DefaultExpiryField = "expires_at"
+	// DefaultPurgeBatchSize is the number of records deleted per statement by PurgeExpired.
+	DefaultPurgeBatchSize = 1000
+)
+
+// ExpiryPolicy describes the expiring records of a table, such as sessions, tokens or cache entries.
+type ExpiryPolicy struct {
+	Table models.Table
+	// Field holds the expiration datetime of the records. It defaults to DefaultExpiryField.
+	// Records without it never expire.
+	Field string
+	// BatchSize is the number of records deleted per statement. It defaults to DefaultPurgeBatchSize.
+	BatchSize int
+}
+
+// ExpiresIn returns the expiration datetime of a record living for ttl, to be stored in the expiry
+// field. Expiration is checked against the server clock, so the datetime is shifted by the clock
+// skew of db when compensated, see WithClockSkewCompensation.
+func (db *DB) ExpiresIn(ttl time.Duration) *models.CustomDateTime {
+	return &models.CustomDateTime{Time: db.toServerTime(time.Now().Add(ttl))}
+}
+
+// withDefaults returns p with its defaults set, along with its escaped expiry field.
+func (p ExpiryPolicy) withDefaults() (ExpiryPolicy, string, error) {
+	if p.Table == "" {
+		return p, "", fmt.Errorf("expiry policy needs a table")
+	}
+	if p.Field == "" {
+		p.Field = DefaultExpiryField
+	}
+	field, err := models.EscapeFieldPath(p.Field)
+	if err != nil {
+		return p, "", fmt.Errorf("invalid expiry field: %w", err)
+	}
+	if p.BatchSize == 0 {
+		p.BatchSize = DefaultPurgeBatchSize
+	}
+	if p.BatchSize < 0 {
+		return p, "", fmt.Errorf("batch size must be positive")
+	}
+	return p, field, nil
+}
+
+// PurgeExpired deletes the expired records of a table, in batches of policy.BatchSize, and returns
+// the number of deleted records. Expiration is checked against the server clock.
+func PurgeExpired(db *DB, policy ExpiryPolicy) (int, error) {
+	policy, field, err := policy.withDefaults()
+	if err != nil {
+		return 0, err
+	}
+
+	sql := fmt.Sprintf(
+		"DELETE (SELECT VALUE id FROM $table WHERE %s <= time::now() LIMIT $limit) RETURN BEFORE",
+		field,
+	)
+	vars := map[string]interface{}{"table": policy.Table, "limit": policy.BatchSize}
+
+	purged := 0
+	for {
+		deleted, err := querySingle[[]cbor.RawMessage](db, sql, vars)
+		if err != nil {
+			return purged, err
+		}
+		purged += len(*deleted)
+		if len(*deleted) < policy.BatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// StartPurger calls PurgeExpired for every policy each interval, until the returned function is
+// called or the context of the DB is done. Failures are logged.
+func StartPurger(db *DB, interval time.Duration, policies ...ExpiryPolicy) (stop func()) {
+	done := make(chan struct{})
+	var ctxDone <-chan struct{}
+	if db.ctx != nil {
+		ctxDone = db.ctx.Done()
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctxDone:
+				return
+			case <-ticker.C:
+			}
+
+			for _, policy := range policies {
+				if _, err := PurgeExpired(db, policy); err != nil {
+					db.logger.Warn("failed to purge expired records", "table", policy.Table.String(), "error", err.Error())
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// ExpiryEventStatement returns the DEFINE EVENT statement letting the server delete the records
+// of a table that are written already expired. The event only checks the record created or
+// updated, so records expiring later are left to PurgeExpired or StartPurger. The statement
+// replaces an event of the same name, which requires SurrealDB 2.0 or later.
+func ExpiryEventStatement(policy ExpiryPolicy) (string, error) {
+	policy, field, err := policy.withDefaults()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"DEFINE EVENT OVERWRITE %s ON TABLE %s WHEN ($event = 'CREATE' OR $event = 'UPDATE') AND $after.%s <= time::now() THEN (DELETE $after.id)",
+		models.EscapeIdent("expire_"+policy.Field), policy.Table.SurrealString(), field,
+	), nil
+}
+
+// DefineExpiryEvent defines the event returned by ExpiryEventStatement, replacing the event
+// defined for an earlier policy of the same field.
+func DefineExpiryEvent(db *DB, policy ExpiryPolicy) error {
+	sql, err := ExpiryEventStatement(policy)
+	if err != nil {
+		return err
+	}
+
+	_, err = querySingle[cbor.RawMessage](db, sql, nil)
+	return err
+}
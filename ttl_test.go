@@ -0,0 +1,83 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestExpiryEventStatement(t *testing.T) {
+	sql, err := surrealdb.ExpiryEventStatement(surrealdb.ExpiryPolicy{Table: "sessions", Field: "meta.expires at"})
+	require.NoError(t, err)
+	require.Equal(t,
+		"DEFINE EVENT OVERWRITE ⟨expire_meta.expires at⟩ ON TABLE sessions WHEN ($event = 'CREATE' OR $event = 'UPDATE') AND $after.meta.⟨expires at⟩ <= time::now() THEN (DELETE $after.id)",
+		sql)
+}
+
+func TestExpiresIn(t *testing.T) {
+	const skew = time.Hour
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		if req.Method != "query" {
+			return nil, nil
+		}
+		return []surrealdb.QueryResult[interface{}]{{Status: "OK", Result: models.CustomDateTime{Time: time.Now().Add(skew)}}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Minute), db.ExpiresIn(time.Minute).Time, time.Second)
+
+	db, err = surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"),
+		surrealdb.WithClockSkewCompensation())
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(skew+time.Minute), db.ExpiresIn(time.Minute).Time, time.Second,
+		"the expiration follows the server clock")
+}
+
+func (s *SurrealDBTestSuite) TestPurgeExpired() {
+	for i := 0; i < 5; i++ {
+		ttl := -time.Minute
+		if i%2 == 0 {
+			ttl = time.Hour
+		}
+		_, err := surrealdb.Create[testUser](s.db, models.NewRecordID("users", i), map[string]interface{}{
+			surrealdb.DefaultExpiryField: s.db.ExpiresIn(ttl),
+		})
+		s.Require().NoError(err)
+	}
+	_, err := surrealdb.Create[testUser](s.db, models.NewRecordID("users", "forever"), map[string]interface{}{})
+	s.Require().NoError(err)
+
+	purged, err := surrealdb.PurgeExpired(s.db, surrealdb.ExpiryPolicy{Table: "users", BatchSize: 1})
+	s.Require().NoError(err)
+	s.Equal(2, purged)
+
+	users, err := surrealdb.Select[[]testUser](s.db, models.Table("users"))
+	s.Require().NoError(err)
+	s.Len(*users, 4)
+
+	s.Run("expiry event", func() {
+		policy := surrealdb.ExpiryPolicy{Table: "users"}
+		s.Require().NoError(surrealdb.DefineExpiryEvent(s.db, policy))
+		s.Require().NoError(surrealdb.DefineExpiryEvent(s.db, policy), "defining the event again replaces it")
+		defer func() {
+			_, err := surrealdb.Query[interface{}](s.db, "REMOVE EVENT expire_expires_at ON TABLE users", map[string]interface{}{})
+			s.NoError(err)
+		}()
+
+		_, err := surrealdb.Create[testUser](s.db, models.NewRecordID("users", "expired"), map[string]interface{}{
+			surrealdb.DefaultExpiryField: s.db.ExpiresIn(-time.Minute),
+		})
+		s.Require().NoError(err)
+
+		users, err := surrealdb.Select[[]testUser](s.db, models.Table("users"))
+		s.Require().NoError(err)
+		s.Len(*users, 4)
+	})
+}
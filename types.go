@@ -1,17 +1,50 @@
 package surrealdb
 
 import (
+	"fmt"
+
 	"github.com/fxamacker/cbor/v2"
 	"github.com/surrealdb/surrealdb.go/internal/codec"
+	"github.com/surrealdb/surrealdb.go/pkg/connection"
 	"github.com/surrealdb/surrealdb.go/pkg/constants"
 	"github.com/surrealdb/surrealdb.go/pkg/models"
 )
 
-// Patch represents a patch object set to MODIFY a record
+// PatchData is a single JSON Patch (RFC 6902) operation, as sent to the patch method or returned
+// by RETURN DIFF
 type PatchData struct {
-	Op    string `json:"op"`
-	Path  string `json:"path"`
-	Value any    `json:"value"`
+	Op    PatchOperation `json:"op"`
+	Path  string         `json:"path"`
+	From  string         `json:"from,omitempty"`
+	Value any            `json:"value"`
+}
+
+// PatchOperation is the operation of a JSON Patch (RFC 6902) entry
+type PatchOperation = string
+
+const (
+	PatchAdd     PatchOperation = "add"
+	PatchRemove  PatchOperation = "remove"
+	PatchReplace PatchOperation = "replace"
+	PatchMove    PatchOperation = "move"
+	PatchCopy    PatchOperation = "copy"
+	PatchTest    PatchOperation = "test"
+	// PatchChange is the SurrealDB extension used in diffs to describe a text change
+	PatchChange PatchOperation = "change"
+)
+
+// MarshalCBOR encodes the value of add, replace, test and change operations even when it is nil,
+// so that a field can be set to null, and leaves it out of the other operations.
+func (p PatchData) MarshalCBOR() ([]byte, error) {
+	data := map[string]interface{}{"op": p.Op, "path": p.Path}
+	if p.From != "" {
+		data["from"] = p.From
+	}
+	switch p.Op {
+	case PatchAdd, PatchReplace, PatchTest, PatchChange:
+		data["value"] = p.Value
+	}
+	return models.CborMarshaler{}.Marshal(data)
 }
 
 type QueryResult[T any] struct {
@@ -20,6 +53,27 @@ type QueryResult[T any] struct {
 	Result T      `json:"result"`
 }
 
+// QueryError is the error of a statement that failed on the server. It matches constants.ErrQuery
+// with errors.Is, as well as the stable error value of the server message when it is known,
+// such as constants.ErrAlreadyExists.
+type QueryError struct {
+	Message string
+}
+
+func (e *QueryError) Error() string {
+	if _, hint := connection.ClassifyError(0, e.Message); hint != "" {
+		return fmt.Sprintf("%s: %s (hint: %s)", constants.ErrQuery, e.Message, hint)
+	}
+	return fmt.Sprintf("%s: %s", constants.ErrQuery, e.Message)
+}
+
+func (e *QueryError) Unwrap() []error {
+	if kind, _ := connection.ClassifyError(0, e.Message); kind != nil {
+		return []error{constants.ErrQuery, kind}
+	}
+	return []error{constants.ErrQuery}
+}
+
 type QueryStmt struct {
 	unmarshaler codec.Unmarshaler
 	SQL         string
@@ -31,7 +85,7 @@ func (q *QueryStmt) GetResult(dest interface{}) error {
 	if q.unmarshaler == nil {
 		return constants.ErrNoUnmarshaler
 	}
-	return q.unmarshaler.Unmarshal(q.Result.Result, dest)
+	return connection.DecodeResult(q.unmarshaler, "query", q.Result.Result, dest)
 }
 
 type Relationship struct {
@@ -59,5 +113,11 @@ type Result[T any] struct {
 }
 
 type TableOrRecord interface {
-	string | models.Table | models.RecordID | []models.Table | []models.RecordID
+	string | models.Table | models.RecordID | models.RecordIDRange
+}
+
+// TablesOrRecords is a TableOrRecord, or several tables or records, accepted by the functions
+// reading or deleting records, which handle them with a query.
+type TablesOrRecords interface {
+	TableOrRecord | []models.Table | []models.RecordID
 }
@@ -0,0 +1,21 @@
+package surrealdb_test
+
+import (
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+func TestQueryError(t *testing.T) {
+	err := error(&surrealdb.QueryError{Message: "Database record `users:1` already exists"})
+	require.ErrorIs(t, err, constants.ErrQuery)
+	require.ErrorIs(t, err, constants.ErrAlreadyExists)
+	require.Contains(t, err.Error(), "UPSERT")
+
+	err = &surrealdb.QueryError{Message: "Something unexpected"}
+	require.ErrorIs(t, err, constants.ErrQuery)
+	require.NotErrorIs(t, err, constants.ErrAlreadyExists)
+}
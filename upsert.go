@@ -0,0 +1,83 @@
+package surrealdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UpsertMode is how the Data of UpsertClauses is written into the records.
+type UpsertMode string
+
+const (
+	// UpsertContent replaces the records with the data, keeping their id.
+	UpsertContent UpsertMode = "CONTENT"
+	// UpsertMerge merges the fields of the data into the records.
+	UpsertMerge UpsertMode = "MERGE"
+	// UpsertPatch applies JSON Patch operations, a []PatchData, to the records.
+	UpsertPatch UpsertMode = "PATCH"
+	// UpsertReplace replaces the records with the data, like UpsertContent, failing on the fields
+	// that cannot be changed.
+	UpsertReplace UpsertMode = "REPLACE"
+)
+
+// UpsertClauses are the clauses of an UPSERT statement, see UpsertWith. The records are written
+// either with the Set assignments, or with the Data according to the Mode.
+type UpsertClauses struct {
+	Set  []Assignment
+	Mode UpsertMode
+	Data interface{}
+	// Where, which may be nil, restricts the records written.
+	Where *Condition
+	// Return is NONE, BEFORE, AFTER, DIFF or a projection of the records written, AFTER when empty.
+	Return string
+}
+
+// UpsertWith creates or updates the records of what, a table or a record, with an UPSERT
+// statement, and returns what its RETURN clause selects. Unlike Upsert, the records can be merged
+// or patched rather than replaced, and some of their fields set from SurrealQL expressions.
+//
+//	counters, err := surrealdb.UpsertWith[Counter](db, models.NewRecordID("counter", "visits"), surrealdb.UpsertClauses{
+//		Set: []surrealdb.Assignment{surrealdb.SetExpr("count", "count + 1")},
+//	})
+func UpsertWith[TResult any, TWhat TableOrRecord](db *DB, what TWhat, clauses UpsertClauses) ([]TResult, error) {
+	vars := map[string]interface{}{"upsert_what": queryTarget(what)}
+	sql := "UPSERT $upsert_what"
+
+	switch {
+	case len(clauses.Set) > 0 && clauses.Mode != "":
+		return nil, fmt.Errorf("an upsert either sets fields or writes data with a mode, not both")
+	case len(clauses.Set) > 0:
+		set, err := assignments(clauses.Set, vars)
+		if err != nil {
+			return nil, err
+		}
+		sql += " SET " + set
+	case clauses.Mode != "":
+		switch clauses.Mode {
+		case UpsertContent, UpsertMerge, UpsertPatch, UpsertReplace:
+		default:
+			return nil, fmt.Errorf("invalid upsert mode %q", clauses.Mode)
+		}
+		sql += " " + string(clauses.Mode) + " $upsert_data"
+		vars["upsert_data"] = clauses.Data
+	}
+
+	if clauses.Where != nil {
+		for name, value := range clauses.Where.Vars {
+			if _, bound := vars[name]; bound {
+				return nil, fmt.Errorf("variable $%s is bound by both the assignments and the condition", name)
+			}
+			vars[name] = value
+		}
+		sql += " WHERE " + clauses.Where.SQL
+	}
+	if clauses.Return != "" {
+		sql += " RETURN " + strings.TrimSpace(clauses.Return)
+	}
+
+	records, err := querySingle[[]TResult](db, sql, vars)
+	if err != nil {
+		return nil, err
+	}
+	return *records, nil
+}
@@ -0,0 +1,56 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+	"github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+func TestUpsertWith(t *testing.T) {
+	type counter struct {
+		Count int `json:"count"`
+	}
+
+	var sql string
+	var vars map[interface{}]interface{}
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		sql = req.Params[0].(string)
+		vars = req.Params[1].(map[interface{}]interface{})
+		return []surrealdb.QueryResult[interface{}]{{Status: "OK", Result: []counter{{Count: 2}}}}, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	counters, err := surrealdb.UpsertWith[counter](db, models.NewRecordID("counter", "visits"), surrealdb.UpsertClauses{
+		Set: []surrealdb.Assignment{surrealdb.SetExpr("count", "count + 1"), surrealdb.Set("page", "/")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []counter{{Count: 2}}, counters)
+	require.Equal(t, "UPSERT $upsert_what SET count = count + 1, page = $set_page", sql)
+	require.Equal(t, models.NewRecordID("counter", "visits"), vars["upsert_what"])
+	require.Equal(t, "/", vars["set_page"])
+
+	_, err = surrealdb.UpsertWith[counter](db, "counter", surrealdb.UpsertClauses{
+		Mode:   surrealdb.UpsertMerge,
+		Data:   map[string]interface{}{"archived": true},
+		Where:  &surrealdb.Condition{SQL: "count < $min", Vars: map[string]interface{}{"min": 10}},
+		Return: "DIFF",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "UPSERT $upsert_what MERGE $upsert_data WHERE count < $min RETURN DIFF", sql)
+	require.Equal(t, models.Table("counter"), vars["upsert_what"])
+	require.EqualValues(t, 10, vars["min"])
+
+	_, err = surrealdb.UpsertWith[counter](db, "counter", surrealdb.UpsertClauses{
+		Set: []surrealdb.Assignment{surrealdb.Set("count", 0)}, Mode: surrealdb.UpsertContent,
+	})
+	require.Error(t, err)
+	_, err = surrealdb.UpsertWith[counter](db, "counter", surrealdb.UpsertClauses{Mode: "UNSET"})
+	require.Error(t, err)
+}
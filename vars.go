@@ -0,0 +1,144 @@
+package surrealdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/surrealdb/surrealdb.go/pkg/constants"
+)
+
+// Var is a variable of the connection holding a value of type T, defined with LetTyped or
+// ScopedLet. It formats as its $parameter, to be used in queries in place of the variable name.
+type Var[T any] struct {
+	name  string
+	value T
+}
+
+// Name returns the name of the variable, without the leading $.
+func (v Var[T]) Name() string {
+	return v.name
+}
+
+// Value returns the value the variable was defined with.
+func (v Var[T]) Value() T {
+	return v.value
+}
+
+// String returns the $parameter of the variable.
+func (v Var[T]) String() string {
+	return "$" + v.name
+}
+
+// LetTyped defines the variable name of the connection, like Let, with a request bounded by ctx,
+// and returns it as a Var so that queries refer to it without repeating its name:
+//
+//	minAge, err := surrealdb.LetTyped(ctx, db, "min_age", 18)
+//	res, err := surrealdb.Query[[]User](db, "SELECT * FROM user WHERE age >= "+minAge.String(), nil)
+func LetTyped[T any](ctx context.Context, db *DB, name string, value T) (Var[T], error) {
+	name, err := varName(name)
+	if err != nil {
+		return Var[T]{}, err
+	}
+	if err := db.WithContext(ctx).send(nil, "let", name, value); err != nil {
+		return Var[T]{}, err
+	}
+	db.setVariable(name, value)
+	return Var[T]{name: name, value: value}, nil
+}
+
+// VarScope groups variables defined for the duration of some queries, see DB.VarScope.
+type VarScope struct {
+	db *DB
+
+	mu       sync.Mutex
+	defined  []string
+	previous map[string]interface{}
+	closed   bool
+}
+
+// VarScope returns a scope to define variables with ScopedLet, shared by the queries that follow
+// until the scope is closed. Closing the scope unsets its variables, or restores the value a
+// variable had when it was defined in the scope:
+//
+//	scope := db.VarScope()
+//	defer scope.Close()
+//	since, err := surrealdb.ScopedLet(scope, "since", time.Now().Add(-24*time.Hour))
+func (db *DB) VarScope() *VarScope {
+	return &VarScope{db: db, previous: map[string]interface{}{}}
+}
+
+// ScopedLet defines the variable name within scope, see LetTyped.
+func ScopedLet[T any](scope *VarScope, name string, value T) (Var[T], error) {
+	name, err := varName(name)
+	if err != nil {
+		return Var[T]{}, err
+	}
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	if scope.closed {
+		return Var[T]{}, fmt.Errorf("variable %s defined in a closed scope", name)
+	}
+
+	previous, wasSet := scope.db.SessionState().Variables[name]
+	if err := scope.db.Let(name, value); err != nil {
+		return Var[T]{}, err
+	}
+	if !scope.isDefined(name) {
+		scope.defined = append(scope.defined, name)
+		if wasSet {
+			scope.previous[name] = previous
+		}
+	}
+	return Var[T]{name: name, value: value}, nil
+}
+
+// Close unsets the variables of the scope, restoring those that were defined before the scope.
+// Closing a scope more than once does nothing.
+func (s *VarScope) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var errs []error
+	for i := len(s.defined) - 1; i >= 0; i-- {
+		name := s.defined[i]
+		var err error
+		if previous, ok := s.previous[name]; ok {
+			err = s.db.Let(name, previous)
+		} else {
+			err = s.db.Unset(name)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cleaning up variable %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *VarScope) isDefined(name string) bool {
+	for _, defined := range s.defined {
+		if defined == name {
+			return true
+		}
+	}
+	return false
+}
+
+// varName validates the name of a variable, which may be given with its leading $.
+func varName(name string) (string, error) {
+	name = strings.TrimPrefix(name, "$")
+	if !isPlaceholderName(name) {
+		return "", fmt.Errorf("invalid variable name %q", name)
+	}
+	if builtinParams[name] || name == constants.AuthTokenKey {
+		return "", fmt.Errorf("variable name %q is reserved", name)
+	}
+	return name, nil
+}
@@ -0,0 +1,51 @@
+package surrealdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/internal/mock"
+)
+
+func TestVarScope(t *testing.T) {
+	server := mock.NewRPCServer(t, func(req mock.RPCRequest) (interface{}, error) {
+		return nil, nil
+	})
+
+	db, err := surrealdb.Connect(context.Background(), server.URL, surrealdb.WithNamespace("test", "test"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	limit, err := surrealdb.LetTyped(ctx, db, "$limit", 10)
+	require.NoError(t, err)
+	require.Equal(t, "$limit", limit.String())
+	require.Equal(t, 10, limit.Value())
+
+	_, err = surrealdb.LetTyped(ctx, db, "auth", "x")
+	require.Error(t, err)
+	_, err = surrealdb.LetTyped(ctx, db, "bad name", "x")
+	require.Error(t, err)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = surrealdb.LetTyped(canceled, db, "offset", 5)
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotContains(t, db.SessionState().Variables, "offset")
+
+	scope := db.VarScope()
+	_, err = surrealdb.ScopedLet(scope, "limit", 20)
+	require.NoError(t, err)
+	_, err = surrealdb.ScopedLet(scope, "since", "yesterday")
+	require.NoError(t, err)
+	require.Equal(t, 20, db.SessionState().Variables["limit"])
+
+	require.NoError(t, scope.Close())
+	require.NoError(t, scope.Close())
+	_, err = surrealdb.ScopedLet(scope, "other", 1)
+	require.Error(t, err)
+
+	require.Equal(t, map[string]interface{}{"limit": 10}, db.SessionState().Variables)
+}
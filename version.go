@@ -0,0 +1,138 @@
+package surrealdb
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// SDKVersion is the version of the SDK, such as v0.4.0, as resolved by the go command from the
+// release tags of the module. It is "(devel)" when the SDK is built from its own repository, unless
+// set at link time with -ldflags "-X github.com/surrealdb/surrealdb.go.SDKVersion=v0.4.0".
+var SDKVersion string
+
+const modulePath = "github.com/surrealdb/surrealdb.go"
+
+func init() {
+	if SDKVersion == "" {
+		SDKVersion = moduleVersion()
+	}
+}
+
+// moduleVersion returns the version of the SDK recorded in the build information of the binary.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+	return "(devel)"
+}
+
+// Deprecation is a deprecated API of the SDK, see Deprecations.
+type Deprecation struct {
+	// API is the qualified name of the function, method or type, such as compat.DB.Signin.
+	API string
+	// Replacement is the API to use instead, empty when there is none.
+	Replacement string
+}
+
+var deprecations = []Deprecation{
+	{API: "compat.New", Replacement: "surrealdb.Connect"},
+	{API: "compat.DB.Close", Replacement: "surrealdb.DB.Close"},
+	{API: "compat.DB.Use", Replacement: "surrealdb.DB.Use"},
+	{API: "compat.DB.Signin", Replacement: "surrealdb.DB.SignIn"},
+	{API: "compat.DB.Signup", Replacement: "surrealdb.DB.SignUp"},
+	{API: "compat.DB.Invalidate", Replacement: "surrealdb.DB.Invalidate"},
+	{API: "compat.DB.Authenticate", Replacement: "surrealdb.DB.Authenticate"},
+	{API: "compat.DB.Let", Replacement: "surrealdb.DB.Let"},
+	{API: "compat.DB.Info", Replacement: "surrealdb.DB.Info"},
+	{API: "compat.DB.Query", Replacement: "surrealdb.Query"},
+	{API: "compat.DB.Select", Replacement: "surrealdb.Select"},
+	{API: "compat.DB.Create", Replacement: "surrealdb.Create"},
+	{API: "compat.DB.Update", Replacement: "surrealdb.Update"},
+	{API: "compat.DB.Change", Replacement: "surrealdb.Merge"},
+	{API: "compat.DB.Modify", Replacement: "surrealdb.Patch"},
+	{API: "compat.DB.Delete", Replacement: "surrealdb.Delete"},
+	{API: "compat.Unmarshal"},
+	{API: "compat.SmartUnmarshal"},
+}
+
+// Deprecations returns the deprecated APIs of the SDK and their replacements, so that tools can
+// point at the APIs to migrate from.
+func Deprecations() []Deprecation {
+	list := make([]Deprecation, len(deprecations))
+	copy(list, deprecations)
+	return list
+}
+
+// CompareVersions compares two versions of the form major.minor.patch, with an optional v
+// prefix, and returns -1, 0 or 1 when a is older than, the same as or newer than b. Pre-release
+// and build suffixes, such as those of pseudo-versions, are ignored.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range va {
+		switch {
+		case va[i] < vb[i]:
+			return -1, nil
+		case va[i] > vb[i]:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// WarnDeprecated logs a warning through the logger of the connection the first time the
+// deprecated api, named as in Deprecations, is used with it. It is called by the deprecated
+// APIs of the SDK.
+func (db *DB) WarnDeprecated(api string) {
+	if _, warned := db.deprecationWarnings.LoadOrStore(api, true); warned {
+		return
+	}
+
+	args := []interface{}{"api", api, "sdk_version", SDKVersion}
+	for _, deprecation := range deprecations {
+		if deprecation.API == api && deprecation.Replacement != "" {
+			args = append(args, "replacement", deprecation.Replacement)
+			break
+		}
+	}
+	db.logger.Warn("use of a deprecated API", args...)
+}
+
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+	release, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), "-")
+	release, _, _ = strings.Cut(release, "+")
+	parts := strings.Split(release, ".")
+	if len(parts) != len(parsed) {
+		return parsed, fmt.Errorf("invalid version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("invalid version %q", version)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
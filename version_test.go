@@ -0,0 +1,43 @@
+package surrealdb_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/surrealdb/surrealdb.go"
+
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go/pkg/logger"
+)
+
+func TestDeprecations(t *testing.T) {
+	cmp, err := surrealdb.CompareVersions("v0.3.10", "0.3.2")
+	require.NoError(t, err)
+	require.Equal(t, 1, cmp)
+	cmp, err = surrealdb.CompareVersions("v0.4.1-0.20240101000000-abcdef123456", "v0.4.1")
+	require.NoError(t, err)
+	require.Equal(t, 0, cmp)
+	_, err = surrealdb.CompareVersions("0.3", "0.3.2")
+	require.Error(t, err)
+
+	// the tests run within the module, which has no released version
+	require.Equal(t, "(devel)", surrealdb.SDKVersion)
+	require.Contains(t, surrealdb.Deprecations(), surrealdb.Deprecation{API: "compat.DB.Signin", Replacement: "surrealdb.DB.SignIn"})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var logs strings.Builder
+	db, err := surrealdb.Connect(context.Background(), server.URL,
+		surrealdb.WithLogger(logger.New(slog.NewTextHandler(&logs, nil))))
+	require.NoError(t, err)
+
+	db.WarnDeprecated("compat.DB.Signin")
+	db.WarnDeprecated("compat.DB.Signin")
+	require.Equal(t, 1, strings.Count(logs.String(), "use of a deprecated API"))
+	require.Contains(t, logs.String(), "replacement=surrealdb.DB.SignIn")
+}